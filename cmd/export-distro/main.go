@@ -21,6 +21,7 @@ import (
 	"github.com/edgexfoundry/edgex-go"
 	"github.com/edgexfoundry/edgex-go/internal"
 	"github.com/edgexfoundry/edgex-go/internal/export/distro"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/startup"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/usage"
 	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
@@ -58,8 +59,13 @@ func main() {
 
 	listenForInterrupt(errs)
 
-	// There can be another receivers that can be initialized here
-	distro.ZeroMQReceiver(eventCh)
+	// The message queue type selects the receiver
+	switch distro.Configuration.MessageQueue.Type {
+	case config.MQTT:
+		distro.MQTTReceiver(eventCh)
+	default:
+		distro.ZeroMQReceiver(eventCh)
+	}
 	distro.Loop(errs, eventCh)
 
 	// Time it took to start service
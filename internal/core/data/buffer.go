@@ -0,0 +1,312 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// Time between replays of the buffered events when none is configured
+const defaultReplayInterval = 5000
+
+var bufferBucket = []byte("events")
+
+// Events accepted while the database was unavailable, kept on disk in the order received
+// until they can be added to the database
+type eventBuffer struct {
+	db        *bbolt.DB
+	max       int
+	mutex     sync.Mutex
+	depth     int
+	buffered  uint64
+	replayed  uint64
+	dropped   uint64
+	lastError string
+}
+
+// State of the event buffer
+type bufferStats struct {
+	Enabled   bool   `json:"enabled"`
+	Depth     int    `json:"depth"`
+	Capacity  int    `json:"capacity"`
+	Buffered  uint64 `json:"buffered"`
+	Replayed  uint64 `json:"replayed"`
+	Dropped   uint64 `json:"dropped"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+var buffer *eventBuffer    // nil when buffering is disabled
+var chReplay chan struct{} // Closed to stop replaying the buffered events
+
+// Open the buffer file, keeping the events buffered before a restart
+func openEventBuffer(path string, max int) (*eventBuffer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0700)); err != nil {
+		return nil, err
+	}
+	b, err := bbolt.Open(path, os.FileMode(0600), &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	depth := 0
+	err = b.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(bufferBucket)
+		if err != nil {
+			return err
+		}
+		depth = bucket.Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		b.Close()
+		return nil, err
+	}
+	return &eventBuffer{db: b, max: max, depth: depth}, nil
+}
+
+func (b *eventBuffer) close() error {
+	return b.db.Close()
+}
+
+// Number of events waiting to be added to the database
+func (b *eventBuffer) size() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.depth
+}
+
+func (b *eventBuffer) stats() bufferStats {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return bufferStats{
+		Enabled:   true,
+		Depth:     b.depth,
+		Capacity:  b.max,
+		Buffered:  b.buffered,
+		Replayed:  b.replayed,
+		Dropped:   b.dropped,
+		LastError: b.lastError,
+	}
+}
+
+// Append the event to the buffer, giving it the id and creation time the database would have
+func (b *eventBuffer) add(e contract.Event) (string, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.depth >= b.max {
+		b.dropped++
+		return "", errors.NewErrEventBufferFull(b.max)
+	}
+
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.Created == 0 {
+		e.Created = db.MakeTimestamp()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	err = b.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(bufferBucket)
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		return bucket.Put(key, data)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	b.depth++
+	b.buffered++
+	return e.ID, nil
+}
+
+// Add the buffered events to the database, oldest first, until the buffer is empty or the database
+// is unavailable again. Events the database rejects are dropped. Return the number of events added.
+func (b *eventBuffer) replay() (int, error) {
+	count := 0
+	for {
+		var key []byte
+		var e contract.Event
+		err := b.db.View(func(tx *bbolt.Tx) error {
+			k, v := tx.Bucket(bufferBucket).Cursor().First()
+			if k == nil {
+				return nil
+			}
+			key = append([]byte{}, k...)
+			return json.Unmarshal(v, &e)
+		})
+		if err != nil {
+			return count, err
+		}
+		if key == nil {
+			return count, nil
+		}
+
+		added := true
+		if _, err = dbClient.AddEvent(e); err != nil {
+			if isDatabaseUnavailable(err) {
+				b.mutex.Lock()
+				b.lastError = err.Error()
+				b.mutex.Unlock()
+				return count, err
+			}
+			LoggingClient.Error(fmt.Sprintf("Dropping buffered event %s rejected by the database: %s", e.ID, err.Error()))
+			added = false
+		}
+
+		err = b.db.Update(func(tx *bbolt.Tx) error {
+			return tx.Bucket(bufferBucket).Delete(key)
+		})
+		if err != nil {
+			return count, err
+		}
+
+		b.mutex.Lock()
+		b.depth--
+		if added {
+			b.replayed++
+			count++
+		} else {
+			b.dropped++
+		}
+		b.mutex.Unlock()
+	}
+}
+
+// Messages of the mgo errors raised when no server can be reached or the connection to it was lost
+var unavailableMessages = []string{
+	"no reachable servers",
+	"server not available",
+	"server was closed",
+	"Closed explicitly",
+	"per-server connection limit reached",
+}
+
+// Tell whether the error comes from the database being unavailable rather than from the event itself.
+// Only network, connection and lock timeout errors are outages; any other error is about the event.
+func isDatabaseUnavailable(err error) bool {
+	switch err {
+	case io.EOF, io.ErrUnexpectedEOF, bbolt.ErrTimeout, bbolt.ErrDatabaseNotOpen:
+		return true
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	for _, message := range unavailableMessages {
+		if err.Error() == message {
+			return true
+		}
+	}
+	return false
+}
+
+// Add the event to the database. While the database is unavailable, and until the events buffered
+// meanwhile have been replayed, the event is buffered instead when buffering is enabled.
+func storeEvent(e contract.Event) (string, error) {
+	if buffer == nil {
+		return dbClient.AddEvent(e)
+	}
+
+	if buffer.size() == 0 {
+		id, err := dbClient.AddEvent(e)
+		if err == nil || !isDatabaseUnavailable(err) {
+			return id, err
+		}
+		LoggingClient.Warn(fmt.Sprintf("Database unavailable, buffering events: %s", err.Error()))
+	}
+	return buffer.add(e)
+}
+
+// Open the buffer when enabled and start replaying its events in the background
+func startBuffer() error {
+	config := Configuration.Buffer
+	if !config.Enabled {
+		return nil
+	}
+
+	var err error
+	buffer, err = openEventBuffer(config.Path, config.MaxEvents)
+	if err != nil {
+		return err
+	}
+	if depth := buffer.size(); depth > 0 {
+		LoggingClient.Info(fmt.Sprintf("%d buffered events to replay", depth))
+	}
+
+	interval := config.ReplayInterval
+	if interval <= 0 {
+		interval = defaultReplayInterval
+	}
+	chReplay = make(chan struct{})
+	go func(b *eventBuffer, done chan struct{}) {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Duration(interval) * time.Millisecond):
+				if b.size() == 0 {
+					continue
+				}
+				count, err := b.replay()
+				if count > 0 {
+					LoggingClient.Info(fmt.Sprintf("Replayed %d buffered events, %d remaining", count, b.size()))
+				}
+				if err != nil {
+					LoggingClient.Warn(fmt.Sprintf("Replay of the buffered events stopped: %s", err.Error()))
+				}
+			}
+		}
+	}(buffer, chReplay)
+	return nil
+}
+
+func stopBuffer() {
+	if chReplay != nil {
+		close(chReplay)
+		chReplay = nil
+	}
+	if buffer != nil {
+		buffer.close()
+		buffer = nil
+	}
+}
+
+func getBufferStats() bufferStats {
+	if buffer == nil {
+		return bufferStats{}
+	}
+	return buffer.stats()
+}
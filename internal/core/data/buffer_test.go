@@ -0,0 +1,263 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/interfaces/mocks"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/stretchr/testify/mock"
+	"go.etcd.io/bbolt"
+)
+
+// Open a buffer in a temporary directory, returning a function removing it
+func newTestBuffer(t *testing.T, max int) (*eventBuffer, func()) {
+	dir, err := ioutil.TempDir("", "buffer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := openEventBuffer(filepath.Join(dir, "data", "buffer.db"), max)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return b, func() {
+		b.close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestEventBufferReplay(t *testing.T) {
+	reset()
+	b, cleanup := newTestBuffer(t, 10)
+	defer cleanup()
+
+	var ids []string
+	for _, device := range []string{"first", "second", "third"} {
+		id, err := b.add(models.Event{Device: device})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	var replayed []models.Event
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Run(func(args mock.Arguments) {
+		replayed = append(replayed, args.Get(0).(models.Event))
+	}).Return("", nil).Once()
+	myMock.On("AddEvent", mock.Anything).Return("", fmt.Errorf("no reachable servers")).Once()
+	dbClient = myMock
+
+	// The replay stops while the database is unavailable
+	count, err := b.replay()
+	if count != 1 || err == nil {
+		t.Fatalf("expected 1 event replayed and an error, got %d %v", count, err)
+	}
+	if b.size() != 2 {
+		t.Errorf("expected 2 events remaining, got %d", b.size())
+	}
+
+	// Events the database rejects are dropped
+	myMock.On("AddEvent", mock.Anything).Return("", db.ErrNotUnique).Once()
+	myMock.On("AddEvent", mock.Anything).Run(func(args mock.Arguments) {
+		replayed = append(replayed, args.Get(0).(models.Event))
+	}).Return("", nil)
+	count, err = b.replay()
+	if count != 1 || err != nil {
+		t.Fatalf("expected 1 event replayed, got %d %v", count, err)
+	}
+
+	if len(replayed) != 2 || replayed[0].Device != "first" || replayed[1].Device != "third" {
+		t.Fatalf("unexpected events replayed %v", replayed)
+	}
+	if replayed[0].ID != ids[0] || replayed[0].Created == 0 {
+		t.Errorf("expected the buffered id and creation time, got %s %d", replayed[0].ID, replayed[0].Created)
+	}
+	stats := b.stats()
+	if stats.Depth != 0 || stats.Buffered != 3 || stats.Replayed != 2 || stats.Dropped != 1 || stats.LastError == "" {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestIsDatabaseUnavailable(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		unavailable bool
+	}{
+		{"No reachable servers", fmt.Errorf("no reachable servers"), true},
+		{"Connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}, true},
+		{"Connection lost", io.EOF, true},
+		{"Bolt timeout", bbolt.ErrTimeout, true},
+		{"Duplicate key", fmt.Errorf("E11000 duplicate key error collection: coredata.event index: _id_"), false},
+		{"Document too large", fmt.Errorf("Document is too large"), false},
+		{"Not unique", db.ErrNotUnique, false},
+		{"Invalid id", db.ErrInvalidObjectId, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if isDatabaseUnavailable(tt.err) != tt.unavailable {
+				t.Errorf("expected unavailable to be %v for %v", tt.unavailable, tt.err)
+			}
+		})
+	}
+}
+
+func TestEventBufferReplayDropsRejected(t *testing.T) {
+	reset()
+	b, cleanup := newTestBuffer(t, 10)
+	defer cleanup()
+	b.add(models.Event{Device: "poison"})
+	b.add(models.Event{Device: "next"})
+
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return("", fmt.Errorf("E11000 duplicate key error")).Once()
+	myMock.On("AddEvent", mock.Anything).Return("", nil).Once()
+	dbClient = myMock
+
+	// An event the database keeps rejecting does not hold back the events buffered after it
+	count, err := b.replay()
+	if count != 1 || err != nil {
+		t.Fatalf("expected 1 event replayed, got %d %v", count, err)
+	}
+	if stats := b.stats(); stats.Depth != 0 || stats.Dropped != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestEventBufferFull(t *testing.T) {
+	b, cleanup := newTestBuffer(t, 1)
+	defer cleanup()
+
+	if _, err := b.add(models.Event{Device: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := b.add(models.Event{Device: "second"})
+	if _, ok := err.(*errors.ErrEventBufferFull); !ok {
+		t.Errorf("expected the buffer to be full, got %v", err)
+	}
+	if eventErrorStatus(err) != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, eventErrorStatus(err))
+	}
+}
+
+func TestEventBufferReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buffer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "buffer.db")
+
+	b, err := openEventBuffer(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.add(models.Event{Device: "first"})
+	b.add(models.Event{Device: "second"})
+	b.close()
+
+	b, err = openEventBuffer(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.close()
+	if b.size() != 2 {
+		t.Errorf("expected 2 buffered events after reopening, got %d", b.size())
+	}
+}
+
+func TestStoreEventBuffering(t *testing.T) {
+	reset()
+	b, cleanup := newTestBuffer(t, 10)
+	defer cleanup()
+	buffer = b
+	defer func() { buffer = nil }()
+
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return("", db.ErrInvalidObjectId).Once()
+	myMock.On("AddEvent", mock.Anything).Return("", fmt.Errorf("no reachable servers")).Once()
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+
+	// Errors about the event itself are not buffered
+	if _, err := persistAndPublishEvent(models.Event{Device: testDeviceName}, clients.ContentJson); err != db.ErrInvalidObjectId {
+		t.Fatalf("expected the database error, got %v", err)
+	}
+
+	// The event is buffered and published while the database is unavailable
+	e, err := persistAndPublishEvent(models.Event{Device: testDeviceName}, clients.ContentJson)
+	if err != nil || e.ID == "" {
+		t.Fatalf("expected the event to be buffered, got %s %v", e.ID, err)
+	}
+	// Later events are buffered until the buffer has been replayed
+	if _, err = storeEvent(models.Event{Device: testDeviceName}); err != nil {
+		t.Fatal(err)
+	}
+	myMock.AssertNumberOfCalls(t, "AddEvent", 2)
+	if b.size() != 2 {
+		t.Errorf("expected 2 buffered events, got %d", b.size())
+	}
+}
+
+func TestBufferHandler(t *testing.T) {
+	reset()
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{"Disabled", false},
+		{"Enabled", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.enabled {
+				b, cleanup := newTestBuffer(t, 10)
+				defer cleanup()
+				buffer = b
+				defer func() { buffer = nil }()
+				b.add(models.Event{Device: testDeviceName})
+			}
+
+			req := httptest.NewRequest(http.MethodGet, clients.ApiBufferRoute, nil)
+			rr := httptest.NewRecorder()
+			testRoutes.ServeHTTP(rr, req)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+			var stats bufferStats
+			if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+				t.Fatal(err)
+			}
+			if stats.Enabled != tt.enabled || (tt.enabled && stats.Depth != 1) {
+				t.Errorf("unexpected stats %+v", stats)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import "github.com/edgexfoundry/edgex-go/internal/pkg/config"
 type ConfigurationStruct struct {
 	Writable                   WritableInfo
 	MessageQueue               config.MessageQueueInfo
+	Buffer                     BufferInfo
 	Clients                    map[string]config.ClientInfo
 	Databases                  map[string]config.DatabaseInfo
 	Logging                    config.LoggingInfo
@@ -31,4 +32,107 @@ type WritableInfo struct {
 	PersistData                bool
 	ServiceUpdateLastConnected bool
 	ValidateCheck              bool
-}
\ No newline at end of file
+	TransformReadings          bool
+	// LastReportedInterval is the time, in milliseconds, over which the last reported updates of a device
+	// are coalesced into one call to metadata. 0 makes one call per event.
+	LastReportedInterval int
+	DeviceCache          DeviceCacheInfo
+	Deduplication        DeduplicationInfo
+	Retention            RetentionInfo
+	Downsampling         DownsamplingInfo
+	RateLimit            RateLimitInfo
+}
+
+// DeduplicationInfo configures the detection of events submitted more than once.
+type DeduplicationInfo struct {
+	// Window is the time, in milliseconds, a submitted event is remembered, 0 disables de-duplication
+	Window int
+	// Size is the maximum number of submitted events remembered
+	Size int
+	// Fingerprint identifies the events submitted without an idempotency key by their device, origin and readings
+	Fingerprint bool
+}
+
+// DeviceCacheInfo configures the cache of the devices fetched from metadata.
+type DeviceCacheInfo struct {
+	// Size is the maximum number of devices cached, 0 disables the cache
+	Size int
+	// TTL is the time, in milliseconds, a cached device is used before being fetched again
+	TTL int
+}
+
+// RetentionInfo configures the background removal of old events and readings.
+type RetentionInfo struct {
+	// Enabled turns the periodic retention pass on
+	Enabled bool
+	// Interval is the time, in milliseconds, between two retention passes
+	Interval int
+	// Default applies to every event, whatever its device
+	Default RetentionPolicy
+	// Devices holds additional policies for the events of a device, keyed by device name
+	Devices map[string]RetentionPolicy
+	// ValueDescriptors holds policies for the readings of a value descriptor, keyed by value descriptor name
+	ValueDescriptors map[string]RetentionPolicy
+}
+
+// RetentionPolicy holds the rules used to remove events or readings. A zero value disables a rule.
+type RetentionPolicy struct {
+	// MaxAge removes what was created more than MaxAge milliseconds ago
+	MaxAge int64
+	// MaxCount keeps only the MaxCount most recently created
+	MaxCount int
+	// PurgePushed removes what has been pushed to export
+	PurgePushed bool
+}
+
+// DownsamplingInfo configures the rollup of aged readings into summary readings.
+type DownsamplingInfo struct {
+	// Enabled turns the periodic downsampling pass on
+	Enabled bool
+	// Interval is the time, in milliseconds, between two downsampling passes
+	Interval int
+	// Rules holds how the readings of a value descriptor are rolled up, keyed by value descriptor name
+	Rules map[string]DownsamplingRule
+}
+
+// DownsamplingRule replaces the readings older than MaxAge by one summary reading per device and period.
+type DownsamplingRule struct {
+	// MaxAge is the age, in milliseconds, beyond which readings are rolled up
+	MaxAge int64
+	// Period is the length, in milliseconds, of the periods summarized by each summary reading
+	Period int64
+}
+
+// RateLimitInfo configures the limits on the events a device, or the devices of a device service, may add.
+type RateLimitInfo struct {
+	// Enabled turns the limits on
+	Enabled bool
+	// Default applies to each device without a limit of its own
+	Default RateLimit
+	// Devices holds the limits of a device, in place of the default, keyed by device name
+	Devices map[string]RateLimit
+	// DeviceServices holds the limits shared by all the devices of a device service, keyed by device service name
+	DeviceServices map[string]RateLimit
+}
+
+// RateLimit holds the limits on the events added. A zero value disables a limit.
+type RateLimit struct {
+	// EventsPerSecond is the sustained rate at which events are accepted
+	EventsPerSecond float64
+	// Burst is the number of events accepted at once above the sustained rate, EventsPerSecond rounded up when 0
+	Burst int
+	// MaxReadingsPerEvent is the number of readings an event may hold
+	MaxReadingsPerEvent int
+}
+
+// BufferInfo configures the on-disk buffer of the events accepted while the database is unavailable.
+type BufferInfo struct {
+	// Enabled turns buffering on
+	Enabled bool
+	// Path is the file holding the buffered events
+	Path string
+	// MaxEvents is the maximum number of events buffered, beyond which events are rejected
+	MaxEvents int
+	// ReplayInterval is the time, in milliseconds, between two attempts to add the buffered events to the database
+	ReplayInterval int
+}
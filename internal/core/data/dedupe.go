@@ -0,0 +1,160 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Events recently submitted, keyed by their idempotency key or fingerprint.
+// The oldest submissions are forgotten first once the window has passed or the size is reached.
+type eventDeduplicator struct {
+	mutex       sync.Mutex
+	submissions map[string]*submission
+	order       *list.List // Every submission, in the order they began
+	completed   *list.List // The completed submissions, in the order they expire
+}
+
+// Submission of an event, done once the event has been added or has failed
+type submission struct {
+	key       string
+	id        string
+	err       error
+	done      chan struct{}
+	expires   time.Time
+	began     *list.Element
+	completed *list.Element
+}
+
+var submissions = newEventDeduplicator()
+
+func newEventDeduplicator() *eventDeduplicator {
+	return &eventDeduplicator{submissions: make(map[string]*submission), order: list.New(), completed: list.New()}
+}
+
+// Get the submission of the key, starting a new one unless an earlier one is still remembered
+func (d *eventDeduplicator) begin(key string, size int) (*submission, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.expire(time.Now())
+	if s, ok := d.submissions[key]; ok {
+		return s, true
+	}
+
+	s := &submission{key: key, done: make(chan struct{})}
+	s.began = d.order.PushBack(s)
+	d.submissions[key] = s
+	for d.order.Len() > size {
+		d.remove(d.order.Front().Value.(*submission))
+	}
+	return s, false
+}
+
+// Complete the submission, remembering it for the window when the event was added
+func (d *eventDeduplicator) finish(s *submission, id string, err error, window time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	s.id = id
+	s.err = err
+	s.expires = time.Now().Add(window)
+	close(s.done)
+
+	if d.submissions[s.key] != s {
+		// Already forgotten beyond the size
+		return
+	}
+	// A failed submission is forgotten so that the event can be submitted again
+	if err != nil {
+		d.remove(s)
+		return
+	}
+	s.completed = d.completed.PushBack(s)
+}
+
+// Forget the completed submissions whose window has passed, stopping at the first one still remembered
+func (d *eventDeduplicator) expire(now time.Time) {
+	for elem := d.completed.Front(); elem != nil; elem = d.completed.Front() {
+		s := elem.Value.(*submission)
+		if !now.After(s.expires) {
+			return
+		}
+		d.remove(s)
+	}
+}
+
+func (d *eventDeduplicator) remove(s *submission) {
+	d.order.Remove(s.began)
+	if s.completed != nil {
+		d.completed.Remove(s.completed)
+	}
+	delete(d.submissions, s.key)
+}
+
+func (d *eventDeduplicator) clear() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.submissions = make(map[string]*submission)
+	d.order.Init()
+	d.completed.Init()
+}
+
+// Get the key identifying the submission of the event: the idempotency key supplied by the client
+// or, when fingerprints are enabled, a hash of the device, origin and readings of the event, binary values included.
+// An empty key means the event is not de-duplicated.
+func submissionKey(e contract.Event, idempotencyKey string) string {
+	if idempotencyKey != "" {
+		return "key:" + e.Device + "\x00" + idempotencyKey
+	}
+	// Events without an origin cannot be told apart from new events with the same readings
+	if !Configuration.Writable.Deduplication.Fingerprint || e.Origin == 0 {
+		return ""
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00", e.Device, e.Origin)
+	for _, r := range e.Readings {
+		fmt.Fprintf(h, "%s\x00%s\x00%d\x00%d\x00", r.Name, r.Value, r.Origin, len(r.BinaryValue))
+		h.Write(r.BinaryValue)
+	}
+	return "fingerprint:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Add the event once for all the submissions with the same key within the de-duplication window.
+// Duplicates get the id of the original event, waiting for it to be added if needed, and are not published.
+func deduplicate(key string, add func() (string, error)) (id string, duplicate bool, err error) {
+	info := Configuration.Writable.Deduplication
+	if key == "" || info.Window <= 0 || info.Size <= 0 {
+		id, err = add()
+		return id, false, err
+	}
+
+	s, found := submissions.begin(key, info.Size)
+	if found {
+		<-s.done
+		return s.id, true, s.err
+	}
+
+	defer func() {
+		submissions.finish(s, id, err, time.Duration(info.Window)*time.Millisecond)
+	}()
+	id, err = add()
+	return id, false, err
+}
@@ -0,0 +1,219 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/interfaces/mocks"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// Reset the de-duplication state and drain the last reported updates of the events added
+func resetDeduplication() {
+	submissions.clear()
+	for {
+		select {
+		case <-chEvents:
+		default:
+			return
+		}
+	}
+}
+
+func TestSubmissionKey(t *testing.T) {
+	reset()
+	e := models.Event{Device: testDeviceName, Origin: testOrigin, Readings: buildReadings()}
+	other := models.Event{Device: testDeviceName, Origin: testOrigin + 1, Readings: buildReadings()}
+
+	if key := submissionKey(e, ""); key != "" {
+		t.Errorf("expected no key without fingerprints, got %s", key)
+	}
+	if submissionKey(e, "abc") == submissionKey(models.Event{Device: "Other"}, "abc") {
+		t.Errorf("expected idempotency keys to be scoped by device")
+	}
+
+	Configuration.Writable.Deduplication.Fingerprint = true
+	if submissionKey(e, "") == "" || submissionKey(e, "") != submissionKey(e, "") {
+		t.Errorf("expected a stable fingerprint")
+	}
+	if submissionKey(e, "") == submissionKey(other, "") {
+		t.Errorf("expected different fingerprints for different origins")
+	}
+	if key := submissionKey(models.Event{Device: testDeviceName}, ""); key != "" {
+		t.Errorf("expected no fingerprint without an origin, got %s", key)
+	}
+
+	image := func(data ...byte) models.Event {
+		return models.Event{Device: testDeviceName, Origin: testOrigin,
+			Readings: []models.Reading{{Name: "Image", Origin: testOrigin, BinaryValue: data}}}
+	}
+	if submissionKey(image(1, 2), "") == submissionKey(image(3, 4), "") {
+		t.Errorf("expected different fingerprints for different binary values")
+	}
+}
+
+func TestAddNewEventDuplicate(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return(testBsonString, nil).Once()
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+	Configuration.Writable.Deduplication = DeduplicationInfo{Window: 60000, Size: 10}
+	evt := models.Event{Device: testDeviceName, Origin: testOrigin, Readings: buildReadings()}
+
+	for i := 0; i < 3; i++ {
+		id, err := addNewEvent(evt, "retry", clients.ContentJson)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err.Error())
+		}
+		if id != testBsonString {
+			t.Errorf("expected the original id %s, got %s", testBsonString, id)
+		}
+	}
+	myMock.AssertNumberOfCalls(t, "AddEvent", 1)
+	if len(chEvents) != 2 {
+		t.Errorf("expected the last reported updates of the original event only, got %d", len(chEvents))
+	}
+}
+
+func TestAddNewEventDuplicateConcurrent(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).After(50*time.Millisecond).Return(testBsonString, nil).Once()
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+	Configuration.Writable.Deduplication = DeduplicationInfo{Window: 60000, Size: 10}
+	evt := models.Event{Device: testDeviceName, Origin: testOrigin, Readings: buildReadings()}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if id, err := addNewEvent(evt, "concurrent", clients.ContentJson); err != nil || id != testBsonString {
+				t.Errorf("unexpected result %s %v", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+	myMock.AssertNumberOfCalls(t, "AddEvent", 1)
+}
+
+func TestAddNewEventDuplicateAfterFailure(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return("", fmt.Errorf("some error")).Once()
+	myMock.On("AddEvent", mock.Anything).Return(testBsonString, nil).Once()
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+	Configuration.Writable.Deduplication = DeduplicationInfo{Window: 60000, Size: 10, Fingerprint: true}
+	evt := models.Event{Device: testDeviceName, Origin: testOrigin, Readings: buildReadings()}
+
+	if _, err := addNewEvent(evt, "", clients.ContentJson); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if id, err := addNewEvent(evt, "", clients.ContentJson); err != nil || id != testBsonString {
+		t.Errorf("expected the event to be added again after a failure, got %s %v", id, err)
+	}
+	myMock.AssertNumberOfCalls(t, "AddEvent", 2)
+}
+
+func TestEventDeduplicatorExpiry(t *testing.T) {
+	d := newEventDeduplicator()
+	s, found := d.begin("a", 2)
+	if found {
+		t.Fatalf("expected a new submission")
+	}
+	d.finish(s, "1", nil, 0)
+	time.Sleep(time.Millisecond)
+
+	// The window has passed
+	if _, found = d.begin("a", 2); found {
+		t.Errorf("expected the submission to have expired")
+	}
+	// The oldest submission is forgotten beyond the size
+	d.begin("b", 2)
+	d.begin("c", 2)
+	if _, found = d.begin("a", 2); found {
+		t.Errorf("expected the oldest submission to be forgotten")
+	}
+	if len(d.submissions) != 2 || d.order.Len() != 2 {
+		t.Errorf("expected 2 submissions, got %d", len(d.submissions))
+	}
+}
+
+func TestEventDeduplicatorExpiryOrder(t *testing.T) {
+	d := newEventDeduplicator()
+	pending, _ := d.begin("pending", 10)
+	s, _ := d.begin("a", 10)
+	d.finish(s, "1", nil, 0)
+	s, _ = d.begin("b", 10)
+	d.finish(s, "2", nil, time.Hour)
+	time.Sleep(time.Millisecond)
+
+	// Only the expired submission is forgotten, the pending one being kept until it completes
+	d.begin("c", 10)
+	if _, ok := d.submissions["a"]; ok {
+		t.Errorf("expected the submission to have expired")
+	}
+	if _, ok := d.submissions["b"]; !ok {
+		t.Errorf("expected the submission within its window to be remembered")
+	}
+	if _, found := d.begin("pending", 10); !found {
+		t.Errorf("expected the pending submission to be remembered")
+	}
+	if d.completed.Len() != 1 || d.order.Len() != 3 {
+		t.Errorf("unexpected lists of %d completed and %d submissions", d.completed.Len(), d.order.Len())
+	}
+	d.finish(pending, "3", nil, time.Hour)
+}
+
+func TestEventHandlerIdempotencyKey(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return(testBsonString, nil).Once()
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+	Configuration.Writable.Deduplication = DeduplicationInfo{Window: 60000, Size: 10}
+	body := `{"device":"` + testDeviceName + `","origin":1471806386919,"readings":[{"name":"Temperature","value":"45"}]}`
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, clients.ApiEventRoute, strings.NewReader(body))
+		req.Header.Set(clients.IdempotencyKey, "retry")
+		rr := httptest.NewRecorder()
+		testRoutes.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK || rr.Body.String() != testBsonString {
+			t.Errorf("unexpected response %d %s", rr.Code, rr.Body.String())
+		}
+	}
+	myMock.AssertNumberOfCalls(t, "AddEvent", 1)
+}
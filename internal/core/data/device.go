@@ -15,18 +15,17 @@ package data
 
 import (
 	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
-	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
 )
 
 // Update when the device was last reported connected
-func updateDeviceLastReportedConnected(device string) {
+func updateDeviceLastReportedConnected(device string, t int64) {
 	// Config set to skip update last reported
 	if !Configuration.Writable.DeviceUpdateLastConnected {
 		LoggingClient.Debug("Skipping update of device connected/reported times for:  " + device)
 		return
 	}
 
-	d, err := mdc.CheckForDevice(device)
+	d, err := getDevice(device)
 	if err != nil {
 		LoggingClient.Error("Error getting device " + device + ": " + err.Error())
 		return
@@ -38,7 +37,6 @@ func updateDeviceLastReportedConnected(device string) {
 		return
 	}
 
-	t := db.MakeTimestamp()
 	// Found device, now update lastReported
 	err = mdc.UpdateLastConnectedByName(d.Name, t)
 	if err != nil {
@@ -52,17 +50,16 @@ func updateDeviceLastReportedConnected(device string) {
 	return
 }
 
-// Update when the device service was last reported connected
-func updateDeviceServiceLastReportedConnected(device string) {
+// Update when the device service was last reported connected.
+// Updated holds the device services already updated, which are skipped.
+func updateDeviceServiceLastReportedConnected(device string, t int64, updated map[string]bool) {
 	if !Configuration.Writable.ServiceUpdateLastConnected {
 		LoggingClient.Debug("Skipping update of device service connected/reported times for:  " + device)
 		return
 	}
 
-	t := db.MakeTimestamp()
-
 	// Get the device
-	d, err := mdc.CheckForDevice(device)
+	d, err := getDevice(device)
 	if err != nil {
 		LoggingClient.Error("Error getting device " + device + ": " + err.Error())
 		return
@@ -81,6 +78,11 @@ func updateDeviceServiceLastReportedConnected(device string) {
 		return
 	}
 
+	if updated[s.Service.Id] {
+		return
+	}
+	updated[s.Service.Id] = true
+
 	msc.UpdateLastConnected(s.Service.Id, t)
 	msc.UpdateLastReported(s.Service.Id, t)
 }
@@ -96,7 +98,7 @@ func checkMaxLimit(limit int) error {
 
 func checkDevice(device string) error {
 	if Configuration.Writable.MetaDataCheck {
-		_, err := mdc.CheckForDevice(device)
+		_, err := getDevice(device)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,157 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Devices fetched from metadata, keyed by the id or name they were requested with.
+// The least recently used device is evicted first once the cache is full.
+type deviceCache struct {
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cachedDevice struct {
+	token   string
+	device  contract.Device
+	fetched time.Time
+}
+
+var devices = newDeviceCache()
+
+func newDeviceCache() *deviceCache {
+	return &deviceCache{entries: make(map[string]*list.Element), lru: list.New()}
+}
+
+// Get the device with the id or name from the cache, telling whether it was found and is still fresh
+func (c *deviceCache) get(token string, ttl time.Duration) (contract.Device, bool, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, ok := c.entries[token]
+	if !ok {
+		return contract.Device{}, false, false
+	}
+	c.lru.MoveToFront(elem)
+	entry := elem.Value.(*cachedDevice)
+	return entry.device, time.Since(entry.fetched) < ttl, true
+}
+
+// Add or refresh the device, evicting the least recently used devices beyond the size
+func (c *deviceCache) put(token string, d contract.Device, size int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if elem, ok := c.entries[token]; ok {
+		elem.Value = &cachedDevice{token: token, device: d, fetched: time.Now()}
+		c.lru.MoveToFront(elem)
+	} else {
+		c.entries[token] = c.lru.PushFront(&cachedDevice{token: token, device: d, fetched: time.Now()})
+	}
+	for c.lru.Len() > size {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// Remove the devices affected by the change metadata reported in the callback alert
+func (c *deviceCache) invalidate(alert contract.CallbackAlert) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	removed := 0
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if alertMatches(alert, elem.Value.(*cachedDevice).device) {
+			c.removeElement(elem)
+			removed++
+		}
+		elem = next
+	}
+	return removed
+}
+
+// Remove the device with the id or name
+func (c *deviceCache) remove(token string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if elem, ok := c.entries[token]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// Remove every device
+func (c *deviceCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+func (c *deviceCache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cachedDevice).token)
+}
+
+// Get the device with the id or name, from the cache when enabled and the device was fetched recently.
+// When metadata cannot be reached, the device last fetched is used rather than failing.
+func getDevice(token string) (contract.Device, error) {
+	info := Configuration.Writable.DeviceCache
+	if info.Size <= 0 {
+		return mdc.CheckForDevice(token)
+	}
+
+	cached, fresh, found := devices.get(token, time.Duration(info.TTL)*time.Millisecond)
+	if fresh {
+		return cached, nil
+	}
+
+	d, err := mdc.CheckForDevice(token)
+	if err != nil {
+		// Metadata replied, the device is gone or invalid
+		_, replied := err.(*types.ErrServiceClient)
+		if replied && found {
+			devices.remove(token)
+		}
+		if replied || !found {
+			return d, err
+		}
+		LoggingClient.Warn("Using the cached device " + token + ", metadata could not be reached: " + err.Error())
+		return cached, nil
+	}
+	if len(d.Name) > 0 {
+		devices.put(token, d, info.Size)
+	}
+	return d, nil
+}
+
+// Whether the change reported in the callback alert affects the device: a change to the device itself,
+// or to its profile, device service or addressable
+func alertMatches(alert contract.CallbackAlert, d contract.Device) bool {
+	switch alert.ActionType {
+	case contract.DEVICE:
+		return d.Id.Hex() == alert.Id || d.Name == alert.Id
+	case contract.PROFILE:
+		return d.Profile.Id.Hex() == alert.Id
+	case contract.SERVICE:
+		return d.Service.Service.Id == alert.Id
+	case contract.ADDRESSABLE:
+		return d.Addressable.Id == alert.Id || d.Service.Service.Addressable.Id == alert.Id
+	}
+	return false
+}
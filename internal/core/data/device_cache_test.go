@@ -0,0 +1,178 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/clients/metadata/mocks"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestDeviceCacheEviction(t *testing.T) {
+	c := newDeviceCache()
+	c.put("a", models.Device{Name: "a"}, 2)
+	c.put("b", models.Device{Name: "b"}, 2)
+	// Using a makes b the least recently used
+	if _, fresh, found := c.get("a", time.Minute); !fresh || !found {
+		t.Fatalf("expected a to be cached")
+	}
+	c.put("c", models.Device{Name: "c"}, 2)
+
+	if _, _, found := c.get("b", time.Minute); found {
+		t.Errorf("expected b to be evicted")
+	}
+	if _, fresh, found := c.get("c", 0); fresh || !found {
+		t.Errorf("expected c to be cached and stale, got fresh %v found %v", fresh, found)
+	}
+}
+
+func TestDeviceCacheInvalidate(t *testing.T) {
+	c := newDeviceCache()
+	d1 := models.Device{Id: bson.NewObjectId(), Name: "d1"}
+	d1.Profile.Id = bson.NewObjectId()
+	d1.Service.Service.Id = "service"
+	d2 := models.Device{Id: bson.NewObjectId(), Name: "d2"}
+	d2.Profile.Id = d1.Profile.Id
+	d2.Service.Service.Id = "service"
+	d3 := models.Device{Id: bson.NewObjectId(), Name: "d3"}
+	d3.Service.Service.Id = "other"
+
+	tests := []struct {
+		name     string
+		alert    models.CallbackAlert
+		expected int
+	}{
+		{"Device by id", models.CallbackAlert{ActionType: models.DEVICE, Id: d1.Id.Hex()}, 1},
+		{"Device by name", models.CallbackAlert{ActionType: models.DEVICE, Id: "d3"}, 1},
+		{"Profile", models.CallbackAlert{ActionType: models.PROFILE, Id: d1.Profile.Id.Hex()}, 2},
+		{"Service", models.CallbackAlert{ActionType: models.SERVICE, Id: "service"}, 2},
+		{"Unrelated", models.CallbackAlert{ActionType: models.SCHEDULE, Id: "service"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.clear()
+			for _, d := range []models.Device{d1, d2, d3} {
+				c.put(d.Name, d, 10)
+			}
+			if removed := c.invalidate(tt.alert); removed != tt.expected {
+				t.Errorf("expected %d devices removed, got %d", tt.expected, removed)
+			}
+		})
+	}
+}
+
+func TestGetDevice(t *testing.T) {
+	reset()
+	devices.clear()
+	defer func() {
+		mdc = newMockDeviceClient()
+		devices.clear()
+	}()
+
+	client := &mocks.DeviceClient{}
+	client.On("CheckForDevice", "cached").Return(models.Device{Name: "cached"}, nil).Once()
+	client.On("CheckForDevice", "cached").Return(models.Device{}, fmt.Errorf("connection refused"))
+	client.On("CheckForDevice", "deleted").Return(models.Device{Name: "deleted"}, nil).Once()
+	client.On("CheckForDevice", "deleted").Return(models.Device{}, types.NewErrServiceClient(http.StatusNotFound, []byte{}))
+	client.On("CheckForDevice", "fresh").Return(models.Device{Name: "fresh"}, nil).Once()
+	mdc = client
+
+	// Without the cache every lookup calls metadata
+	getDevice("cached")
+	if _, err := getDevice("cached"); err == nil {
+		t.Fatalf("expected an error without the cache")
+	}
+
+	Configuration.Writable.DeviceCache = DeviceCacheInfo{Size: 10, TTL: 60000}
+	for i := 0; i < 3; i++ {
+		if d, err := getDevice("fresh"); err != nil || d.Name != "fresh" {
+			t.Fatalf("unexpected device %v %v", d, err)
+		}
+	}
+	client.AssertNumberOfCalls(t, "CheckForDevice", 3)
+
+	// Stale devices are used when metadata cannot be reached, and dropped when it no longer knows them
+	Configuration.Writable.DeviceCache.TTL = 0
+	devices.put("cached", models.Device{Name: "cached"}, 10)
+	if d, err := getDevice("cached"); err != nil || d.Name != "cached" {
+		t.Errorf("expected the stale device, got %v %v", d, err)
+	}
+	getDevice("deleted")
+	if _, err := getDevice("deleted"); err == nil {
+		t.Errorf("expected an error for the deleted device")
+	}
+	if _, _, found := devices.get("deleted", 0); found {
+		t.Errorf("expected the deleted device to be removed from the cache")
+	}
+}
+
+func TestCallbackHandler(t *testing.T) {
+	reset()
+	devices.clear()
+	defer devices.clear()
+	d := models.Device{Id: bson.NewObjectId(), Name: testDeviceName}
+	devices.put(testDeviceName, d, 10)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		cached bool
+	}{
+		{"Unrelated", `{"type":"DEVICE","id":"other"}`, http.StatusOK, true},
+		{"Device", `{"type":"DEVICE","id":"` + d.Id.Hex() + `"}`, http.StatusOK, false},
+		{"Malformed", `{"type":`, http.StatusBadRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/callback", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			testRoutes.ServeHTTP(rr, req)
+			if rr.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, rr.Code)
+			}
+			if _, _, found := devices.get(testDeviceName, time.Minute); found != tt.cached {
+				t.Errorf("expected cached %v, got %v", tt.cached, found)
+			}
+		})
+	}
+}
+
+func TestLastReportedUpdate(t *testing.T) {
+	reset()
+	defer func() { mdc = newMockDeviceClient() }()
+	Configuration.Writable.DeviceUpdateLastConnected = true
+
+	client := &mocks.DeviceClient{}
+	client.On("CheckForDevice", "Thermostat").Return(models.Device{Name: "Thermostat"}, nil)
+	client.On("UpdateLastConnectedByName", "Thermostat", int64(20)).Return(nil)
+	client.On("UpdateLastReportedByName", "Thermostat", int64(20)).Return(nil)
+	mdc = client
+
+	reported := newLastReported()
+	reported.devices["Thermostat"] = 20
+	reported.update()
+
+	client.AssertNumberOfCalls(t, "UpdateLastConnectedByName", 1)
+	client.AssertNumberOfCalls(t, "UpdateLastReportedByName", 1)
+}
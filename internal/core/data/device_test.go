@@ -33,7 +33,9 @@ const (
 )
 
 // Mock implementation of the event publisher for testing purposes
-type mockEventPublisher struct{}
+type mockEventPublisher struct {
+	contentType string // Content type of the last event sent
+}
 
 func TestCheckMaxLimit(t *testing.T) {
 	reset()
@@ -63,7 +65,8 @@ func newMockEventPublisher(config messaging.PubSubConfiguration) messaging.Event
 	return &mockEventPublisher{}
 }
 
-func (zep *mockEventPublisher) SendEventMessage(e models.Event) error {
+func (zep *mockEventPublisher) SendEventMessage(e models.Event, contentType string) error {
+	zep.contentType = contentType
 	return nil
 }
 
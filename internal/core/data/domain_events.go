@@ -13,6 +13,12 @@
  *******************************************************************************/
 package data
 
+import (
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+)
+
 // An event indicating that a given device has just reported some data
 type DeviceLastReported struct {
 	DeviceName string
@@ -23,26 +29,61 @@ type DeviceServiceLastReported struct {
 	DeviceName string
 }
 
+// Handle the domain events. The last reported updates are coalesced: a device, or device service,
+// reporting several times within the configured interval is updated once in metadata at its end.
 func initEventHandlers() {
 	go func() {
+		reported := newLastReported()
+		var flush <-chan time.Time
 		for {
 			select {
 			case e, ok := <-chEvents:
-				if ok {
-					switch e.(type) {
-					case DeviceLastReported:
-						dlr := e.(DeviceLastReported)
-						updateDeviceLastReportedConnected(dlr.DeviceName)
-						break
-					case DeviceServiceLastReported:
-						dslr := e.(DeviceServiceLastReported)
-						updateDeviceServiceLastReportedConnected(dslr.DeviceName)
-						break
-					}
-				} else {
+				if !ok {
+					reported.update()
 					return
 				}
+				switch e.(type) {
+				case DeviceLastReported:
+					dlr := e.(DeviceLastReported)
+					reported.devices[dlr.DeviceName] = db.MakeTimestamp()
+				case DeviceServiceLastReported:
+					dslr := e.(DeviceServiceLastReported)
+					reported.services[dslr.DeviceName] = db.MakeTimestamp()
+				}
+
+				interval := Configuration.Writable.LastReportedInterval
+				if interval <= 0 {
+					reported.update()
+					reported = newLastReported()
+				} else if flush == nil {
+					flush = time.After(time.Duration(interval) * time.Millisecond)
+				}
+			case <-flush:
+				reported.update()
+				reported = newLastReported()
+				flush = nil
 			}
 		}
 	}()
 }
+
+// When devices, keyed by name, last reported since metadata was last updated
+type lastReported struct {
+	devices  map[string]int64
+	services map[string]int64
+}
+
+func newLastReported() lastReported {
+	return lastReported{devices: make(map[string]int64), services: make(map[string]int64)}
+}
+
+// Update metadata with the last reported times, once per device and device service
+func (r lastReported) update() {
+	for device, t := range r.devices {
+		updateDeviceLastReportedConnected(device, t)
+	}
+	updated := make(map[string]bool)
+	for device, t := range r.services {
+		updateDeviceServiceLastReportedConnected(device, t, updated)
+	}
+}
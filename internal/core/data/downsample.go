@@ -0,0 +1,275 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Time between downsampling passes when none is configured
+const defaultDownsamplingInterval = 60000
+
+// Number of readings read from the database at once during a downsampling pass
+const downsamplingPageSize = 1000
+
+// What a rule rolled up during a downsampling pass
+type downsamplingRollup struct {
+	ValueDescriptor string `json:"valueDescriptor"`
+	Readings        int    `json:"readings"`
+	Summaries       int    `json:"summaries"`
+}
+
+// Outcome of a downsampling pass
+type downsamplingReport struct {
+	Started   int64                `json:"started"`
+	Completed int64                `json:"completed"`
+	Readings  int                  `json:"readings"`
+	Summaries int                  `json:"summaries"`
+	Rollups   []downsamplingRollup `json:"rollups"`
+	Errors    []string             `json:"errors,omitempty"`
+}
+
+// Rules of the downsampling along with the report of the latest pass
+type downsamplingState struct {
+	Enabled bool                        `json:"enabled"`
+	Rules   map[string]DownsamplingRule `json:"rules"`
+	Report  downsamplingReport          `json:"report"`
+}
+
+var downsamplingMutex sync.Mutex
+var lastDownsamplingReport downsamplingReport
+var chDownsampling chan struct{} // Closed to stop the background downsampling passes
+
+// Creation time up to which the readings of each value descriptor have been rolled up,
+// so that a pass only reads the readings aged since the previous one
+var downsampledUntil = make(map[string]int64)
+
+// Start running downsampling passes in the background, at the configured interval, while they are enabled
+func startDownsampling() {
+	chDownsampling = make(chan struct{})
+	go func(done chan struct{}) {
+		for {
+			interval := Configuration.Writable.Downsampling.Interval
+			if interval <= 0 {
+				interval = defaultDownsamplingInterval
+			}
+
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Duration(interval) * time.Millisecond):
+				if Configuration.Writable.Downsampling.Enabled {
+					runDownsampling()
+				}
+			}
+		}
+	}(chDownsampling)
+}
+
+func stopDownsampling() {
+	if chDownsampling != nil {
+		close(chDownsampling)
+		chDownsampling = nil
+	}
+}
+
+// Apply every configured downsampling rule once and report what was rolled up.
+// A failing rule is reported and does not prevent the other rules from being applied.
+func runDownsampling() downsamplingReport {
+	downsamplingMutex.Lock()
+	defer downsamplingMutex.Unlock()
+
+	rules := Configuration.Writable.Downsampling.Rules
+	report := downsamplingReport{Started: db.MakeTimestamp(), Rollups: []downsamplingRollup{}}
+
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rollup, err := downsample(name, rules[name], report.Started)
+		if err != nil {
+			msg := fmt.Sprintf("readings of value descriptor '%s': %v", name, err)
+			LoggingClient.Error("Downsampling failed: " + msg)
+			report.Errors = append(report.Errors, msg)
+		}
+		if rollup.Readings > 0 {
+			report.Readings += rollup.Readings
+			report.Summaries += rollup.Summaries
+			report.Rollups = append(report.Rollups, rollup)
+		}
+	}
+
+	report.Completed = db.MakeTimestamp()
+	if report.Readings > 0 || len(report.Errors) > 0 {
+		LoggingClient.Info(fmt.Sprintf("Downsampling replaced %d readings by %d summaries with %d errors", report.Readings, report.Summaries, len(report.Errors)))
+	}
+	lastDownsamplingReport = report
+	return report
+}
+
+// The rules of the downsampling and the report of the latest pass
+func getDownsamplingState() downsamplingState {
+	downsamplingMutex.Lock()
+	defer downsamplingMutex.Unlock()
+
+	return downsamplingState{
+		Enabled: Configuration.Writable.Downsampling.Enabled,
+		Rules:   Configuration.Writable.Downsampling.Rules,
+		Report:  lastDownsamplingReport,
+	}
+}
+
+// Readings of a device within a period, being rolled up into a summary reading
+type periodRollup struct {
+	summary contract.ReadingAggregate
+	ids     []string
+	pushed  int64
+}
+
+// Replace the numeric readings of the value descriptor created in the whole periods older than the
+// maximum age of the rule by one summary reading per device and period. Readings that are not numeric,
+// and the summary readings of earlier passes, are left in place.
+func downsample(name string, rule DownsamplingRule, now int64) (downsamplingRollup, error) {
+	rollup := downsamplingRollup{ValueDescriptor: name}
+	if rule.MaxAge <= 0 || rule.Period <= 0 {
+		return rollup, fmt.Errorf("the maximum age and the period must be positive")
+	}
+
+	// Only whole periods are rolled up, so that each period is summarized once
+	end := (now - rule.MaxAge) / rule.Period * rule.Period
+	start := downsampledUntil[name]
+	if end <= start {
+		return rollup, nil
+	}
+
+	type key struct {
+		device string
+		start  int64
+	}
+	periods := make(map[key]*periodRollup)
+
+	// Replace the readings of the periods starting before the given time, which are complete
+	flush := func(before int64) error {
+		for k, p := range periods {
+			if k.start >= before {
+				continue
+			}
+			if err := replaceBySummary(p); err != nil {
+				return err
+			}
+			rollup.Readings += len(p.ids)
+			rollup.Summaries++
+			delete(periods, k)
+		}
+		return nil
+	}
+
+	cursor := ""
+	for {
+		readings, next, err := dbClient.ReadingsPage(start, end-1, name, cursor, downsamplingPageSize)
+		if err != nil {
+			return rollup, err
+		}
+
+		for _, r := range readings {
+			if r.Summary != nil {
+				continue
+			}
+			value, err := strconv.ParseFloat(r.Value, 64)
+			if err != nil {
+				// Non numeric readings cannot be summarized
+				continue
+			}
+
+			// Readings come in creation order, so the periods before the one of the reading are complete
+			k := key{device: r.Device, start: r.Created / rule.Period * rule.Period}
+			if err = flush(k.start); err != nil {
+				return rollup, err
+			}
+			p, ok := periods[k]
+			if !ok {
+				p = &periodRollup{summary: contract.ReadingAggregate{
+					Name:   name,
+					Device: r.Device,
+					Start:  k.start,
+					End:    k.start + rule.Period,
+					Min:    value,
+					Max:    value,
+					First:  value,
+				}, pushed: r.Pushed}
+				periods[k] = p
+			}
+			p.add(r, value)
+		}
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if err := flush(end); err != nil {
+		return rollup, err
+	}
+	downsampledUntil[name] = end
+	return rollup, nil
+}
+
+func (p *periodRollup) add(r contract.Reading, value float64) {
+	s := &p.summary
+	if value < s.Min {
+		s.Min = value
+	}
+	if value > s.Max {
+		s.Max = value
+	}
+	// Avg holds the running sum until the period is complete
+	s.Avg += value
+	s.Last = value
+	s.Count++
+	p.ids = append(p.ids, r.Id)
+
+	// The summary is pushed only when every reading it replaces was
+	if r.Pushed == 0 || p.pushed == 0 {
+		p.pushed = 0
+	} else if r.Pushed > p.pushed {
+		p.pushed = r.Pushed
+	}
+}
+
+// Add the summary reading of the period in place of the readings it summarizes
+func replaceBySummary(p *periodRollup) error {
+	summary := p.summary
+	summary.Avg /= float64(summary.Count)
+	r := contract.Reading{
+		Name:    summary.Name,
+		Device:  summary.Device,
+		Value:   strconv.FormatFloat(summary.Avg, 'f', -1, 64),
+		Origin:  summary.Start,
+		Created: summary.Start,
+		Pushed:  p.pushed,
+		Summary: &summary,
+	}
+	_, err := dbClient.ReplaceReadings(p.ids, r)
+	return err
+}
@@ -0,0 +1,169 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/interfaces/mocks"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func resetDownsampling() {
+	downsampledUntil = make(map[string]int64)
+	lastDownsamplingReport = downsamplingReport{}
+}
+
+// Database returning the readings created between 1000 and 1199 over two pages
+func newDownsamplingMockDB() *dbMock.DBClient {
+	myMock := &dbMock.DBClient{}
+	myMock.On("ReadingsPage", int64(0), int64(1199), "Temperature", "", downsamplingPageSize).Return([]models.Reading{
+		{Id: "a", Device: "d1", Name: "Temperature", Value: "10", Created: 1000, Pushed: 5},
+		{Id: "c", Device: "d2", Name: "Temperature", Value: "5", Created: 1020},
+		{Id: "s", Device: "d1", Name: "Temperature", Value: "on", Created: 1040},
+		{Id: "b", Device: "d1", Name: "Temperature", Value: "30", Created: 1050, Pushed: 7},
+	}, "next", nil)
+	myMock.On("ReadingsPage", int64(0), int64(1199), "Temperature", "next", downsamplingPageSize).Return([]models.Reading{
+		{Id: "old", Device: "d1", Name: "Temperature", Value: "20", Created: 1100,
+			Summary: &models.ReadingAggregate{Count: 2}},
+		{Id: "d", Device: "d1", Name: "Temperature", Value: "40", Created: 1150},
+	}, "", nil)
+	return myMock
+}
+
+func TestDownsample(t *testing.T) {
+	reset()
+	resetDownsampling()
+	myMock := newDownsamplingMockDB()
+
+	replaced := map[string]models.Reading{}
+	myMock.On("ReplaceReadings", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ids := args.Get(0).([]string)
+		sort.Strings(ids)
+		replaced[fmt.Sprint(ids)] = args.Get(1).(models.Reading)
+	}).Return("new", nil)
+	dbClient = myMock
+
+	rule := DownsamplingRule{MaxAge: 1000, Period: 100}
+	rollup, err := downsample("Temperature", rule, 2250)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rollup.Readings != 4 || rollup.Summaries != 3 {
+		t.Errorf("expected 4 readings replaced by 3 summaries, got %+v", rollup)
+	}
+
+	expected := map[string]models.Reading{
+		"[a b]": {Name: "Temperature", Device: "d1", Value: "20", Origin: 1000, Created: 1000, Pushed: 7,
+			Summary: &models.ReadingAggregate{Name: "Temperature", Device: "d1", Start: 1000, End: 1100, Count: 2, Min: 10, Max: 30, Avg: 20, First: 10, Last: 30}},
+		"[c]": {Name: "Temperature", Device: "d2", Value: "5", Origin: 1000, Created: 1000,
+			Summary: &models.ReadingAggregate{Name: "Temperature", Device: "d2", Start: 1000, End: 1100, Count: 1, Min: 5, Max: 5, Avg: 5, First: 5, Last: 5}},
+		"[d]": {Name: "Temperature", Device: "d1", Value: "40", Origin: 1100, Created: 1100,
+			Summary: &models.ReadingAggregate{Name: "Temperature", Device: "d1", Start: 1100, End: 1200, Count: 1, Min: 40, Max: 40, Avg: 40, First: 40, Last: 40}},
+	}
+	if !reflect.DeepEqual(replaced, expected) {
+		t.Errorf("unexpected summaries %v", replaced)
+	}
+
+	// The periods already rolled up are not read again
+	rollup, err = downsample("Temperature", rule, 2250)
+	if err != nil || rollup.Readings != 0 {
+		t.Errorf("expected nothing more to roll up, got %+v %v", rollup, err)
+	}
+	myMock.AssertNumberOfCalls(t, "ReadingsPage", 2)
+}
+
+func TestDownsampleError(t *testing.T) {
+	reset()
+	resetDownsampling()
+	myMock := newDownsamplingMockDB()
+	myMock.On("ReplaceReadings", mock.Anything, mock.Anything).Return("", fmt.Errorf("some error"))
+	dbClient = myMock
+
+	if _, err := downsample("Temperature", DownsamplingRule{MaxAge: 1000, Period: 100}, 2250); err == nil {
+		t.Fatalf("expected an error replacing the readings")
+	}
+	if downsampledUntil["Temperature"] != 0 {
+		t.Errorf("the readings should be rolled up again on the next pass")
+	}
+}
+
+func TestRunDownsampling(t *testing.T) {
+	reset()
+	resetDownsampling()
+	myMock := &dbMock.DBClient{}
+	myMock.On("ReadingsPage", mock.Anything, mock.Anything, "Temperature", "", downsamplingPageSize).Return([]models.Reading{
+		{Id: "a", Device: "d1", Name: "Temperature", Value: "10", Created: 1000},
+	}, "", nil)
+	myMock.On("ReplaceReadings", []string{"a"}, mock.Anything).Return("new", nil)
+	dbClient = myMock
+	Configuration.Writable.Downsampling.Rules = map[string]DownsamplingRule{
+		"Temperature": {MaxAge: 1000, Period: 100},
+		"Pressure":    {MaxAge: 1000},
+	}
+
+	report := runDownsampling()
+
+	if report.Readings != 1 || report.Summaries != 1 || len(report.Rollups) != 1 {
+		t.Errorf("expected 1 reading replaced, reported %+v", report)
+	}
+	if len(report.Errors) != 1 {
+		t.Errorf("expected the rule without a period to be reported, got %v", report.Errors)
+	}
+	if getDownsamplingState().Report.Completed != report.Completed {
+		t.Errorf("the latest report should be kept")
+	}
+}
+
+func TestDownsamplingHandler(t *testing.T) {
+	reset()
+	resetDownsampling()
+	myMock := &dbMock.DBClient{}
+	myMock.On("ReadingsPage", int64(0), mock.Anything, "Temperature", "", downsamplingPageSize).Return([]models.Reading{}, "", nil)
+	dbClient = myMock
+	Configuration.Writable.Downsampling.Rules = map[string]DownsamplingRule{
+		"Temperature": {MaxAge: 1000, Period: 100},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, clients.ApiDownsamplingRoute, nil)
+	rr := httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	// The report of the pass is kept along with the rules
+	req = httptest.NewRequest(http.MethodGet, clients.ApiDownsamplingRoute, nil)
+	rr = httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var state downsamplingState
+	if err := json.Unmarshal(rr.Body.Bytes(), &state); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Rules) != 1 || state.Report.Completed == 0 || len(state.Report.Errors) != 0 {
+		t.Errorf("unexpected state %+v", state)
+	}
+}
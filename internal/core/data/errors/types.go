@@ -15,6 +15,8 @@ package errors
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
 )
 
@@ -30,6 +32,64 @@ func NewErrEventNotFound(id string) error {
 	return &ErrEventNotFound{id: id}
 }
 
+type ErrEventBufferFull struct {
+	max int
+}
+
+func (e ErrEventBufferFull) Error() string {
+	return fmt.Sprintf("database unavailable and event buffer full with %d events", e.max)
+}
+
+func NewErrEventBufferFull(max int) error {
+	return &ErrEventBufferFull{max: max}
+}
+
+type ErrRateLimited struct {
+	device     string
+	limit      string
+	retryAfter time.Duration
+}
+
+func (e ErrRateLimited) Error() string {
+	return fmt.Sprintf("event rate limit of %s exceeded by device '%s', retry after %v", e.limit, e.device, e.retryAfter)
+}
+
+// Time after which the limit allows another event of the device
+func (e ErrRateLimited) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func NewErrRateLimited(device string, limit string, retryAfter time.Duration) error {
+	return &ErrRateLimited{device: device, limit: limit, retryAfter: retryAfter}
+}
+
+type ErrTooManyReadings struct {
+	device string
+	count  int
+	max    int
+}
+
+func (e ErrTooManyReadings) Error() string {
+	return fmt.Sprintf("event of device '%s' has %d readings, more than the limit of %d", e.device, e.count, e.max)
+}
+
+func NewErrTooManyReadings(device string, count int, max int) error {
+	return &ErrTooManyReadings{device: device, count: count, max: max}
+}
+
+type ErrEventTagInvalid struct {
+	key    string
+	reason string
+}
+
+func (e ErrEventTagInvalid) Error() string {
+	return fmt.Sprintf("invalid event tag '%s': %s", e.key, e.reason)
+}
+
+func NewErrEventTagInvalid(key string, reason string) error {
+	return &ErrEventTagInvalid{key: key, reason: reason}
+}
+
 type ErrValueDescriptorInvalid struct {
 	name string
 	err  error
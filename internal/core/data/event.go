@@ -15,12 +15,28 @@ package data
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
 	contract "github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
+// Largest single line accepted in a newline-delimited batch of events
+const maxBatchLineSize = 1024 * 1024
+
+// Outcome of adding a single event of a batch
+type eventResult struct {
+	ID         string `json:"id,omitempty"`
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error,omitempty"`
+	// RetryAfter is the time, in milliseconds, after which a rate limited event may be sent again
+	RetryAfter int64 `json:"retryAfter,omitempty"`
+}
+
 func countEvents() (int, error) {
 	count, err := dbClient.EventCount()
 	if err != nil {
@@ -43,18 +59,10 @@ func countEventsByDevice(device string) (int, error) {
 }
 
 func deleteEventsByAge(age int64) (int, error) {
-	events, err := dbClient.EventsOlderThanAge(age)
+	count, err := dbClient.RemoveEventByAge(age)
 	if err != nil {
 		return -1, err
 	}
-
-	// Delete all the events
-	count := len(events)
-	for _, event := range events {
-		if err = deleteEvent(event); err != nil {
-			return -1, err
-		}
-	}
 	return count, nil
 }
 
@@ -74,46 +82,200 @@ func getEvents(limit int) ([]contract.Event, error) {
 	return events, err
 }
 
-func addNewEvent(e contract.Event) (string, error) {
+// Get a page of the events created between start and end, after the position of the cursor
+func getEventsPage(start int64, end int64, cursor string, limit int) (contract.EventPage, error) {
+	events, next, err := dbClient.EventsPage(start, end, nil, cursor, limit)
+	if err != nil {
+		return contract.EventPage{}, err
+	}
+	return contract.EventPage{Events: events, Next: next}, nil
+}
+
+// Add the event, unless the idempotency key or the fingerprint of the event shows it was already added.
+// The id of the original event is returned for duplicates, which are neither stored nor published again.
+// The event is published in the content type it was received in.
+func addNewEvent(e contract.Event, idempotencyKey string, contentType string) (string, error) {
 	err := checkDevice(e.Device)
+	var id string
+	var duplicate bool
+	if err == nil {
+		id, duplicate, err = deduplicate(submissionKey(e, idempotencyKey), func() (string, error) {
+			return addEvent(e, contentType)
+		})
+	}
+	countEvent(err, duplicate)
+	if err != nil {
+		return "", err
+	}
+	if duplicate {
+		LoggingClient.Info(fmt.Sprintf("Duplicate event from device %s ignored, original event %s", e.Device, id))
+		return id, nil
+	}
+
+	chEvents <- DeviceLastReported{e.Device}        // update last reported connected (device)
+	chEvents <- DeviceServiceLastReported{e.Device} // update last reported connected (device service)
+
+	return id, nil
+}
+
+// Rate limit, transform, validate, persist and publish an event whose device has been checked.
+// It is only called for events that are not duplicates, so that duplicates never use up the rate limit.
+func addEvent(e contract.Event, contentType string) (string, error) {
+	err := checkRateLimit(e)
 	if err != nil {
 		return "", err
 	}
 
-	if Configuration.Writable.ValidateCheck {
-		LoggingClient.Debug("Validation enabled, parsing events")
-		for reading := range e.Readings {
-			// Check value descriptor
-			name := e.Readings[reading].Name
-			vd, err := dbClient.ValueDescriptorByName(name)
-			if err != nil {
-				if err == db.ErrNotFound {
-					return "", errors.NewErrValueDescriptorNotFound(name)
-				} else {
-					return "", err
-				}
-			}
-			err = isValidValueDescriptor(vd, e.Readings[reading])
-			if err != nil {
-				return "", err
+	err = validateTags(e.Tags)
+	if err != nil {
+		return "", err
+	}
+
+	e, err = transformReadings(e)
+	if err != nil {
+		return "", err
+	}
+
+	err = validateReadings(e)
+	if err != nil {
+		return "", err
+	}
+
+	e, err = persistAndPublishEvent(e, contentType)
+	if err != nil {
+		return "", err
+	}
+	return e.ID, nil
+}
+
+// Add a batch of events, reporting the outcome of each one in the order received.
+// A failure on one event does not prevent the remaining events from being added.
+// The metadata check and the last reported updates are done once per device.
+func addNewEvents(events []contract.Event, contentType string) []eventResult {
+	results := make([]eventResult, len(events))
+	checked := make(map[string]error)
+	seen := make(map[string]bool)
+	var reported []string
+
+	for i, e := range events {
+		err, ok := checked[e.Device]
+		if !ok {
+			err = checkDevice(e.Device)
+			checked[e.Device] = err
+		}
+		var id string
+		var duplicate bool
+		if err == nil {
+			id, duplicate, err = deduplicate(submissionKey(e, ""), func() (string, error) {
+				return addEvent(e, contentType)
+			})
+		}
+		countEvent(err, duplicate)
+		if err != nil {
+			LoggingClient.Error(fmt.Sprintf("Error adding event %d of batch: %s", i, err.Error()))
+			results[i] = eventResult{StatusCode: eventErrorStatus(err), Error: err.Error(),
+				RetryAfter: int64(retryAfter(err) / time.Millisecond)}
+			continue
+		}
+
+		results[i] = eventResult{ID: id, StatusCode: http.StatusOK}
+		if duplicate {
+			LoggingClient.Info(fmt.Sprintf("Duplicate event %d of batch from device %s ignored, original event %s", i, e.Device, id))
+			continue
+		}
+		if !seen[e.Device] {
+			seen[e.Device] = true
+			reported = append(reported, e.Device)
+		}
+	}
+
+	for _, device := range reported {
+		chEvents <- DeviceLastReported{device}        // update last reported connected (device)
+		chEvents <- DeviceServiceLastReported{device} // update last reported connected (device service)
+	}
+
+	return results
+}
+
+// Check that the tag keys can be stored and queried, whatever the database
+func validateTags(tags map[string]string) error {
+	for key := range tags {
+		switch {
+		case key == "":
+			return errors.NewErrEventTagInvalid(key, "the key is empty")
+		case strings.Contains(key, "."):
+			return errors.NewErrEventTagInvalid(key, "the key contains a '.'")
+		case strings.HasPrefix(key, "$"):
+			return errors.NewErrEventTagInvalid(key, "the key starts with a '$'")
+		}
+	}
+	return nil
+}
+
+// Check the readings of the event against their value descriptors when validation is enabled
+func validateReadings(e contract.Event) error {
+	if !Configuration.Writable.ValidateCheck {
+		return nil
+	}
+
+	LoggingClient.Debug("Validation enabled, parsing events")
+	for reading := range e.Readings {
+		// Check value descriptor
+		name := e.Readings[reading].Name
+		vd, err := dbClient.ValueDescriptorByName(name)
+		if err != nil {
+			if err == db.ErrNotFound {
+				readingsValidated.Inc("invalid")
+				return errors.NewErrValueDescriptorNotFound(name)
+			} else {
+				return err
 			}
 		}
+		err = isValidValueDescriptor(vd, e.Readings[reading])
+		if err != nil {
+			readingsValidated.Inc("invalid")
+			return err
+		}
+		readingsValidated.Inc("valid")
 	}
+	return nil
+}
 
-	// Add the event and readings to the database
+// Add the event and readings to the database (if enabled) and push the event to the export service
+func persistAndPublishEvent(e contract.Event, contentType string) (contract.Event, error) {
 	if Configuration.Writable.PersistData {
-		id, err := dbClient.AddEvent(e)
+		id, err := storeEvent(e)
 		if err != nil {
-			return "", err
+			return e, err
 		}
 		e.ID = id
 	}
 
-	putEventOnQueue(e)                              // Push the aux struct to export service (It has the actual readings)
-	chEvents <- DeviceLastReported{e.Device}        // update last reported connected (device)
-	chEvents <- DeviceServiceLastReported{e.Device} // update last reported connected (device service)
+	putEventOnQueue(e, contentType) // Push the aux struct to export service (It has the actual readings)
+	streams.publish(e)              // Push the event to the live streams
+	return e, nil
+}
 
-	return e.ID, nil
+// Map an error raised while adding an event to the HTTP status reported for it
+func eventErrorStatus(err error) int {
+	switch t := err.(type) {
+	case *errors.ErrValueDescriptorNotFound:
+		return http.StatusBadRequest
+	case *errors.ErrValueDescriptorInvalid:
+		return http.StatusBadRequest
+	case *errors.ErrEventTagInvalid:
+		return http.StatusBadRequest
+	case *errors.ErrEventBufferFull:
+		return http.StatusServiceUnavailable
+	case *errors.ErrRateLimited:
+		return http.StatusTooManyRequests
+	case *errors.ErrTooManyReadings:
+		return http.StatusRequestEntityTooLarge
+	case *types.ErrServiceClient:
+		return t.StatusCode
+	default:
+		return http.StatusInternalServerError
+	}
 }
 
 func updateEvent(from contract.Event) error {
@@ -139,6 +301,12 @@ func updateEvent(from contract.Event) error {
 	if from.Origin != 0 {
 		to.Origin = from.Origin
 	}
+	if from.Tags != nil {
+		if err = validateTags(from.Tags); err != nil {
+			return err
+		}
+		to.Tags = from.Tags
+	}
 	return dbClient.UpdateEvent(to)
 }
 
@@ -199,10 +367,10 @@ func updateEventPushDate(id string) error {
 }
 
 // Put event on the message queue to be processed by the rules engine
-func putEventOnQueue(e contract.Event) {
+func putEventOnQueue(e contract.Event, contentType string) {
 	LoggingClient.Info("Putting event on message queue")
 	//	Have multiple implementations (start with ZeroMQ)
-	err := ep.SendEventMessage(e)
+	err := ep.SendEventMessage(e, contentType)
 	if err != nil {
 		LoggingClient.Error("Unable to send message for event: " + e.String())
 	}
@@ -218,8 +386,8 @@ func getEventsByDeviceIdLimit(limit int, deviceId string) ([]contract.Event, err
 	return eventList, nil
 }
 
-func getEventsByCreationTime(limit int, start int64, end int64) ([]contract.Event, error) {
-	eventList, err := dbClient.EventsByCreationTime(start, end, limit)
+func getEventsByTag(limit int, key string, value string) ([]contract.Event, error) {
+	eventList, err := dbClient.EventsByTag(key, value, limit)
 	if err != nil {
 		LoggingClient.Error(err.Error())
 		return nil, err
@@ -228,23 +396,23 @@ func getEventsByCreationTime(limit int, start int64, end int64) ([]contract.Even
 	return eventList, nil
 }
 
-func deleteEvents(deviceId string) (int, error) {
-	// Get the events by the device name
-	events, err := dbClient.EventsForDevice(deviceId)
+func getEventsByCreationTime(limit int, start int64, end int64) ([]contract.Event, error) {
+	eventList, err := dbClient.EventsByCreationTime(start, end, limit)
 	if err != nil {
 		LoggingClient.Error(err.Error())
-		return 0, err
+		return nil, err
 	}
 
+	return eventList, nil
+}
+
+func deleteEvents(deviceId string) (int, error) {
 	LoggingClient.Info("Deleting the events for device: " + deviceId)
 
-	// Delete the events
-	count := len(events)
-	for _, event := range events {
-		if err = deleteEvent(event); err != nil {
-			LoggingClient.Error(err.Error())
-			return 0, err
-		}
+	count, err := dbClient.DeleteEventsByDeviceId(deviceId)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		return 0, err
 	}
 
 	return count, nil
@@ -253,21 +421,11 @@ func deleteEvents(deviceId string) (int, error) {
 func scrubPushedEvents() (int, error) {
 	LoggingClient.Info("Scrubbing events.  Deleting all events that have been pushed")
 
-	// Get the events
-	events, err := dbClient.EventsPushed()
+	count, err := dbClient.ScrubEvents()
 	if err != nil {
 		LoggingClient.Error(err.Error())
 		return 0, err
 	}
 
-	// Delete all the events
-	count := len(events)
-	for _, event := range events {
-		if err = deleteEvent(event); err != nil {
-			LoggingClient.Error(err.Error())
-			return 0, err
-		}
-	}
-
 	return count, nil
 }
@@ -15,14 +15,21 @@
 package data
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
 	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/interfaces/mocks"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/encoding"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 
 	"github.com/globalsign/mgo/bson"
@@ -80,21 +87,9 @@ func buildEvents() []models.Event {
 func newDeleteEventsOlderThanAgeMockDB() *dbMock.DBClient {
 	myMock := &dbMock.DBClient{}
 
-	myMock.On("EventsOlderThanAge", mock.MatchedBy(func(age int64) bool {
+	myMock.On("RemoveEventByAge", mock.MatchedBy(func(age int64) bool {
 		return age == -1
-	})).Return(buildEvents(), nil).Maybe()
-
-	myMock.On("DeleteReadingById", mock.MatchedBy(func(id string) bool {
-		return id == "1"
-	})).Return(nil)
-
-	myMock.On("DeleteReadingById", mock.MatchedBy(func(id string) bool {
-		return id == "2"
-	})).Return(nil)
-
-	myMock.On("DeleteEventById", mock.MatchedBy(func(id string) bool {
-		return id == "1"
-	})).Return(nil)
+	})).Return(len(buildEvents()), nil)
 
 	return myMock
 }
@@ -115,13 +110,13 @@ func TestDeleteByAge(t *testing.T) {
 	mockDb.AssertExpectations(t)
 }
 
-func TestDeleteEventByAgeErrorThrownByEventsOlderThanAge(t *testing.T) {
+func TestDeleteEventByAgeErrorThrownByRemoveEventByAge(t *testing.T) {
 	reset()
 	myMock := &dbMock.DBClient{}
 
-	myMock.On("EventsOlderThanAge", mock.MatchedBy(func(age int64) bool {
+	myMock.On("RemoveEventByAge", mock.MatchedBy(func(age int64) bool {
 		return age == -1
-	})).Return([]models.Event{}, fmt.Errorf("some error"))
+	})).Return(0, fmt.Errorf("some error"))
 
 	dbClient = myMock
 
@@ -214,7 +209,7 @@ func TestAddEventWithPersistence(t *testing.T) {
 	wg.Add(1)
 	go handleDomainEvents(bitEvents, &wg, t)
 
-	_, err := addNewEvent(evt)
+	_, err := addNewEvent(evt, "", clients.ContentJson)
 	Configuration.Writable.PersistData = false
 	if err != nil {
 		t.Errorf(err.Error())
@@ -242,7 +237,7 @@ func TestAddEventNoPersistence(t *testing.T) {
 	wg.Add(1)
 	go handleDomainEvents(bitEvents, &wg, t)
 
-	newId, err := addNewEvent(evt)
+	newId, err := addNewEvent(evt, "", clients.ContentJson)
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -260,6 +255,125 @@ func TestAddEventNoPersistence(t *testing.T) {
 	myMock.AssertExpectations(t)
 }
 
+func newAddEventsMockDB() *dbMock.DBClient {
+	myMock := newAddEventMockDB(true)
+
+	myMock.On("ValueDescriptorByName", mock.MatchedBy(func(name string) bool {
+		return name == "Unknown"
+	})).Return(models.ValueDescriptor{}, db.ErrNotFound)
+	myMock.On("ValueDescriptorByName", mock.Anything).Return(models.ValueDescriptor{Type: "F"}, nil)
+
+	return myMock
+}
+
+func TestAddNewEventsPartialFailure(t *testing.T) {
+	reset()
+	myMock := newAddEventsMockDB()
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+	Configuration.Writable.ValidateCheck = true
+
+	invalid := buildReadings()[0:1]
+	invalid[0].Name = "Unknown"
+	events := []models.Event{
+		{Device: testDeviceName, Origin: testOrigin, Readings: buildReadings()},
+		{Device: testDeviceName, Origin: testOrigin, Readings: invalid},
+		{Device: testDeviceName, Origin: testOrigin, Readings: buildReadings()},
+	}
+	//wire up handlers to listen for device events
+	bitEvents := make([]bool, 2)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go handleDomainEvents(bitEvents, &wg, t)
+
+	results := addNewEvents(events, clients.ContentJson)
+	Configuration.Writable.PersistData = false
+	Configuration.Writable.ValidateCheck = false
+
+	if len(results) != len(events) {
+		t.Fatalf("expected %d results, received %d", len(events), len(results))
+	}
+	expected := []int{http.StatusOK, http.StatusBadRequest, http.StatusOK}
+	for i, result := range results {
+		if result.StatusCode != expected[i] {
+			t.Errorf("result %d: expected status %d, received %d", i, expected[i], result.StatusCode)
+		}
+		if (result.StatusCode == http.StatusOK) != (result.ID != "") {
+			t.Errorf("result %d: unexpected id %q for status %d", i, result.ID, result.StatusCode)
+		}
+	}
+
+	wg.Wait()
+	for i, val := range bitEvents {
+		if !val {
+			t.Errorf("event not received in timely fashion, index %v, TestAddNewEventsPartialFailure", i)
+		}
+	}
+
+	myMock.AssertNumberOfCalls(t, "AddEvent", 2)
+}
+
+func TestBatchEventHandler(t *testing.T) {
+	reset()
+	myMock := newAddEventMockDB(true)
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+
+	evt, _ := json.Marshal(models.Event{Device: testDeviceName, Origin: testOrigin, Readings: buildReadings()})
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		expected    []int
+	}{
+		{"JSON array", clients.ContentJson, "[" + string(evt) + "," + string(evt) + "]", http.StatusOK, []int{http.StatusOK, http.StatusOK}},
+		{"NDJSON", clients.ContentNdjson, string(evt) + "\n\n{bad json\n" + string(evt) + "\n", http.StatusOK, []int{http.StatusOK, http.StatusBadRequest, http.StatusOK}},
+		{"Malformed array", clients.ContentJson, "[" + string(evt), http.StatusBadRequest, nil},
+		{"Empty array", clients.ContentJson, "[]", http.StatusOK, []int{}},
+		{"Empty NDJSON", clients.ContentNdjson, "\n", http.StatusOK, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//drain the device events raised by the batch
+			bitEvents := make([]bool, 2)
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			go handleDomainEvents(bitEvents, &wg, t)
+
+			req := httptest.NewRequest(http.MethodPost, clients.ApiEventRoute+"/batch", strings.NewReader(tt.body))
+			req.Header.Set(clients.ContentType, tt.contentType)
+			rr := httptest.NewRecorder()
+			testRoutes.ServeHTTP(rr, req)
+			wg.Wait()
+
+			if rr.Code != tt.status {
+				t.Fatalf("expected status %d, received %d", tt.status, rr.Code)
+			}
+			if tt.expected == nil {
+				return
+			}
+
+			if len(tt.expected) == 0 && strings.TrimSpace(rr.Body.String()) != "[]" {
+				t.Fatalf("expected an empty array, received %s", rr.Body.String())
+			}
+			var results []eventResult
+			if err := json.Unmarshal(rr.Body.Bytes(), &results); err != nil {
+				t.Fatalf("unable to decode results: %s", err.Error())
+			}
+			if len(results) != len(tt.expected) {
+				t.Fatalf("expected %d results, received %d", len(tt.expected), len(results))
+			}
+			for i, result := range results {
+				if result.StatusCode != tt.expected[i] {
+					t.Errorf("result %d: expected status %d, received %d", i, tt.expected[i], result.StatusCode)
+				}
+			}
+		})
+	}
+	Configuration.Writable.PersistData = false
+}
+
 func TestAddEventWithValidationValueDescriptorExistsAndIsInvalid(t *testing.T) {
 	reset()
 	myMock := &dbMock.DBClient{}
@@ -280,7 +394,7 @@ func TestAddEventWithValidationValueDescriptorExistsAndIsInvalid(t *testing.T) {
 	wg.Add(1)
 	go handleDomainEvents(bitEvents, &wg, t)
 
-	_, err := addNewEvent(evt)
+	_, err := addNewEvent(evt, "", clients.ContentJson)
 	if err == nil {
 		t.Errorf("expected error")
 	}
@@ -305,7 +419,7 @@ func TestAddEventWithValidationValueDescriptorNotFound(t *testing.T) {
 	wg.Add(1)
 	go handleDomainEvents(bitEvents, &wg, t)
 
-	_, err := addNewEvent(evt)
+	_, err := addNewEvent(evt, "", clients.ContentJson)
 	switch err.(type) {
 	case *errors.ErrValueDescriptorNotFound:
 	// expected
@@ -333,7 +447,7 @@ func TestAddEventWithValidationValueDescriptorDBError(t *testing.T) {
 	wg.Add(1)
 	go handleDomainEvents(bitEvents, &wg, t)
 
-	_, err := addNewEvent(evt)
+	_, err := addNewEvent(evt, "", clients.ContentJson)
 	if err == nil {
 		t.Errorf("expected error")
 	}
@@ -629,27 +743,24 @@ func TestGetEventsByCreationTimeDBThrowsError(t *testing.T) {
 
 func TestDeleteEvents(t *testing.T) {
 	reset()
-	readings := buildReadings()
 	myMock := &dbMock.DBClient{}
 
-	myMock.On("EventsForDevice", mock.MatchedBy(func(deviceId string) bool {
+	myMock.On("DeleteEventsByDeviceId", mock.MatchedBy(func(deviceId string) bool {
 		return deviceId == testUUIDString
-	})).Return([]models.Event{{ID: testUUIDString, Readings: readings}}, nil)
-
-	myMock.On("DeleteReadingById", mock.Anything).Return(nil).Times(len(readings))
-
-	myMock.On("DeleteEventById", mock.MatchedBy(func(eventId string) bool {
-		return eventId == testUUIDString
-	})).Return(nil)
+	})).Return(2, nil)
 
 	dbClient = myMock
 
-	_, expectedNil := deleteEvents(testUUIDString)
+	count, expectedNil := deleteEvents(testUUIDString)
 
 	if expectedNil != nil {
 		t.Errorf("Should not throw error")
 	}
 
+	if count != 2 {
+		t.Errorf("Expected 2 deletions, was %d", count)
+	}
+
 	myMock.AssertExpectations(t)
 }
 
@@ -657,7 +768,7 @@ func TestDeleteEventsDBLookupThrowsError(t *testing.T) {
 	reset()
 	myMock := &dbMock.DBClient{}
 
-	myMock.On("EventsForDevice", mock.Anything).Return(nil, fmt.Errorf("some error"))
+	myMock.On("DeleteEventsByDeviceId", mock.Anything).Return(0, fmt.Errorf("some error"))
 
 	dbClient = myMock
 
@@ -675,19 +786,8 @@ func TestDeleteEventsDBLookupThrowsError(t *testing.T) {
 func TestScrubPushedEvents(t *testing.T) {
 	reset()
 
-	pushedEvents := []models.Event{testEvent, testEvent}
-	pushedEvents[1].ID = testUUIDString
-
 	myMock := &dbMock.DBClient{}
-	myMock.On("EventsPushed").Return(pushedEvents, nil)
-
-	myMock.On("DeleteReadingById", mock.MatchedBy(func(id string) bool {
-		return id == pushedEvents[0].Readings[0].Id || id == pushedEvents[0].Readings[1].Id
-	})).Return(nil).Times(4)
-
-	myMock.On("DeleteEventById", mock.MatchedBy(func(id string) bool {
-		return id == pushedEvents[0].ID || id == pushedEvents[1].ID
-	})).Return(nil).Twice()
+	myMock.On("ScrubEvents").Return(2, nil)
 
 	dbClient = myMock
 
@@ -716,3 +816,200 @@ func testEventWithoutReadings(event models.Event, t *testing.T) {
 		t.Error("origin mismatch. expected " + strconv.FormatInt(testEvent.Origin, 10) + " received " + strconv.FormatInt(event.Origin, 10))
 	}
 }
+
+func TestEventPageHandler(t *testing.T) {
+	reset()
+	Configuration.Service.ReadMaxLimit = 10
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("EventsPage", int64(100), int64(200), []string(nil), "", 2).Return([]models.Event{testEvent}, "next", nil)
+	myMock.On("EventsPage", int64(100), int64(200), []string(nil), "bad", 2).Return([]models.Event{}, "", db.ErrInvalidCursor)
+
+	dbClient = myMock
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"first page", "/page/100/200/2", http.StatusOK},
+		{"invalid cursor", "/page/100/200/2?cursor=bad", http.StatusBadRequest},
+		{"limit exceeded", "/page/100/200/20", http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, clients.ApiEventRoute+tt.path, nil)
+			rr := httptest.NewRecorder()
+			testRoutes.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Fatalf("expected status %d, received %d", tt.status, rr.Code)
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			var page models.EventPage
+			if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+				t.Fatalf("unable to decode page: %s", err.Error())
+			}
+			if len(page.Events) != 1 || page.Next != "next" {
+				t.Errorf("unexpected page %v", page)
+			}
+		})
+	}
+}
+
+func TestAddEventTagInvalid(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+
+	for _, key := range []string{"", "site.code", "$where"} {
+		evt := models.Event{Device: testDeviceName, Origin: testOrigin, Readings: buildReadings(), Tags: map[string]string{key: "A"}}
+		_, err := addEvent(evt, clients.ContentJson)
+		if _, ok := err.(*errors.ErrEventTagInvalid); !ok {
+			t.Errorf("tag key '%s': expected an invalid tag error, got %v", key, err)
+		}
+		if eventErrorStatus(err) != http.StatusBadRequest {
+			t.Errorf("tag key '%s': expected status %d, got %d", key, http.StatusBadRequest, eventErrorStatus(err))
+		}
+	}
+
+	myMock.AssertNotCalled(t, "AddEvent", mock.Anything)
+}
+
+func TestUpdateEventTags(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+	myMock.On("EventById", testEvent.ID).Return(models.Event{ID: testEvent.ID, Device: testDeviceName, Tags: map[string]string{"batch": "41"}}, nil)
+	myMock.On("UpdateEvent", mock.MatchedBy(func(e models.Event) bool {
+		return e.Tags["batch"] == "42"
+	})).Return(nil)
+	dbClient = myMock
+
+	err := updateEvent(models.Event{ID: testEvent.ID, Tags: map[string]string{"batch": "42"}})
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = updateEvent(models.Event{ID: testEvent.ID, Tags: map[string]string{"batch.id": "42"}})
+	if _, ok := err.(*errors.ErrEventTagInvalid); !ok {
+		t.Errorf("expected an invalid tag error, got %v", err)
+	}
+
+	myMock.AssertNumberOfCalls(t, "UpdateEvent", 1)
+}
+
+func TestEventsByTagHandler(t *testing.T) {
+	reset()
+	Configuration.Service.ReadMaxLimit = 10
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("EventsByTag", "site code", "A/1", 2).Return([]models.Event{testEvent}, nil)
+	myMock.On("EventsByTag", "batch", "error", 2).Return(nil, fmt.Errorf("some error"))
+
+	dbClient = myMock
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"escaped tag", "/tag/site+code/A%252F1/2", http.StatusOK},
+		{"invalid key", "/tag/site.code/A/2", http.StatusBadRequest},
+		{"limit exceeded", "/tag/batch/42/20", http.StatusRequestEntityTooLarge},
+		{"database error", "/tag/batch/error/2", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, clients.ApiEventRoute+tt.path, nil)
+			rr := httptest.NewRecorder()
+			testRoutes.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Fatalf("expected status %d, received %d", tt.status, rr.Code)
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			var events []models.Event
+			if err := json.Unmarshal(rr.Body.Bytes(), &events); err != nil {
+				t.Fatalf("unable to decode events: %s", err.Error())
+			}
+			if len(events) != 1 || events[0].ID != testEvent.ID {
+				t.Errorf("unexpected events %v", events)
+			}
+		})
+	}
+}
+
+func TestEventHandlerCBOR(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.MatchedBy(func(e models.Event) bool {
+		return e.Device == testDeviceName && len(e.Readings) == 1 && len(e.Readings[0].BinaryValue) == 3
+	})).Return(testBsonString, nil)
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+
+	evt := models.Event{Device: testDeviceName, Readings: []models.Reading{{Name: "Image", BinaryValue: []byte{0xff, 0xd8, 0xff}}}}
+	body, err := encoding.Marshal(evt, clients.ContentCbor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, clients.ApiEventRoute, bytes.NewReader(body))
+	req.Header.Set(clients.ContentType, clients.ContentCbor)
+	rr := httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, received %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if rr.Body.String() != testBsonString {
+		t.Errorf("expected id %s, received %s", testBsonString, rr.Body.String())
+	}
+	// The event is published in the encoding it was received in
+	if contentType := ep.(*mockEventPublisher).contentType; contentType != clients.ContentCbor {
+		t.Errorf("expected the event to be published as %s, got %s", clients.ContentCbor, contentType)
+	}
+	myMock.AssertExpectations(t)
+}
+
+func TestBatchEventHandlerCBOR(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return(testBsonString, nil)
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+
+	events := []models.Event{
+		{Device: testDeviceName, Origin: 1, Readings: buildReadings()},
+		{Device: testDeviceName, Origin: 2, Readings: buildReadings()},
+	}
+	body, err := encoding.Marshal(events, clients.ContentCbor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, clients.ApiEventRoute+"/batch", bytes.NewReader(body))
+	req.Header.Set(clients.ContentType, clients.ContentCbor)
+	rr := httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+
+	var results []eventResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &results); err != nil {
+		t.Fatalf("unable to decode results: %s", err.Error())
+	}
+	if len(results) != 2 || results[0].StatusCode != http.StatusOK || results[1].StatusCode != http.StatusOK {
+		t.Errorf("unexpected results %v", results)
+	}
+	myMock.AssertNumberOfCalls(t, "AddEvent", 2)
+}
@@ -0,0 +1,238 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Number of events read from the database at once during an export
+const exportPageSize = 500
+
+// Export formats
+const (
+	exportCSV    = "csv"
+	exportNDJSON = "ndjson"
+)
+
+// Export views, exporting whole events or one record per reading
+const (
+	exportEvents   = "events"
+	exportReadings = "readings"
+)
+
+var eventCSVHeader = []string{"id", "device", "origin", "created", "modified", "pushed", "readings"}
+var readingCSVHeader = []string{"eventId", "id", "device", "name", "value", "origin", "created", "modified", "pushed"}
+
+// Writes the exported events in a format and view
+type exportWriter interface {
+	write(e contract.Event) error
+	// Write what is still buffered to the underlying writer
+	flush() error
+}
+
+// Writer sending what was written so far on Flush
+type flusher interface {
+	Flush() error
+}
+
+// Selects the exported events. Empty fields match everything.
+type exportRequest struct {
+	start   int64
+	end     int64
+	devices []string
+	filter  streamFilter
+	format  string
+	view    string
+}
+
+// Content type of the export
+func (req exportRequest) contentType() string {
+	if req.format == exportCSV {
+		return "text/csv"
+	}
+	return clients.ContentNdjson
+}
+
+func (req exportRequest) newWriter(w io.Writer) exportWriter {
+	if req.format == exportCSV {
+		cw := &csvExportWriter{w: csv.NewWriter(w), readings: req.view == exportReadings}
+		if cw.readings {
+			cw.w.Write(readingCSVHeader)
+		} else {
+			cw.w.Write(eventCSVHeader)
+		}
+		return cw
+	}
+	return &ndjsonExportWriter{enc: json.NewEncoder(w), readings: req.view == exportReadings}
+}
+
+// Build an export request from the query parameters, checking the format and view
+func newExportRequest(start int64, end int64, device []string, reading []string, format string, view string) (exportRequest, error) {
+	if format == "" {
+		format = exportNDJSON
+	}
+	if format != exportCSV && format != exportNDJSON {
+		return exportRequest{}, fmt.Errorf("unsupported export format '%s'", format)
+	}
+	if view == "" {
+		view = exportEvents
+	}
+	if view != exportEvents && view != exportReadings {
+		return exportRequest{}, fmt.Errorf("unsupported export view '%s'", view)
+	}
+
+	return exportRequest{
+		start:   start,
+		end:     end,
+		devices: sortedNames(splitNames(device)),
+		filter:  newStreamFilter("", reading),
+		format:  format,
+		view:    view,
+	}, nil
+}
+
+// Names of the set in order, nil when it is empty
+func sortedNames(set map[string]bool) []string {
+	var names []string
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Export the events one page at a time, so that the events are never all held in memory.
+// The first page is read before anything is written, so that an error reading it can still be
+// reported with the status of the response. Begin is called once the first page has been read.
+func exportEventsPaged(req exportRequest, begin func() (io.Writer, error)) (int, error) {
+	events, cursor, err := dbClient.EventsPage(req.start, req.end, req.devices, "", exportPageSize)
+	if err != nil {
+		return 0, err
+	}
+	w, err := begin()
+	if err != nil {
+		return 0, err
+	}
+
+	writer := req.newWriter(w)
+	count := 0
+	for {
+		for _, e := range events {
+			e, ok := req.filter.apply(e)
+			if !ok {
+				continue
+			}
+			if err = writer.write(e); err != nil {
+				return count, err
+			}
+			count++
+		}
+		// Send each page as soon as it is written
+		if err = writer.flush(); err != nil {
+			return count, err
+		}
+		if f, ok := w.(flusher); ok {
+			if err = f.Flush(); err != nil {
+				return count, err
+			}
+		}
+
+		if cursor == "" {
+			return count, nil
+		}
+		events, cursor, err = dbClient.EventsPage(req.start, req.end, req.devices, cursor, exportPageSize)
+		if err != nil {
+			return count, err
+		}
+	}
+}
+
+type ndjsonExportWriter struct {
+	enc      *json.Encoder
+	readings bool
+}
+
+func (n *ndjsonExportWriter) write(e contract.Event) error {
+	if !n.readings {
+		return n.enc.Encode(e)
+	}
+
+	for _, r := range e.Readings {
+		// The reading is tied to its event, keeping the JSON form of readings
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		record := map[string]interface{}{}
+		if err = json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+		record["eventId"] = e.ID
+		if err = n.enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *ndjsonExportWriter) flush() error {
+	return nil
+}
+
+type csvExportWriter struct {
+	w        *csv.Writer
+	readings bool
+}
+
+func (c *csvExportWriter) write(e contract.Event) error {
+	if !c.readings {
+		readings, err := json.Marshal(e.Readings)
+		if err != nil {
+			return err
+		}
+		return c.w.Write([]string{e.ID, e.Device, formatInt(e.Origin), formatInt(e.Created), formatInt(e.Modified),
+			formatInt(e.Pushed), string(readings)})
+	}
+
+	for _, r := range e.Readings {
+		device := r.Device
+		if device == "" {
+			device = e.Device
+		}
+		err := c.w.Write([]string{e.ID, r.Id, device, r.Name, r.Value, formatInt(r.Origin), formatInt(r.Created),
+			formatInt(r.Modified), formatInt(r.Pushed)})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvExportWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatInt(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
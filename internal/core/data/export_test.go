@@ -0,0 +1,212 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/interfaces/mocks"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// Database returning the events of the devices over two pages
+func newExportMockDB() *dbMock.DBClient {
+	other := models.Event{ID: "other", Device: "Other", Readings: []models.Reading{{Name: "Humidity", Value: "20"}}}
+	pages := map[string][]models.Event{"": {testEvent, other}, "next": {testEvent}}
+	next := map[string]string{"": "next", "next": ""}
+	myMock := &dbMock.DBClient{}
+	myMock.On("EventsPage", int64(100), int64(200), mock.Anything, mock.Anything, exportPageSize).Return(
+		func(start int64, end int64, devices []string, cursor string, limit int) []models.Event {
+			var events []models.Event
+			for _, e := range pages[cursor] {
+				for _, d := range devices {
+					if e.Device == d {
+						events = append(events, e)
+					}
+				}
+				if len(devices) == 0 {
+					events = append(events, e)
+				}
+			}
+			return events
+		},
+		func(start int64, end int64, devices []string, cursor string, limit int) string {
+			return next[cursor]
+		},
+		nil)
+	myMock.On("EventsPage", int64(0), int64(1), mock.Anything, "", exportPageSize).Return(nil, "", fmt.Errorf("some error"))
+	return myMock
+}
+
+func TestEventExportHandler(t *testing.T) {
+	reset()
+	dbClient = newExportMockDB()
+
+	tests := []struct {
+		name        string
+		query       string
+		status      int
+		contentType string
+		lines       int
+	}{
+		{"NDJSON events", "", http.StatusOK, "application/x-ndjson", 3},
+		{"NDJSON readings", "?view=readings", http.StatusOK, "application/x-ndjson", 5},
+		{"Device", "?device=Other", http.StatusOK, "application/x-ndjson", 1},
+		{"Devices", "?device=Other," + url.QueryEscape(testDeviceName), http.StatusOK, "application/x-ndjson", 3},
+		{"Reading", "?view=readings&reading=Pressure", http.StatusOK, "application/x-ndjson", 2},
+		{"CSV events", "?format=csv", http.StatusOK, "text/csv", 4},
+		{"CSV readings", "?format=csv&view=readings&device=" + url.QueryEscape(testDeviceName), http.StatusOK, "text/csv", 5},
+		{"Unknown format", "?format=xml", http.StatusBadRequest, "", 0},
+		{"Unknown view", "?view=values", http.StatusBadRequest, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/event/export/100/200"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			testRoutes.ServeHTTP(rr, req)
+			if rr.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, rr.Code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("expected content type %s, got %s", tt.contentType, ct)
+			}
+			lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+			if len(lines) != tt.lines {
+				t.Errorf("expected %d lines, got %d: %s", tt.lines, len(lines), rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestEventExportReadings(t *testing.T) {
+	reset()
+	dbClient = newExportMockDB()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/event/export/100/200?view=readings&format=csv&reading=Temperature", nil)
+	rr := httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != strings.Join(readingCSVHeader, ",") {
+		t.Fatalf("unexpected records %v", records)
+	}
+	if records[1][0] != testEvent.ID || records[1][2] != testDeviceName || records[1][3] != "Temperature" || records[1][4] != "45" {
+		t.Errorf("unexpected record %v", records[1])
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/event/export/100/200?view=readings&reading=Temperature", nil)
+	rr = httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+	var reading map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.Split(rr.Body.String(), "\n")[0]), &reading); err != nil {
+		t.Fatal(err)
+	}
+	if reading["eventId"] != testEvent.ID || reading["name"] != "Temperature" || reading["value"] != "45" {
+		t.Errorf("unexpected reading %v", reading)
+	}
+}
+
+func TestEventExportGzip(t *testing.T) {
+	reset()
+	dbClient = newExportMockDB()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/event/export/100/200", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	rr := httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+
+	if rr.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzip encoded export")
+	}
+	gz, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 {
+		t.Errorf("expected 3 events, got %d", len(lines))
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	var tests = []struct {
+		header   string
+		accepted bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, GZIP", true},
+		{"gzip;q=0.5", true},
+		{"gzip;q=0", false},
+		{"gzip; q=0.0, deflate", false},
+		{"deflate, *", true},
+		{"gzip;q=0, *", false},
+		{"*;q=0, gzip", true},
+		{"identity", false},
+	}
+	for _, tt := range tests {
+		if accepted := acceptsGzip(tt.header); accepted != tt.accepted {
+			t.Errorf("expected gzip accepted to be %v for %q", tt.accepted, tt.header)
+		}
+	}
+}
+
+func TestEventExportGzipRefused(t *testing.T) {
+	reset()
+	dbClient = newExportMockDB()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/event/export/100/200", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0, identity")
+	rr := httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+
+	if rr.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected an export without encoding")
+	}
+	if lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n"); len(lines) != 3 {
+		t.Errorf("expected 3 events, got %d", len(lines))
+	}
+}
+
+func TestEventExportError(t *testing.T) {
+	reset()
+	dbClient = newExportMockDB()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/event/export/0/1", nil)
+	rr := httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/consul"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db/bolt"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db/mongo"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/startup"
 	"github.com/edgexfoundry/edgex-go/pkg/clients"
@@ -91,6 +92,12 @@ func Init(useConsul bool) bool {
 	}
 	chEvents = make(chan interface{}, 100)
 	initEventHandlers()
+	startRetention()
+	startDownsampling()
+	if err := startBuffer(); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error opening the event buffer: %s", err.Error()))
+		return false
+	}
 
 	if useConsul {
 		chConfig = make(chan interface{})
@@ -100,6 +107,10 @@ func Init(useConsul bool) bool {
 }
 
 func Destruct() {
+	stopRetention()
+	stopDownsampling()
+	stopBuffer()
+	streams.closeAll()
 	if dbClient != nil {
 		dbClient.CloseSession()
 		dbClient = nil
@@ -137,6 +148,8 @@ func newDBClient(dbType string, config db.Configuration) (interfaces.DBClient, e
 	switch dbType {
 	case db.MongoDB:
 		return mongo.NewClient(config)
+	case db.BoltDB:
+		return bolt.NewClient(config)
 	default:
 		return nil, db.ErrUnsupportedDatabase
 	}
@@ -249,7 +262,9 @@ func initializeClients(useConsul bool) {
 
 	// Create the event publisher
 	ep = messaging.NewEventPublisher(messaging.PubSubConfiguration{
-		AddressPort: Configuration.MessageQueue.Uri(),
+		AddressPort:   Configuration.MessageQueue.Uri(),
+		MessageQueue:  Configuration.MessageQueue,
+		LoggingClient: LoggingClient,
 	})
 }
 
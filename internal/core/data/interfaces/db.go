@@ -65,30 +65,57 @@ type DBClient interface {
 	// Get a list of events based on the device id
 	EventsForDevice(id string) ([]contract.Event, error)
 
+	// Get a list of events holding the tag with the value, limited by the limit
+	EventsByTag(key, value string, limit int) ([]contract.Event, error)
+
 	// Delete all of the events by the device id (and the readings)
-	//DeleteEventsByDeviceId(id string) error
+	// Return the number of events removed
+	DeleteEventsByDeviceId(id string) (int, error)
 
 	// Return a list of events whos creation time is between startTime and endTime
 	// Limit the number of results by limit
 	EventsByCreationTime(startTime, endTime int64, limit int) ([]contract.Event, error)
 
+	// Return a page of events whos creation time is between startTime and endTime, ordered by
+	// creation time, starting after the position of the cursor (from the start when empty)
+	// Only the events of the devices are returned, unless there are none
+	// Also return the cursor of the next page, which is empty when there are no more events
+	// InvalidCursor - the cursor was not produced by this database
+	EventsPage(startTime, endTime int64, devices []string, cursor string, limit int) ([]contract.Event, string, error)
+
 	// Return a list of readings for a device filtered by the value descriptor and limited by the limit
 	// The readings are linked to the device through an event
 	ReadingsByDeviceAndValueDescriptor(deviceId, valueDescriptor string, limit int) ([]contract.Reading, error)
 
 	// Remove all the events that are older than the given age
 	// Return the number of events removed
-	//RemoveEventByAge(age int64) (int, error)
+	RemoveEventByAge(age int64) (int, error)
 
 	// Get events that are older than a age
 	EventsOlderThanAge(age int64) ([]contract.Event, error)
 
-	// Remove all the events that have been pushed
-	//func (dbc *DBClient) ScrubEvents()(int, error)
+	// Remove all the events, and their readings, that have been pushed
+	// Return the number of events removed
+	ScrubEvents() (int, error)
 
 	// Get events that have been pushed (pushed field is not 0)
 	EventsPushed() ([]contract.Event, error)
 
+	// Delete the events, and their readings, created before the given time
+	// An empty device matches the events of every device
+	// Return the number of events removed
+	DeleteEventsCreatedBefore(device string, created int64) (int, error)
+
+	// Delete the events, and their readings, beyond the given number of most recently created ones
+	// An empty device matches the events of every device
+	// Return the number of events removed
+	DeleteEventsBeyondCount(device string, count int) (int, error)
+
+	// Delete the events, and their readings, that have been pushed
+	// An empty device matches the events of every device
+	// Return the number of events removed
+	DeletePushedEvents(device string) (int, error)
+
 	// Delete all readings and events
 	ScrubAllEvents() error
 
@@ -128,24 +155,58 @@ type DBClient interface {
 	// Return a list of readings whose name is in the list of value descriptor names
 	ReadingsByValueDescriptorNames(names []string, limit int) ([]contract.Reading, error)
 
-	// Return a list of readings specified by the UOM label
-	//ReadingsByUomLabel(uomLabel string, limit int)([]contract.Reading, error)
+	// Return a list of readings whose value descriptor has the UOM label
+	ReadingsByUomLabel(uomLabel string, limit int) ([]contract.Reading, error)
 
-	// Return a list of readings based on the label (value descriptor)
-	// 413 - limit exceeded
-	//ReadingsByLabel(label string, limit int) ([]contract.Reading, error)
+	// Return a list of readings whose value descriptor has the label
+	ReadingsByLabel(label string, limit int) ([]contract.Reading, error)
 
-	// Return a list of readings who's value descriptor has the type
-	//ReadingsByType(typeString string, limit int) ([]contract.Reading, error)
+	// Return a list of readings whose value descriptor has the type
+	ReadingsByType(typeString string, limit int) ([]contract.Reading, error)
 
 	// Return a list of readings whos created time is between the start and end times
 	ReadingsByCreationTime(start, end int64, limit int) ([]contract.Reading, error)
 
+	// Return a list of readings for the value descriptor whos created time is between the start and end times
+	ReadingsByValueDescriptorAndCreationTime(name string, start, end int64, limit int) ([]contract.Reading, error)
+
+	// Return a page of readings whos created time is between the start and end times, ordered by
+	// creation time, starting after the position of the cursor (from the start when empty)
+	// An empty value descriptor name matches every reading
+	// Also return the cursor of the next page, which is empty when there are no more readings
+	// InvalidCursor - the cursor was not produced by this database
+	ReadingsPage(start, end int64, name string, cursor string, limit int) ([]contract.Reading, string, error)
+
+	// Summarize the numeric readings of the value descriptor created between start (inclusive)
+	// and end (exclusive) into buckets of interval milliseconds, one series per device
+	// An empty device summarizes every device
+	ReadingAggregates(name string, device string, start, end, interval int64) ([]contract.ReadingAggregate, error)
+
+	// Delete the readings created before the given time
+	// An empty value descriptor name matches every reading
+	// Return the number of readings removed
+	DeleteReadingsCreatedBefore(name string, created int64) (int, error)
+
+	// Delete the readings beyond the given number of most recently created ones
+	// An empty value descriptor name matches every reading
+	// Return the number of readings removed
+	DeleteReadingsBeyondCount(name string, count int) (int, error)
+
+	// Delete the readings that have been pushed
+	// An empty value descriptor name matches every reading
+	// Return the number of readings removed
+	DeletePushedReadings(name string) (int, error)
+
+	// Add the reading in place of the readings with the ids, such as a summary of them
+	// Ids of readings that cannot be found are ignored
+	// Return the id of the reading added
+	ReplaceReadings(ids []string, r contract.Reading) (string, error)
+
 	// ************************** VALUE DESCRIPTOR FUNCTIONS ***************************
 	// Add a value descriptor
 	// 409 - Formatting is bad or it is not unique
 	// 503 - Unexpected
-	// TODO: Check for valid printf formatting
+	// The formatting is checked by the caller
 	AddValueDescriptor(v contract.ValueDescriptor) (string, error)
 
 	// Return a list of all the value descriptors
@@ -154,7 +215,7 @@ type DBClient interface {
 
 	// Update a value descriptor
 	// First use the ID for identification, then the name
-	// TODO: Check for the valid printf formatting
+	// The formatting is checked by the caller
 	// 404 not found if the value descriptor cannot be found by the identifiers
 	UpdateValueDescriptor(v contract.ValueDescriptor) error
 
@@ -106,6 +106,111 @@ func (_m *DBClient) DeleteEventById(id string) error {
 	return r0
 }
 
+// DeleteEventsBeyondCount provides a mock function with given fields: device, count
+func (_m *DBClient) DeleteEventsBeyondCount(device string, count int) (int, error) {
+	ret := _m.Called(device, count)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, int) int); ok {
+		r0 = rf(device, count)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(device, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteEventsByDeviceId provides a mock function with given fields: id
+func (_m *DBClient) DeleteEventsByDeviceId(id string) (int, error) {
+	ret := _m.Called(id)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteEventsCreatedBefore provides a mock function with given fields: device, created
+func (_m *DBClient) DeleteEventsCreatedBefore(device string, created int64) (int, error) {
+	ret := _m.Called(device, created)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, int64) int); ok {
+		r0 = rf(device, created)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = rf(device, created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePushedEvents provides a mock function with given fields: device
+func (_m *DBClient) DeletePushedEvents(device string) (int, error) {
+	ret := _m.Called(device)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(device)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePushedReadings provides a mock function with given fields: name
+func (_m *DBClient) DeletePushedReadings(name string) (int, error) {
+	ret := _m.Called(name)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteReadingById provides a mock function with given fields: id
 func (_m *DBClient) DeleteReadingById(id string) error {
 	ret := _m.Called(id)
@@ -120,6 +225,48 @@ func (_m *DBClient) DeleteReadingById(id string) error {
 	return r0
 }
 
+// DeleteReadingsBeyondCount provides a mock function with given fields: name, count
+func (_m *DBClient) DeleteReadingsBeyondCount(name string, count int) (int, error) {
+	ret := _m.Called(name, count)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, int) int); ok {
+		r0 = rf(name, count)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(name, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteReadingsCreatedBefore provides a mock function with given fields: name, created
+func (_m *DBClient) DeleteReadingsCreatedBefore(name string, created int64) (int, error) {
+	ret := _m.Called(name, created)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, int64) int); ok {
+		r0 = rf(name, created)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = rf(name, created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteValueDescriptorById provides a mock function with given fields: id
 func (_m *DBClient) DeleteValueDescriptorById(id string) error {
 	ret := _m.Called(id)
@@ -243,6 +390,29 @@ func (_m *DBClient) EventsByCreationTime(startTime int64, endTime int64, limit i
 	return r0, r1
 }
 
+// EventsByTag provides a mock function with given fields: key, value, limit
+func (_m *DBClient) EventsByTag(key string, value string, limit int) ([]models.Event, error) {
+	ret := _m.Called(key, value, limit)
+
+	var r0 []models.Event
+	if rf, ok := ret.Get(0).(func(string, string, int) []models.Event); ok {
+		r0 = rf(key, value, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, int) error); ok {
+		r1 = rf(key, value, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EventsForDevice provides a mock function with given fields: id
 func (_m *DBClient) EventsForDevice(id string) ([]models.Event, error) {
 	ret := _m.Called(id)
@@ -312,6 +482,36 @@ func (_m *DBClient) EventsOlderThanAge(age int64) ([]models.Event, error) {
 	return r0, r1
 }
 
+// EventsPage provides a mock function with given fields: startTime, endTime, devices, cursor, limit
+func (_m *DBClient) EventsPage(startTime int64, endTime int64, devices []string, cursor string, limit int) ([]models.Event, string, error) {
+	ret := _m.Called(startTime, endTime, devices, cursor, limit)
+
+	var r0 []models.Event
+	if rf, ok := ret.Get(0).(func(int64, int64, []string, string, int) []models.Event); ok {
+		r0 = rf(startTime, endTime, devices, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Event)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(int64, int64, []string, string, int) string); ok {
+		r1 = rf(startTime, endTime, devices, cursor, limit)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int64, int64, []string, string, int) error); ok {
+		r2 = rf(startTime, endTime, devices, cursor, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // EventsPushed provides a mock function with given fields:
 func (_m *DBClient) EventsPushed() ([]models.Event, error) {
 	ret := _m.Called()
@@ -358,6 +558,29 @@ func (_m *DBClient) EventsWithLimit(limit int) ([]models.Event, error) {
 	return r0, r1
 }
 
+// ReadingAggregates provides a mock function with given fields: name, device, start, end, interval
+func (_m *DBClient) ReadingAggregates(name string, device string, start int64, end int64, interval int64) ([]models.ReadingAggregate, error) {
+	ret := _m.Called(name, device, start, end, interval)
+
+	var r0 []models.ReadingAggregate
+	if rf, ok := ret.Get(0).(func(string, string, int64, int64, int64) []models.ReadingAggregate); ok {
+		r0 = rf(name, device, start, end, interval)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ReadingAggregate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, int64, int64, int64) error); ok {
+		r1 = rf(name, device, start, end, interval)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadingById provides a mock function with given fields: id
 func (_m *DBClient) ReadingById(id string) (models.Reading, error) {
 	ret := _m.Called(id)
@@ -492,6 +715,75 @@ func (_m *DBClient) ReadingsByDeviceAndValueDescriptor(deviceId string, valueDes
 	return r0, r1
 }
 
+// ReadingsByLabel provides a mock function with given fields: label, limit
+func (_m *DBClient) ReadingsByLabel(label string, limit int) ([]models.Reading, error) {
+	ret := _m.Called(label, limit)
+
+	var r0 []models.Reading
+	if rf, ok := ret.Get(0).(func(string, int) []models.Reading); ok {
+		r0 = rf(label, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reading)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(label, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadingsByType provides a mock function with given fields: typeString, limit
+func (_m *DBClient) ReadingsByType(typeString string, limit int) ([]models.Reading, error) {
+	ret := _m.Called(typeString, limit)
+
+	var r0 []models.Reading
+	if rf, ok := ret.Get(0).(func(string, int) []models.Reading); ok {
+		r0 = rf(typeString, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reading)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(typeString, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadingsByUomLabel provides a mock function with given fields: uomLabel, limit
+func (_m *DBClient) ReadingsByUomLabel(uomLabel string, limit int) ([]models.Reading, error) {
+	ret := _m.Called(uomLabel, limit)
+
+	var r0 []models.Reading
+	if rf, ok := ret.Get(0).(func(string, int) []models.Reading); ok {
+		r0 = rf(uomLabel, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reading)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(uomLabel, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadingsByValueDescriptor provides a mock function with given fields: name, limit
 func (_m *DBClient) ReadingsByValueDescriptor(name string, limit int) ([]models.Reading, error) {
 	ret := _m.Called(name, limit)
@@ -515,6 +807,29 @@ func (_m *DBClient) ReadingsByValueDescriptor(name string, limit int) ([]models.
 	return r0, r1
 }
 
+// ReadingsByValueDescriptorAndCreationTime provides a mock function with given fields: name, start, end, limit
+func (_m *DBClient) ReadingsByValueDescriptorAndCreationTime(name string, start int64, end int64, limit int) ([]models.Reading, error) {
+	ret := _m.Called(name, start, end, limit)
+
+	var r0 []models.Reading
+	if rf, ok := ret.Get(0).(func(string, int64, int64, int) []models.Reading); ok {
+		r0 = rf(name, start, end, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reading)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int64, int64, int) error); ok {
+		r1 = rf(name, start, end, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadingsByValueDescriptorNames provides a mock function with given fields: names, limit
 func (_m *DBClient) ReadingsByValueDescriptorNames(names []string, limit int) ([]models.Reading, error) {
 	ret := _m.Called(names, limit)
@@ -538,6 +853,78 @@ func (_m *DBClient) ReadingsByValueDescriptorNames(names []string, limit int) ([
 	return r0, r1
 }
 
+// ReadingsPage provides a mock function with given fields: start, end, name, cursor, limit
+func (_m *DBClient) ReadingsPage(start int64, end int64, name string, cursor string, limit int) ([]models.Reading, string, error) {
+	ret := _m.Called(start, end, name, cursor, limit)
+
+	var r0 []models.Reading
+	if rf, ok := ret.Get(0).(func(int64, int64, string, string, int) []models.Reading); ok {
+		r0 = rf(start, end, name, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reading)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(int64, int64, string, string, int) string); ok {
+		r1 = rf(start, end, name, cursor, limit)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int64, int64, string, string, int) error); ok {
+		r2 = rf(start, end, name, cursor, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RemoveEventByAge provides a mock function with given fields: age
+func (_m *DBClient) RemoveEventByAge(age int64) (int, error) {
+	ret := _m.Called(age)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int64) int); ok {
+		r0 = rf(age)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(age)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceReadings provides a mock function with given fields: ids, r
+func (_m *DBClient) ReplaceReadings(ids []string, r models.Reading) (string, error) {
+	ret := _m.Called(ids, r)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func([]string, models.Reading) string); ok {
+		r0 = rf(ids, r)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, models.Reading) error); ok {
+		r1 = rf(ids, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ScrubAllEvents provides a mock function with given fields:
 func (_m *DBClient) ScrubAllEvents() error {
 	ret := _m.Called()
@@ -566,6 +953,27 @@ func (_m *DBClient) ScrubAllValueDescriptors() error {
 	return r0
 }
 
+// ScrubEvents provides a mock function with given fields:
+func (_m *DBClient) ScrubEvents() (int, error) {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateEvent provides a mock function with given fields: e
 func (_m *DBClient) UpdateEvent(e models.Event) error {
 	ret := _m.Called(e)
@@ -14,18 +14,30 @@
 package messaging
 
 import (
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	logger "github.com/edgexfoundry/edgex-go/pkg/clients/logging"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
 // Configuration struct for PubSub
 type PubSubConfiguration struct {
 	AddressPort string
+	// The type of the message queue selects the publisher, the other settings apply to brokers
+	MessageQueue config.MessageQueueInfo
+	// Reports the connection state of the publishers that connect to a broker
+	LoggingClient logger.LoggingClient
 }
 
 type EventPublisher interface {
-	SendEventMessage(e models.Event) error
+	// SendEventMessage publishes the event encoded in the content type, JSON unless CBOR
+	SendEventMessage(e models.Event, contentType string) error
 }
 
 func NewEventPublisher(conf PubSubConfiguration) EventPublisher {
-	return newZeroMQEventPublisher(conf)
+	switch conf.MessageQueue.Type {
+	case config.MQTT:
+		return newMQTTEventPublisher(conf)
+	default:
+		return newZeroMQEventPublisher(conf)
+	}
 }
@@ -0,0 +1,68 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package messaging
+
+import (
+	"errors"
+	"fmt"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/encoding"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/mqtt"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var errPublishTimeout = errors.New("timed out publishing the event to the MQTT broker")
+
+// MQTT implementation of the event publisher
+type mqttEventPublisher struct {
+	client MQTT.Client
+	queue  config.MessageQueueInfo
+}
+
+// Events are rejected until the first connection to the broker succeeds, which is retried in the background
+func newMQTTEventPublisher(conf PubSubConfiguration) EventPublisher {
+	opts := mqtt.NewClientOptions(conf.MessageQueue)
+	opts.SetOnConnectHandler(func(c MQTT.Client) {
+		conf.LoggingClient.Info("Connected to the MQTT broker at " + conf.MessageQueue.Uri())
+	})
+	opts.SetConnectionLostHandler(func(c MQTT.Client, err error) {
+		conf.LoggingClient.Warn(fmt.Sprintf("Lost the connection to the MQTT broker, reconnecting: %v", err))
+	})
+
+	client := MQTT.NewClient(opts)
+	go mqtt.Connect(client, func(err error) {
+		conf.LoggingClient.Error(fmt.Sprintf("Could not connect to the MQTT broker at %s: %v", conf.MessageQueue.Uri(), err))
+	})
+
+	return &mqttEventPublisher{
+		client: client,
+		queue:  conf.MessageQueue,
+	}
+}
+
+// MQTT messages have no headers, subscribers tell the encoding of the event from its first byte
+func (mep *mqttEventPublisher) SendEventMessage(e models.Event, contentType string) error {
+	s, err := encoding.Marshal(&e, contentType)
+	if err != nil {
+		return err
+	}
+
+	token := mep.client.Publish(mqtt.EventTopic(mep.queue, e.Device), byte(mep.queue.Qos), false, s)
+	if !token.WaitTimeout(mqtt.PublishTimeout) {
+		return errPublishTimeout
+	}
+	return token.Error()
+}
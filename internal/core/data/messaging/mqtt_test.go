@@ -0,0 +1,109 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/encoding"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/mqtt"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/mqtt/test"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	logger "github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestMQTTEventPublisher(t *testing.T) {
+	broker, err := test.NewBroker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	queue := broker.MessageQueue()
+	queue.Topic = "events"
+	queue.TopicPerDevice = true
+	queue.Qos = 1
+	queue.ClientId = "core-data"
+
+	// Subscribe before publishing, the broker does not retain messages
+	received := make(chan MQTT.Message, 10)
+	subscribed := make(chan struct{}, 10)
+	opts := mqtt.NewClientOptions(queue)
+	opts.SetClientID("subscriber")
+	opts.SetOnConnectHandler(func(c MQTT.Client) {
+		token := c.Subscribe(mqtt.SubscriptionTopic(queue), 0, func(c MQTT.Client, msg MQTT.Message) {
+			received <- msg
+		})
+		if token.Wait() && token.Error() == nil {
+			subscribed <- struct{}{}
+		}
+	})
+	subscriber := MQTT.NewClient(opts)
+	if token := subscriber.Connect(); token.Wait() && token.Error() != nil {
+		t.Fatal(token.Error())
+	}
+	defer subscriber.Disconnect(0)
+
+	ep := NewEventPublisher(PubSubConfiguration{MessageQueue: queue, LoggingClient: logger.NewMockClient()})
+	if _, ok := ep.(*mqttEventPublisher); !ok {
+		t.Fatalf("expected an MQTT publisher, got %T", ep)
+	}
+
+	// Publishing fails until the publisher is connected, then again while it reconnects
+	send := func(e models.Event, contentType string) {
+		for i := 0; i < 50; i++ {
+			if err = ep.SendEventMessage(e, contentType); err == nil {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("could not publish the event: %v", err)
+	}
+	waitSubscribed := func() {
+		select {
+		case <-subscribed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the subscription")
+		}
+	}
+	expect := func(device string) {
+		select {
+		case msg := <-received:
+			var e models.Event
+			if err := encoding.Unmarshal(msg.Payload(), encoding.ContentTypeOf(msg.Payload()), &e); err != nil {
+				t.Fatal(err)
+			}
+			if e.Device != device || msg.Topic() != "events/"+device {
+				t.Errorf("unexpected event %s on %s", e.Device, msg.Topic())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the event of %s", device)
+		}
+	}
+
+	waitSubscribed()
+	send(models.Event{Device: "Thermostat"}, clients.ContentJson)
+	expect("Thermostat")
+
+	// The events received as CBOR are published as CBOR
+	// The subscriber reconnects too, and would miss an event published before it subscribes again
+	broker.Disconnect()
+	waitSubscribed()
+	send(models.Event{Device: "Meter"}, clients.ContentCbor)
+	expect("Meter")
+}
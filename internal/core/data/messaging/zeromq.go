@@ -14,9 +14,10 @@
 package messaging
 
 import (
-	"encoding/json"
 	"sync"
 
+	"github.com/edgexfoundry/edgex-go/internal/pkg/encoding"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	zmq "github.com/pebbe/zmq4"
 )
@@ -36,14 +37,20 @@ func newZeroMQEventPublisher(config PubSubConfiguration) EventPublisher {
 	}
 }
 
-func (zep *zeroMQEventPublisher) SendEventMessage(e models.Event) error {
-	s, err := json.Marshal(&e)
+// JSON events are sent as a single part message, as they always were. Events in other encodings are
+// sent as a two part message, the content type of the event followed by the encoded event.
+func (zep *zeroMQEventPublisher) SendEventMessage(e models.Event, contentType string) error {
+	s, err := encoding.Marshal(&e, contentType)
 	if err != nil {
 		return err
 	}
 	zep.mux.Lock()
 	defer zep.mux.Unlock()
-	_, err = zep.publisher.SendBytes(s, 0)
+	if contentType == clients.ContentCbor {
+		_, err = zep.publisher.SendMessage(contentType, s)
+	} else {
+		_, err = zep.publisher.SendBytes(s, 0)
+	}
 	if err != nil {
 		return err
 	}
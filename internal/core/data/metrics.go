@@ -0,0 +1,73 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
+)
+
+// Outcomes of the events submitted
+const (
+	eventAdded     = "added"
+	eventDuplicate = "duplicate"
+	eventRejected  = "rejected"
+)
+
+var eventsIngested = metrics.NewCounter("edgex_core_data_events_total",
+	"Events submitted to core data, by outcome: added, duplicate or rejected.", "outcome")
+
+var readingsValidated = metrics.NewCounter("edgex_core_data_readings_validated_total",
+	"Readings checked against their value descriptor, by outcome: valid or invalid.", "outcome")
+
+func init() {
+	// The buffer and the rate limits keep counters of their own, read when the metrics are written
+	metrics.NewGaugeFunc("edgex_core_data_buffered_events",
+		"Events in the on-disk buffer, waiting to be added to the database.", func() float64 {
+			return float64(getBufferStats().Depth)
+		})
+	metrics.NewCounterFunc("edgex_core_data_buffer_events_total",
+		"Events gone through the on-disk buffer, by outcome: buffered, replayed or dropped.", []string{"outcome"},
+		func() []metrics.Sample {
+			stats := getBufferStats()
+			return []metrics.Sample{
+				{LabelValues: []string{"buffered"}, Value: float64(stats.Buffered)},
+				{LabelValues: []string{"replayed"}, Value: float64(stats.Replayed)},
+				{LabelValues: []string{"dropped"}, Value: float64(stats.Dropped)},
+			}
+		})
+	metrics.NewCounterFunc("edgex_core_data_rate_limit_events_total",
+		"Events checked against the rate limits, by device and outcome: accepted, throttled or rejected.",
+		[]string{"device", "outcome"}, func() []metrics.Sample {
+			var samples []metrics.Sample
+			for device, c := range limiter.stats().Devices {
+				samples = append(samples,
+					metrics.Sample{LabelValues: []string{device, "accepted"}, Value: float64(c.Accepted)},
+					metrics.Sample{LabelValues: []string{device, "throttled"}, Value: float64(c.Throttled)},
+					metrics.Sample{LabelValues: []string{device, "rejected"}, Value: float64(c.Rejected)})
+			}
+			return samples
+		})
+}
+
+// Count the outcome of an event submitted
+func countEvent(err error, duplicate bool) {
+	switch {
+	case err != nil:
+		eventsIngested.Inc(eventRejected)
+	case duplicate:
+		eventsIngested.Inc(eventDuplicate)
+	default:
+		eventsIngested.Inc(eventAdded)
+	}
+}
@@ -0,0 +1,74 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/interfaces/mocks"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return(testBsonString, nil)
+	myMock.On("ValueDescriptorByName", "Temperature").Return(models.ValueDescriptor{Name: "Temperature", Type: "I"}, nil)
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+	Configuration.Writable.ValidateCheck = true
+	defer func() { Configuration.Writable.ValidateCheck = false }()
+
+	added := eventsIngested.Value(eventAdded)
+	rejected := eventsIngested.Value(eventRejected)
+	invalid := readingsValidated.Value("invalid")
+
+	for _, value := range []string{"45", "warm"} {
+		body := `{"device":"` + testDeviceName + `","readings":[{"name":"Temperature","value":"` + value + `"}]}`
+		req := httptest.NewRequest(http.MethodPost, clients.ApiEventRoute, strings.NewReader(body))
+		testRoutes.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if eventsIngested.Value(eventAdded) != added+1 || eventsIngested.Value(eventRejected) != rejected+1 {
+		t.Errorf("expected one event added and one rejected")
+	}
+	if readingsValidated.Value("invalid") != invalid+1 {
+		t.Errorf("expected one invalid reading")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, clients.ApiPrometheusRoute, nil)
+	rr := httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	for _, expected := range []string{
+		`edgex_core_data_events_total{outcome="added"}`,
+		`edgex_core_data_readings_validated_total{outcome="valid"}`,
+		`edgex_core_data_buffered_events 0`,
+		`edgex_http_requests_total{route="/api/v1/event",method="POST",code="400"}`,
+	} {
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("expected the metrics to hold %s", expected)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const (
+	// Interval between the sweeps of the buckets and counters of the idle devices
+	rateLimitSweepInterval = time.Minute
+	// Devices without events for this long are dropped from the counters
+	rateCountersIdle = time.Hour
+)
+
+// Tokens of the events accepted at a sustained rate, refilled as time passes up to the burst
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	// Time the bucket is refilled up to the burst, after which it is no different from a new one
+	full time.Time
+}
+
+// Add the tokens earned since the last refill, then return the time until a token is available
+func (b *tokenBucket) refill(now time.Time, limit RateLimit) time.Duration {
+	burst := limitBurst(limit)
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*limit.EventsPerSecond)
+	}
+	b.last = now
+	b.full = now.Add(time.Duration((burst - b.tokens) / limit.EventsPerSecond * float64(time.Second)))
+
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / limit.EventsPerSecond * float64(time.Second))
+}
+
+// Take the token of an event accepted, which takes a token's time longer to refill
+func (b *tokenBucket) take(limit RateLimit) {
+	b.tokens--
+	b.full = b.full.Add(time.Duration(float64(time.Second) / limit.EventsPerSecond))
+}
+
+func limitBurst(limit RateLimit) float64 {
+	if limit.Burst <= 0 {
+		return math.Ceil(limit.EventsPerSecond)
+	}
+	return float64(limit.Burst)
+}
+
+// Counters of the events of a device checked against the limits
+type rateCounters struct {
+	Service   string `json:"service,omitempty"`
+	Accepted  uint64 `json:"accepted"`
+	Throttled uint64 `json:"throttled"`
+	Rejected  uint64 `json:"rejected"`
+	last      time.Time
+}
+
+// State of the rate limits, with the counters keyed by device name
+type rateLimitStats struct {
+	Enabled bool                    `json:"enabled"`
+	Devices map[string]rateCounters `json:"devices"`
+}
+
+// Token buckets of the devices and device services, along with the counters of the devices.
+// The buckets and counters of the idle devices are swept, so that they do not pile up.
+type rateLimiter struct {
+	mutex    sync.Mutex
+	devices  map[string]*tokenBucket
+	services map[string]*tokenBucket
+	counters map[string]*rateCounters
+	swept    time.Time
+}
+
+var limiter = newRateLimiter()
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		devices:  make(map[string]*tokenBucket),
+		services: make(map[string]*tokenBucket),
+		counters: make(map[string]*rateCounters),
+	}
+}
+
+// Check the event against the limits of its device and of its device service, when the service has
+// limits of its own. The event only takes a token when every limit accepts it.
+func (l *rateLimiter) allow(e contract.Event, service string, now time.Time) error {
+	config := Configuration.Writable.RateLimit
+	limit, ok := config.Devices[e.Device]
+	if !ok {
+		limit = config.Default
+	}
+	serviceLimit, limitService := config.DeviceServices[service]
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.sweep(now)
+
+	counters, ok := l.counters[e.Device]
+	if !ok {
+		counters = &rateCounters{}
+		l.counters[e.Device] = counters
+	}
+	counters.Service = service
+	counters.last = now
+
+	if max := limit.MaxReadingsPerEvent; max > 0 && len(e.Readings) > max {
+		counters.Rejected++
+		return errors.NewErrTooManyReadings(e.Device, len(e.Readings), max)
+	}
+	if max := serviceLimit.MaxReadingsPerEvent; limitService && max > 0 && len(e.Readings) > max {
+		counters.Rejected++
+		return errors.NewErrTooManyReadings(e.Device, len(e.Readings), max)
+	}
+
+	type limitedBucket struct {
+		bucket *tokenBucket
+		limit  RateLimit
+	}
+	var buckets []limitedBucket
+	if limit.EventsPerSecond > 0 {
+		b := bucket(l.devices, e.Device)
+		if wait := b.refill(now, limit); wait > 0 {
+			counters.Throttled++
+			return errors.NewErrRateLimited(e.Device, fmt.Sprintf("%g events per second", limit.EventsPerSecond), wait)
+		}
+		buckets = append(buckets, limitedBucket{b, limit})
+	}
+	if limitService && serviceLimit.EventsPerSecond > 0 {
+		b := bucket(l.services, service)
+		if wait := b.refill(now, serviceLimit); wait > 0 {
+			counters.Throttled++
+			return errors.NewErrRateLimited(e.Device, fmt.Sprintf("%g events per second for device service '%s'",
+				serviceLimit.EventsPerSecond, service), wait)
+		}
+		buckets = append(buckets, limitedBucket{b, serviceLimit})
+	}
+
+	for _, b := range buckets {
+		b.bucket.take(b.limit)
+	}
+	counters.Accepted++
+	return nil
+}
+
+// Drop the buckets refilled up to their burst and the counters of the devices idle for long
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < rateLimitSweepInterval {
+		return
+	}
+	l.swept = now
+
+	for _, buckets := range []map[string]*tokenBucket{l.devices, l.services} {
+		for name, b := range buckets {
+			if !now.Before(b.full) {
+				delete(buckets, name)
+			}
+		}
+	}
+	for device, c := range l.counters {
+		if now.Sub(c.last) >= rateCountersIdle {
+			delete(l.counters, device)
+		}
+	}
+}
+
+func (l *rateLimiter) stats() rateLimitStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	stats := rateLimitStats{Enabled: Configuration.Writable.RateLimit.Enabled, Devices: make(map[string]rateCounters, len(l.counters))}
+	for device, c := range l.counters {
+		stats.Devices[device] = *c
+	}
+	return stats
+}
+
+func bucket(buckets map[string]*tokenBucket, name string) *tokenBucket {
+	b, ok := buckets[name]
+	if !ok {
+		b = &tokenBucket{}
+		buckets[name] = b
+	}
+	return b
+}
+
+// Check the event against the rate limits when they are enabled.
+// The device service of the device is only looked up when device services have limits.
+func checkRateLimit(e contract.Event) error {
+	config := Configuration.Writable.RateLimit
+	if !config.Enabled {
+		return nil
+	}
+
+	service := ""
+	if len(config.DeviceServices) > 0 {
+		d, err := getDevice(e.Device)
+		if err != nil {
+			LoggingClient.Warn(fmt.Sprintf("Device service of device %s unknown, only the device limits apply: %s", e.Device, err.Error()))
+		} else {
+			service = d.Service.Name
+		}
+	}
+
+	err := limiter.allow(e, service, time.Now())
+	if err != nil {
+		LoggingClient.Warn(err.Error())
+	}
+	return err
+}
+
+// Time after which a rate limited event may be sent again, 0 for other errors
+func retryAfter(err error) time.Duration {
+	if t, ok := err.(*errors.ErrRateLimited); ok {
+		return t.RetryAfter()
+	}
+	return 0
+}
+
+// Value of the Retry-After header, in whole seconds rounded up
+func retryAfterSeconds(wait time.Duration) string {
+	return fmt.Sprintf("%d", int64(math.Ceil(wait.Seconds())))
+}
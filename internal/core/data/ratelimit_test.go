@@ -0,0 +1,249 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/interfaces/mocks"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	reset()
+	l := newRateLimiter()
+	Configuration.Writable.RateLimit = RateLimitInfo{Enabled: true, Default: RateLimit{EventsPerSecond: 2, Burst: 3}}
+	e := models.Event{Device: testDeviceName}
+	now := time.Unix(1000, 0)
+
+	// The burst is accepted at once, then events are accepted at the sustained rate
+	for i := 0; i < 3; i++ {
+		if err := l.allow(e, "", now); err != nil {
+			t.Fatalf("expected event %d of the burst to be accepted, got %v", i, err)
+		}
+	}
+	err := l.allow(e, "", now)
+	limited, ok := err.(*errors.ErrRateLimited)
+	if !ok {
+		t.Fatalf("expected the event to be rate limited, got %v", err)
+	}
+	if limited.RetryAfter() != 500*time.Millisecond {
+		t.Errorf("expected to retry after 500ms, got %v", limited.RetryAfter())
+	}
+	if err = l.allow(e, "", now.Add(500*time.Millisecond)); err != nil {
+		t.Errorf("expected the event to be accepted once a token is earned, got %v", err)
+	}
+
+	// Other devices have buckets of their own
+	if err = l.allow(models.Event{Device: "Other"}, "", now); err != nil {
+		t.Errorf("expected the event of another device to be accepted, got %v", err)
+	}
+
+	counters := l.stats().Devices[testDeviceName]
+	if counters.Accepted != 4 || counters.Throttled != 1 || counters.Rejected != 0 {
+		t.Errorf("unexpected counters %+v", counters)
+	}
+}
+
+func TestRateLimiterDevices(t *testing.T) {
+	reset()
+	l := newRateLimiter()
+	Configuration.Writable.RateLimit = RateLimitInfo{
+		Enabled: true,
+		Default: RateLimit{MaxReadingsPerEvent: 1},
+		Devices: map[string]RateLimit{
+			"Chatty": {EventsPerSecond: 1},
+		},
+		DeviceServices: map[string]RateLimit{
+			"Service": {EventsPerSecond: 1, Burst: 2, MaxReadingsPerEvent: 5},
+		},
+	}
+	now := time.Unix(1000, 0)
+
+	err := l.allow(models.Event{Device: testDeviceName, Readings: buildReadings()}, "", now)
+	if _, ok := err.(*errors.ErrTooManyReadings); !ok {
+		t.Errorf("expected too many readings, got %v", err)
+	}
+	if eventErrorStatus(err) != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status %d, got %d", http.StatusRequestEntityTooLarge, eventErrorStatus(err))
+	}
+
+	// The device limit replaces the default one
+	if err = l.allow(models.Event{Device: "Chatty", Readings: buildReadings()}, "Service", now); err != nil {
+		t.Fatalf("expected the event to be accepted, got %v", err)
+	}
+	err = l.allow(models.Event{Device: "Chatty"}, "Service", now)
+	if eventErrorStatus(err) != http.StatusTooManyRequests {
+		t.Fatalf("expected the device to be rate limited, got %v", err)
+	}
+
+	// The devices of the service share its limit, a throttled event does not take a token of the service
+	if err = l.allow(models.Event{Device: "Quiet"}, "Service", now); err != nil {
+		t.Fatalf("expected the event to be accepted, got %v", err)
+	}
+	if err = l.allow(models.Event{Device: "Other"}, "Service", now); err == nil {
+		t.Errorf("expected the device service to be rate limited")
+	}
+
+	stats := l.stats()
+	if stats.Devices["Chatty"].Service != "Service" || stats.Devices["Chatty"].Throttled != 1 || stats.Devices[testDeviceName].Rejected != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	reset()
+	l := newRateLimiter()
+	Configuration.Writable.RateLimit = RateLimitInfo{
+		Enabled:        true,
+		Default:        RateLimit{EventsPerSecond: 1, Burst: 120},
+		DeviceServices: map[string]RateLimit{"Service": {EventsPerSecond: 1, Burst: 2}},
+	}
+	now := time.Unix(1000, 0)
+
+	for _, device := range []string{"Idle", "Busy"} {
+		if err := l.allow(models.Event{Device: device}, "Service", now); err != nil {
+			t.Fatalf("expected the event of %s to be accepted, got %v", device, err)
+		}
+	}
+	// The bucket of the device takes 100 seconds to refill
+	for i := 0; i < 99; i++ {
+		if err := l.allow(models.Event{Device: "Idle"}, "", now); err != nil {
+			t.Fatalf("expected the event to be accepted, got %v", err)
+		}
+	}
+
+	// The buckets refilled up to their burst are swept, the one still refilling is kept
+	if err := l.allow(models.Event{Device: "Busy"}, "", now.Add(rateLimitSweepInterval)); err != nil {
+		t.Fatalf("expected the event to be accepted, got %v", err)
+	}
+	if _, ok := l.services["Service"]; ok {
+		t.Error("expected the refilled bucket of the device service to be swept")
+	}
+	if _, ok := l.devices["Idle"]; !ok {
+		t.Error("expected the bucket still refilling to be kept")
+	}
+
+	// The devices idle for long are dropped from the counters
+	if err := l.allow(models.Event{Device: "Busy"}, "", now.Add(rateCountersIdle+time.Second)); err != nil {
+		t.Fatalf("expected the event to be accepted, got %v", err)
+	}
+	if len(l.devices) != 1 || len(l.counters) != 1 {
+		t.Errorf("expected the idle device to be swept, got buckets %v and counters %v", l.devices, l.counters)
+	}
+	if counters := l.stats().Devices["Busy"]; counters.Accepted != 3 {
+		t.Errorf("unexpected counters %+v", counters)
+	}
+}
+
+func TestEventHandlerRateLimited(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	limiter = newRateLimiter()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return(testBsonString, nil)
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+	Configuration.Writable.RateLimit = RateLimitInfo{Enabled: true, Default: RateLimit{EventsPerSecond: 0.5}}
+	body := `{"device":"` + testDeviceName + `","readings":[{"name":"Temperature","value":"45"}]}`
+
+	expected := []int{http.StatusOK, http.StatusTooManyRequests}
+	for i, status := range expected {
+		req := httptest.NewRequest(http.MethodPost, clients.ApiEventRoute, strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		testRoutes.ServeHTTP(rr, req)
+		if rr.Code != status {
+			t.Fatalf("event %d: expected status %d, got %d", i, status, rr.Code)
+		}
+		if status == http.StatusTooManyRequests && rr.Header().Get("Retry-After") != "2" {
+			t.Errorf("expected to retry after 2 seconds, got %s", rr.Header().Get("Retry-After"))
+		}
+	}
+	myMock.AssertNumberOfCalls(t, "AddEvent", 1)
+
+	req := httptest.NewRequest(http.MethodGet, clients.ApiRateLimitRoute, nil)
+	rr := httptest.NewRecorder()
+	testRoutes.ServeHTTP(rr, req)
+	var stats rateLimitStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if !stats.Enabled || stats.Devices[testDeviceName].Accepted != 1 || stats.Devices[testDeviceName].Throttled != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestAddNewEventsRateLimited(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	limiter = newRateLimiter()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return(testBsonString, nil)
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+	Configuration.Writable.RateLimit = RateLimitInfo{Enabled: true, Default: RateLimit{EventsPerSecond: 1, Burst: 2}}
+	e := models.Event{Device: testDeviceName, Readings: buildReadings()}
+
+	results := addNewEvents([]models.Event{e, e, e}, clients.ContentJson)
+
+	expected := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i, result := range results {
+		if result.StatusCode != expected[i] {
+			t.Errorf("result %d: expected status %d, received %d", i, expected[i], result.StatusCode)
+		}
+	}
+	if results[2].RetryAfter <= 0 || results[2].RetryAfter > 1000 {
+		t.Errorf("expected a retry hint of at most a second, got %d", results[2].RetryAfter)
+	}
+}
+
+func TestEventHandlerRateLimitedDuplicate(t *testing.T) {
+	reset()
+	resetDeduplication()
+	defer resetDeduplication()
+	limiter = newRateLimiter()
+	myMock := &dbMock.DBClient{}
+	myMock.On("AddEvent", mock.Anything).Return(testBsonString, nil)
+	dbClient = myMock
+	Configuration.Writable.PersistData = true
+	Configuration.Writable.Deduplication = DeduplicationInfo{Window: 60000, Size: 10}
+	Configuration.Writable.RateLimit = RateLimitInfo{Enabled: true, Default: RateLimit{EventsPerSecond: 0.5}}
+	body := `{"device":"` + testDeviceName + `","readings":[{"name":"Temperature","value":"45"}]}`
+
+	// Retries of an accepted event get its id without using up the rate limit
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, clients.ApiEventRoute, strings.NewReader(body))
+		req.Header.Set(clients.IdempotencyKey, "retry")
+		rr := httptest.NewRecorder()
+		testRoutes.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK || rr.Body.String() != testBsonString {
+			t.Fatalf("retry %d: unexpected response %d %s", i, rr.Code, rr.Body.String())
+		}
+	}
+	myMock.AssertNumberOfCalls(t, "AddEvent", 1)
+	if stats := limiter.stats(); stats.Devices[testDeviceName].Throttled != 0 {
+		t.Errorf("expected no event throttled, got %+v", stats)
+	}
+}
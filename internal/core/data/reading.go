@@ -247,6 +247,36 @@ func getReadingsByValueDescriptorNames(listOfNames []string, limit int) (reading
 	return readings, nil
 }
 
+func getReadingsByUomLabel(uomLabel string, limit int) (readings []contract.Reading, err error) {
+	readings, err = dbClient.ReadingsByUomLabel(uomLabel, limit)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		return nil, err
+	}
+
+	return readings, nil
+}
+
+func getReadingsByLabel(label string, limit int) (readings []contract.Reading, err error) {
+	readings, err = dbClient.ReadingsByLabel(label, limit)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		return nil, err
+	}
+
+	return readings, nil
+}
+
+func getReadingsByType(typeString string, limit int) (readings []contract.Reading, err error) {
+	readings, err = dbClient.ReadingsByType(typeString, limit)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		return nil, err
+	}
+
+	return readings, nil
+}
+
 func getReadingsByCreationTime(start int64, end int64, limit int) (readings []contract.Reading, err error) {
 	readings, err = dbClient.ReadingsByCreationTime(start, end, limit)
 	if err != nil {
@@ -257,6 +287,16 @@ func getReadingsByCreationTime(start int64, end int64, limit int) (readings []co
 	return readings, nil
 }
 
+// Get a page of the readings created between start and end, after the position of the cursor
+func getReadingsPage(start int64, end int64, cursor string, limit int) (contract.ReadingPage, error) {
+	readings, next, err := dbClient.ReadingsPage(start, end, "", cursor, limit)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		return contract.ReadingPage{}, err
+	}
+	return contract.ReadingPage{Readings: readings, Next: next}, nil
+}
+
 func getReadingsByDeviceAndValueDescriptor(device string, name string, limit int) (readings []contract.Reading, err error) {
 	readings, err = dbClient.ReadingsByDeviceAndValueDescriptor(device, name, limit)
 	if err != nil {
@@ -266,3 +306,25 @@ func getReadingsByDeviceAndValueDescriptor(device string, name string, limit int
 
 	return readings, nil
 }
+
+// Summarize the numeric readings of a value descriptor created between start (inclusive) and
+// end (exclusive) into fixed buckets of interval milliseconds, one series per device.
+// An empty device summarizes every device. Buckets without numeric readings are omitted.
+func getReadingAggregates(name string, device string, start int64, end int64, interval int64) ([]contract.ReadingAggregate, error) {
+	if interval <= 0 || end <= start {
+		return nil, fmt.Errorf("invalid aggregation range %d-%d with interval %d", start, end, interval)
+	}
+	buckets := (end - start + interval - 1) / interval
+	if buckets > int64(Configuration.Service.ReadMaxLimit) {
+		LoggingClient.Error(maxExceededString)
+		return nil, errors.NewErrLimitExceeded(int(buckets))
+	}
+
+	aggregates, err := dbClient.ReadingAggregates(name, device, start, end, interval)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		return nil, err
+	}
+
+	return aggregates, nil
+}
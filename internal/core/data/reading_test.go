@@ -3,6 +3,7 @@ package data
 import (
 	"fmt"
 	"math"
+	"reflect"
 	"testing"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
@@ -398,6 +399,99 @@ func TestGetReadingsByValueDescriptorNamesError(t *testing.T) {
 	}
 }
 
+func TestGetReadingsByUomLabel(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("ReadingsByUomLabel", "C", 10).Return([]models.Reading{}, nil)
+
+	dbClient = myMock
+
+	_, err := getReadingsByUomLabel("C", 10)
+
+	if err != nil {
+		t.Errorf("Unexpected error getting readings by UOM label")
+	}
+	myMock.AssertExpectations(t)
+}
+
+func TestGetReadingsByUomLabelError(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("ReadingsByUomLabel", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("some error"))
+
+	dbClient = myMock
+
+	_, err := getReadingsByUomLabel("error", 10)
+
+	if err == nil {
+		t.Errorf("Expected error in getting readings by UOM label")
+	}
+}
+
+func TestGetReadingsByLabel(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("ReadingsByLabel", "valid", 10).Return([]models.Reading{}, nil)
+
+	dbClient = myMock
+
+	_, err := getReadingsByLabel("valid", 10)
+
+	if err != nil {
+		t.Errorf("Unexpected error getting readings by label")
+	}
+	myMock.AssertExpectations(t)
+}
+
+func TestGetReadingsByLabelError(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("ReadingsByLabel", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("some error"))
+
+	dbClient = myMock
+
+	_, err := getReadingsByLabel("error", 10)
+
+	if err == nil {
+		t.Errorf("Expected error in getting readings by label")
+	}
+}
+
+func TestGetReadingsByType(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("ReadingsByType", "Int64", 10).Return([]models.Reading{}, nil)
+
+	dbClient = myMock
+
+	_, err := getReadingsByType("Int64", 10)
+
+	if err != nil {
+		t.Errorf("Unexpected error getting readings by type")
+	}
+	myMock.AssertExpectations(t)
+}
+
+func TestGetReadingsByTypeError(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("ReadingsByType", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("some error"))
+
+	dbClient = myMock
+
+	_, err := getReadingsByType("error", 10)
+
+	if err == nil {
+		t.Errorf("Expected error in getting readings by type")
+	}
+}
+
 func TestGetReadingsByCreationTime(t *testing.T) {
 	reset()
 	myMock := &dbMock.DBClient{}
@@ -457,3 +551,62 @@ func TestGetReadingsByDeviceAndValueDescriptorError(t *testing.T) {
 		t.Errorf("Expected error in getting readings by device and value descriptor")
 	}
 }
+
+func TestGetReadingAggregates(t *testing.T) {
+	reset()
+	Configuration.Service.ReadMaxLimit = 100
+	myMock := &dbMock.DBClient{}
+
+	expected := []models.ReadingAggregate{
+		{Name: "Temperature", Device: "d1", Start: 1000, End: 1100, Count: 3, Min: 10, Max: 30, Avg: 20, First: 10, Last: 30},
+	}
+	myMock.On("ReadingAggregates", "Temperature", "d1", int64(1000), int64(1200), int64(100)).Return(expected, nil)
+
+	dbClient = myMock
+
+	aggregates, err := getReadingAggregates("Temperature", "d1", 1000, 1200, 100)
+	if err != nil {
+		t.Fatalf("Unexpected error getting reading aggregates: %s", err.Error())
+	}
+	if !reflect.DeepEqual(aggregates, expected) {
+		t.Errorf("Unexpected reading aggregates %v", aggregates)
+	}
+}
+
+func TestGetReadingAggregatesOverLimit(t *testing.T) {
+	reset()
+	Configuration.Service.ReadMaxLimit = 1
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("ReadingAggregates", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.ReadingAggregate{}, nil)
+
+	dbClient = myMock
+
+	// The number of readings in the range is not limited
+	_, err := getReadingAggregates("Temperature", "", 0, 100, 100)
+	if err != nil {
+		t.Errorf("Unexpected error getting a single bucket: %s", err.Error())
+	}
+
+	_, err = getReadingAggregates("Temperature", "", 0, 100, 10)
+	switch err.(type) {
+	case *errors.ErrLimitExceeded:
+	// expected
+	default:
+		t.Errorf("Expected errors.ErrLimitExceeded for too many buckets")
+	}
+}
+
+func TestGetReadingAggregatesError(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("ReadingAggregates", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.ReadingAggregate{}, fmt.Errorf("some error"))
+
+	dbClient = myMock
+
+	_, err := getReadingAggregates("Temperature", "", 0, 100, 100)
+	if err == nil {
+		t.Errorf("Expected error in getting reading aggregates")
+	}
+}
@@ -0,0 +1,187 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+)
+
+// Time between retention passes when none is configured
+const defaultRetentionInterval = 60000
+
+// Rule names used in the retention report
+const (
+	retentionMaxAge      = "maxAge"
+	retentionMaxCount    = "maxCount"
+	retentionPurgePushed = "purgePushed"
+)
+
+// What a single rule removed during a retention pass
+type retentionPurge struct {
+	Device          string `json:"device,omitempty"`
+	ValueDescriptor string `json:"valueDescriptor,omitempty"`
+	Rule            string `json:"rule"`
+	Events          int    `json:"events"`
+	Readings        int    `json:"readings"`
+}
+
+// Outcome of a retention pass
+type retentionReport struct {
+	Started   int64            `json:"started"`
+	Completed int64            `json:"completed"`
+	Events    int              `json:"events"`
+	Readings  int              `json:"readings"`
+	Purges    []retentionPurge `json:"purges"`
+	Errors    []string         `json:"errors,omitempty"`
+}
+
+var retentionMutex sync.Mutex
+var lastRetentionReport retentionReport
+var chRetention chan struct{} // Closed to stop the background retention passes
+
+// Start running retention passes in the background, at the configured interval, while they are enabled
+func startRetention() {
+	chRetention = make(chan struct{})
+	go func(done chan struct{}) {
+		for {
+			interval := Configuration.Writable.Retention.Interval
+			if interval <= 0 {
+				interval = defaultRetentionInterval
+			}
+
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Duration(interval) * time.Millisecond):
+				if Configuration.Writable.Retention.Enabled {
+					runRetention()
+				}
+			}
+		}
+	}(chRetention)
+}
+
+func stopRetention() {
+	if chRetention != nil {
+		close(chRetention)
+		chRetention = nil
+	}
+}
+
+// Apply every configured retention rule once and report what was removed.
+// A failing rule is reported and does not prevent the other rules from being applied.
+func runRetention() retentionReport {
+	retentionMutex.Lock()
+	defer retentionMutex.Unlock()
+
+	config := Configuration.Writable.Retention
+	report := retentionReport{Started: db.MakeTimestamp(), Purges: []retentionPurge{}}
+
+	applyEventPolicy(&report, "", config.Default)
+	for _, device := range sortedKeys(config.Devices) {
+		applyEventPolicy(&report, device, config.Devices[device])
+	}
+	for _, name := range sortedKeys(config.ValueDescriptors) {
+		applyReadingPolicy(&report, name, config.ValueDescriptors[name])
+	}
+
+	report.Completed = db.MakeTimestamp()
+	if report.Events > 0 || report.Readings > 0 || len(report.Errors) > 0 {
+		LoggingClient.Info(fmt.Sprintf("Retention removed %d events and %d readings with %d errors", report.Events, report.Readings, len(report.Errors)))
+	}
+	lastRetentionReport = report
+	return report
+}
+
+// The report of the latest retention pass
+func getRetentionReport() retentionReport {
+	retentionMutex.Lock()
+	defer retentionMutex.Unlock()
+
+	return lastRetentionReport
+}
+
+// Remove the events, of the device or of every device when empty, that the policy no longer retains
+func applyEventPolicy(report *retentionReport, device string, policy RetentionPolicy) {
+	record := func(rule string, removed int, err error) {
+		if err != nil {
+			report.addError(fmt.Sprintf("%s on events of device '%s': %v", rule, device, err))
+			return
+		}
+		if removed > 0 {
+			report.Events += removed
+			report.Purges = append(report.Purges, retentionPurge{Device: device, Rule: rule, Events: removed})
+		}
+	}
+
+	if policy.PurgePushed {
+		removed, err := dbClient.DeletePushedEvents(device)
+		record(retentionPurgePushed, removed, err)
+	}
+	if policy.MaxAge > 0 {
+		removed, err := dbClient.DeleteEventsCreatedBefore(device, db.MakeTimestamp()-policy.MaxAge)
+		record(retentionMaxAge, removed, err)
+	}
+	if policy.MaxCount > 0 {
+		removed, err := dbClient.DeleteEventsBeyondCount(device, policy.MaxCount)
+		record(retentionMaxCount, removed, err)
+	}
+}
+
+// Remove the readings of the value descriptor that the policy no longer retains
+func applyReadingPolicy(report *retentionReport, name string, policy RetentionPolicy) {
+	record := func(rule string, removed int, err error) {
+		if err != nil {
+			report.addError(fmt.Sprintf("%s on readings of value descriptor '%s': %v", rule, name, err))
+			return
+		}
+		if removed > 0 {
+			report.Readings += removed
+			report.Purges = append(report.Purges, retentionPurge{ValueDescriptor: name, Rule: rule, Readings: removed})
+		}
+	}
+
+	if policy.PurgePushed {
+		removed, err := dbClient.DeletePushedReadings(name)
+		record(retentionPurgePushed, removed, err)
+	}
+	if policy.MaxAge > 0 {
+		removed, err := dbClient.DeleteReadingsCreatedBefore(name, db.MakeTimestamp()-policy.MaxAge)
+		record(retentionMaxAge, removed, err)
+	}
+	if policy.MaxCount > 0 {
+		removed, err := dbClient.DeleteReadingsBeyondCount(name, policy.MaxCount)
+		record(retentionMaxCount, removed, err)
+	}
+}
+
+func (r *retentionReport) addError(msg string) {
+	LoggingClient.Error("Retention failed: " + msg)
+	r.Errors = append(r.Errors, msg)
+}
+
+// Keys of the policies in a stable order, so that passes and reports are reproducible
+func sortedKeys(policies map[string]RetentionPolicy) []string {
+	keys := make([]string, 0, len(policies))
+	for k := range policies {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
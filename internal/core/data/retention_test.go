@@ -0,0 +1,78 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"fmt"
+	"testing"
+
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/interfaces/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunRetention(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+
+	myMock.On("DeletePushedEvents", "").Return(3, nil)
+	myMock.On("DeleteEventsCreatedBefore", "", mock.Anything).Return(2, nil)
+	myMock.On("DeleteEventsBeyondCount", "Thermostat", 10).Return(4, nil)
+	myMock.On("DeleteReadingsCreatedBefore", "Temperature", mock.Anything).Return(0, fmt.Errorf("some error"))
+	myMock.On("DeleteReadingsBeyondCount", "Temperature", 5).Return(6, nil)
+
+	dbClient = myMock
+	Configuration.Writable.Retention = RetentionInfo{
+		Default: RetentionPolicy{MaxAge: 1000, PurgePushed: true},
+		Devices: map[string]RetentionPolicy{
+			"Thermostat": {MaxCount: 10},
+		},
+		ValueDescriptors: map[string]RetentionPolicy{
+			"Temperature": {MaxAge: 1000, MaxCount: 5},
+		},
+	}
+
+	report := runRetention()
+
+	if report.Events != 9 {
+		t.Errorf("expected 9 events removed, reported %d", report.Events)
+	}
+	if report.Readings != 6 {
+		t.Errorf("expected 6 readings removed, reported %d", report.Readings)
+	}
+	if len(report.Purges) != 4 {
+		t.Errorf("expected 4 purges, reported %v", report.Purges)
+	}
+	if len(report.Errors) != 1 {
+		t.Errorf("expected 1 error, reported %v", report.Errors)
+	}
+	if getRetentionReport().Completed != report.Completed {
+		t.Errorf("the latest report should be kept")
+	}
+
+	myMock.AssertExpectations(t)
+}
+
+func TestRunRetentionNoPolicy(t *testing.T) {
+	reset()
+	myMock := &dbMock.DBClient{}
+	dbClient = myMock
+
+	report := runRetention()
+
+	if report.Events != 0 || report.Readings != 0 || len(report.Purges) != 0 {
+		t.Errorf("expected nothing removed, reported %v", report)
+	}
+	myMock.AssertExpectations(t)
+}
@@ -14,23 +14,35 @@
 package data
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"runtime"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/edgexfoundry/edgex-go/internal"
 	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/encoding"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
 	"github.com/edgexfoundry/edgex-go/pkg/clients"
 	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
 )
 
 func LoadRestRoutes() *mux.Router {
 	r := mux.NewRouter()
+	r.Use(metrics.Middleware)
 
 	// Ping Resource
 	r.HandleFunc(clients.ApiPingRoute, pingHandler).Methods(http.MethodGet)
@@ -40,10 +52,17 @@ func LoadRestRoutes() *mux.Router {
 
 	// Metrics
 	r.HandleFunc(clients.ApiMetricsRoute, metricsHandler).Methods(http.MethodGet)
+	r.HandleFunc(clients.ApiPrometheusRoute, metrics.Handler).Methods(http.MethodGet)
+
+	// Callback from metadata on device changes
+	r.HandleFunc(clients.ApiCallbackRoute, callbackHandler).Methods(http.MethodPost, http.MethodPut, http.MethodDelete)
 
 	// Events
 	r.HandleFunc(clients.ApiEventRoute, eventHandler).Methods(http.MethodGet, http.MethodPut, http.MethodPost)
 	e := r.PathPrefix(clients.ApiEventRoute).Subrouter()
+	e.HandleFunc("/batch", batchEventHandler).Methods(http.MethodPost)
+	e.HandleFunc("/stream/sse", eventStreamSSEHandler).Methods(http.MethodGet)
+	e.HandleFunc("/stream/ws", eventStreamWebSocketHandler).Methods(http.MethodGet)
 	e.HandleFunc("/scrub", scrubHandler).Methods(http.MethodDelete)
 	e.HandleFunc("/scruball", scrubAllHandler).Methods(http.MethodDelete)
 	e.HandleFunc("/count", eventCountHandler).Methods(http.MethodGet)
@@ -52,10 +71,25 @@ func LoadRestRoutes() *mux.Router {
 	e.HandleFunc("/id/{id}", eventIdHandler).Methods(http.MethodDelete, http.MethodPut)
 	e.HandleFunc("/device/{deviceId}/{limit:[0-9]+}", getEventByDeviceHandler).Methods(http.MethodGet)
 	e.HandleFunc("/device/{deviceId}", deleteByDeviceIdHandler).Methods(http.MethodDelete)
+	e.HandleFunc("/tag/{key}/{value}/{limit:[0-9]+}", getEventsByTagHandler).Methods(http.MethodGet)
 	e.HandleFunc("/removeold/age/{age:[0-9]+}", eventByAgeHandler).Methods(http.MethodDelete)
 	e.HandleFunc("/{start:[0-9]+}/{end:[0-9]+}/{limit:[0-9]+}", eventByCreationTimeHandler).Methods(http.MethodGet)
+	e.HandleFunc("/page/{start:[0-9]+}/{end:[0-9]+}/{limit:[0-9]+}", eventPageHandler).Methods(http.MethodGet)
+	e.HandleFunc("/export/{start:[0-9]+}/{end:[0-9]+}", eventExportHandler).Methods(http.MethodGet)
 	e.HandleFunc("/device/{deviceId}/valuedescriptor/{valueDescriptor}/{limit:[0-9]+}", readingByDeviceFilteredValueDescriptor).Methods(http.MethodGet)
 
+	// Retention
+	r.HandleFunc(clients.ApiRetentionRoute, retentionHandler).Methods(http.MethodGet, http.MethodPost)
+
+	// Downsampling
+	r.HandleFunc(clients.ApiDownsamplingRoute, downsamplingHandler).Methods(http.MethodGet, http.MethodPost)
+
+	// Event buffer
+	r.HandleFunc(clients.ApiBufferRoute, bufferHandler).Methods(http.MethodGet)
+
+	// Rate limits
+	r.HandleFunc(clients.ApiRateLimitRoute, rateLimitHandler).Methods(http.MethodGet)
+
 	// Readings
 	r.HandleFunc(clients.ApiReadingRoute, readingHandler).Methods(http.MethodGet, http.MethodPut, http.MethodPost)
 	rd := r.PathPrefix(clients.ApiReadingRoute).Subrouter()
@@ -68,7 +102,10 @@ func LoadRestRoutes() *mux.Router {
 	rd.HandleFunc("/label/{label}/{limit:[0-9]+}", readingByLabelHandler).Methods(http.MethodGet)
 	rd.HandleFunc("/type/{type}/{limit:[0-9]+}", readingByTypeHandler).Methods(http.MethodGet)
 	rd.HandleFunc("/{start:[0-9]+}/{end:[0-9]+}/{limit:[0-9]+}", readingByCreationTimeHandler).Methods(http.MethodGet)
+	rd.HandleFunc("/page/{start:[0-9]+}/{end:[0-9]+}/{limit:[0-9]+}", readingPageHandler).Methods(http.MethodGet)
 	rd.HandleFunc("/name/{name}/device/{device}/{limit:[0-9]+}", readingByValueDescriptorAndDeviceHandler).Methods(http.MethodGet)
+	rd.HandleFunc("/aggregate/name/{name}/{start:[0-9]+}/{end:[0-9]+}/{interval:[0-9]+}", readingAggregateHandler).Methods(http.MethodGet)
+	rd.HandleFunc("/aggregate/name/{name}/device/{device}/{start:[0-9]+}/{end:[0-9]+}/{interval:[0-9]+}", readingAggregateHandler).Methods(http.MethodGet)
 
 	// Value descriptors
 	r.HandleFunc(clients.ApiValueDescriptorRoute, valueDescriptorHandler).Methods(http.MethodGet, http.MethodPut, http.MethodPost)
@@ -195,8 +232,8 @@ func eventHandler(w http.ResponseWriter, r *http.Request) {
 		// Post a new event
 	case http.MethodPost:
 		var e models.Event
-		dec := json.NewDecoder(r.Body)
-		err := dec.Decode(&e)
+		contentType := encoding.ContentType(r.Header.Get(clients.ContentType))
+		err := encoding.Decode(r.Body, contentType, &e)
 
 		// Problem Decoding Event
 		if err != nil {
@@ -207,13 +244,22 @@ func eventHandler(w http.ResponseWriter, r *http.Request) {
 
 		LoggingClient.Info("Posting Event: " + e.String())
 
-		newId, err := addNewEvent(e)
+		newId, err := addNewEvent(e, r.Header.Get(clients.IdempotencyKey), contentType)
 		if err != nil {
 			switch t := err.(type) {
 			case *errors.ErrValueDescriptorNotFound:
 				http.Error(w, t.Error(), http.StatusBadRequest)
 			case *errors.ErrValueDescriptorInvalid:
 				http.Error(w, t.Error(), http.StatusBadRequest)
+			case *errors.ErrEventTagInvalid:
+				http.Error(w, t.Error(), http.StatusBadRequest)
+			case *errors.ErrEventBufferFull:
+				http.Error(w, t.Error(), http.StatusServiceUnavailable)
+			case *errors.ErrRateLimited:
+				w.Header().Set("Retry-After", retryAfterSeconds(t.RetryAfter()))
+				http.Error(w, t.Error(), http.StatusTooManyRequests)
+			case *errors.ErrTooManyReadings:
+				http.Error(w, t.Error(), http.StatusRequestEntityTooLarge)
 			default:
 				http.Error(w, t.Error(), http.StatusInternalServerError)
 			}
@@ -244,6 +290,8 @@ func eventHandler(w http.ResponseWriter, r *http.Request) {
 			switch t := err.(type) {
 			case *errors.ErrEventNotFound:
 				http.Error(w, t.Error(), http.StatusNotFound)
+			case *errors.ErrEventTagInvalid:
+				http.Error(w, t.Error(), http.StatusBadRequest)
 			default:
 				http.Error(w, t.Error(), http.StatusInternalServerError)
 			}
@@ -257,6 +305,179 @@ func eventHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+/*
+Handler for adding a batch of events
+The body is either a JSON array of events, a CBOR array of events with a Content-Type of
+application/cbor or, with a Content-Type of application/x-ndjson, one event per line. Each event is added independently and the response holds one result
+per event, in the order received, with the new id or the reason the event was rejected.
+Status code 200 - batch processed, see the per-event results
+Status code 400 - the body could not be decoded
+api/v1/event/batch
+*/
+func batchEventHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var events []models.Event
+	results := []eventResult{} // An empty batch is answered with an empty array
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(clients.ContentType))
+	if mediaType == clients.ContentNdjson {
+		// Lines that fail to decode are reported individually, the rest of the batch is still added
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 64*1024), maxBatchLineSize)
+		var positions []int
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+
+			var e models.Event
+			if err := json.Unmarshal(line, &e); err != nil {
+				LoggingClient.Error("Error decoding event: " + err.Error())
+				results = append(results, eventResult{StatusCode: http.StatusBadRequest, Error: err.Error()})
+				continue
+			}
+			positions = append(positions, len(results))
+			results = append(results, eventResult{})
+			events = append(events, e)
+		}
+		if err := scanner.Err(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			LoggingClient.Error("Error reading event batch: " + err.Error())
+			return
+		}
+
+		for i, result := range addNewEvents(events, clients.ContentJson) {
+			results[positions[i]] = result
+		}
+	} else {
+		contentType := encoding.ContentType(mediaType)
+		err := encoding.Decode(r.Body, contentType, &events)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			LoggingClient.Error("Error decoding event batch: " + err.Error())
+			return
+		}
+
+		results = addNewEvents(events, contentType)
+	}
+
+	LoggingClient.Info(fmt.Sprintf("Posted batch of %d events", len(results)))
+	encode(results, w)
+}
+
+/*
+Handler streaming the events accepted by core data as Server-Sent Events
+Each event is sent as JSON in the data of an SSE "event" message whose id is the event id
+?device= - only stream the events of the device
+?reading= - only stream the readings with the name, may be repeated or a comma separated list
+api/v1/event/stream/sse
+*/
+func eventStreamSSEHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		LoggingClient.Error("Event stream requested on a connection that cannot be flushed")
+		return
+	}
+
+	s := streams.subscribe(newStreamFilter(r.URL.Query().Get("device"), r.URL.Query()["reading"]))
+	defer streams.unsubscribe(s)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			// Comment lines keep proxies from closing an idle connection
+			if _, err := w.Write([]byte(": keep-alive\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e, ok := <-s.events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				LoggingClient.Error("Error encoding the event for the stream: " + err.Error())
+				continue
+			}
+			if _, err = fmt.Fprintf(w, "id: %s\nevent: event\ndata: %s\n\n", e.ID, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+var streamUpgrader = websocket.Upgrader{
+	// The stream is read only and meant for browser dashboards served from other origins
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+/*
+Handler streaming the events accepted by core data over a WebSocket
+Each event is sent as a JSON text message, messages from the client are ignored
+?device= - only stream the events of the device
+?reading= - only stream the readings with the name, may be repeated or a comma separated list
+api/v1/event/stream/ws
+*/
+func eventStreamWebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already replied to the client
+		LoggingClient.Error("Error opening the event WebSocket: " + err.Error())
+		return
+	}
+	defer conn.Close()
+
+	s := streams.subscribe(newStreamFilter(r.URL.Query().Get("device"), r.URL.Query()["reading"]))
+	defer streams.unsubscribe(s)
+
+	// Read until the client goes away so that control messages are handled
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-keepAlive.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout))
+		case e, ok := <-s.events:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(streamWriteTimeout))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			err = conn.WriteJSON(e)
+		}
+		if err != nil {
+			LoggingClient.Debug("Closing the event WebSocket: " + err.Error())
+			return
+		}
+	}
+}
+
 // Undocumented feature to remove all readings and events from the database
 // This should primarily be used for debugging purposes
 func scrubAllHandler(w http.ResponseWriter, r *http.Request) {
@@ -304,6 +525,55 @@ func getEventByIdHandler(w http.ResponseWriter, r *http.Request) {
 	encode(e, w)
 }
 
+// Get events by tag
+// Returns the events holding the tag with the value, limited by 'limit'
+// {key} - the key of the tag
+// {value} - the value of the tag
+// {limit} - the limit of events
+// 413 - limit exceeds the max limit
+// api/v1/event/tag/{key}/{value}/{limit}
+func getEventsByTagHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	vars := mux.Vars(r)
+	key, err := url.QueryUnescape(vars["key"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error("Error unescaping the tag key: " + err.Error())
+		return
+	}
+	value, err := url.QueryUnescape(vars["value"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error("Error unescaping the tag value: " + err.Error())
+		return
+	}
+	if err = validateTags(map[string]string{key: value}); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error(err.Error())
+		return
+	}
+
+	limit, err := strconv.Atoi(vars["limit"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error("Error converting the limit to an integer: " + err.Error())
+		return
+	}
+	if err = checkMaxLimit(limit); err != nil {
+		http.Error(w, maxExceededString, http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	events, err := getEventsByTag(limit, key, value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	encode(events, w)
+}
+
 // Get event by device id
 // Returns the events for the given device sorted by creation date and limited by 'limit'
 // {deviceId} - the device that the events are for
@@ -461,6 +731,184 @@ func deleteByDeviceIdHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Get a page of events by creation time
+// {start} - start time, {end} - end time, {limit} - max number of results in the page
+// ?cursor= - the next cursor of the previous page, omitted for the first page
+// Sort the events by creation date, the page holds the cursor of the next page
+// 400 - invalid cursor
+// 413 - limit exceeds the max limit
+// 500 - unanticipated issues
+// api/v1/event/page/{start}/{end}/{limit}
+func eventPageHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	start, end, limit, err := parsePageVars(mux.Vars(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error(err.Error())
+		return
+	}
+	if err = checkMaxLimit(limit); err != nil {
+		http.Error(w, maxExceededString, http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	page, err := getEventsPage(start, end, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		if err == db.ErrInvalidCursor {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	encode(page, w)
+}
+
+// Export the events created between the start and end times, without any limit
+// Query parameters:
+// device - only export the events of the devices, may be repeated or a comma separated list
+// reading - only export the readings with the names, may be repeated or a comma separated list
+// format - csv or ndjson (default)
+// view - events (default) or readings, one record per reading
+// The export is gzip compressed when the request accepts the gzip encoding
+// api/v1/event/export/{start}/{end}
+func eventExportHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	start, end, err := parseTimeRangeVars(mux.Vars(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error(err.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	req, err := newExportRequest(start, end, q["device"], q["reading"], q.Get("format"), q.Get("view"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error(err.Error())
+		return
+	}
+
+	out := &exportResponse{w: w}
+	count, err := exportEventsPaged(req, func() (io.Writer, error) {
+		w.Header().Set("Content-Type", req.contentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=events-%d-%d.%s", start, end, req.format))
+		if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			w.Header().Set("Content-Encoding", "gzip")
+			out.gz = gzip.NewWriter(w)
+		}
+		w.WriteHeader(http.StatusOK)
+		out.started = true
+		return out, nil
+	})
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error exporting events after %d events: %s", count, err.Error()))
+		// The status can only be reported when the first page could not be read
+		if !out.started {
+			status := http.StatusInternalServerError
+			if err == db.ErrInvalidCursor {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+		}
+		return
+	}
+	LoggingClient.Debug(fmt.Sprintf("Exported %d events", count))
+}
+
+// Body of an export, gzip compressed or not, sent to the client each time it is flushed
+// Whether the Accept-Encoding header lists gzip, or any encoding, with a quality other than 0
+func acceptsGzip(header string) bool {
+	accepted := false
+	for _, coding := range strings.Split(header, ",") {
+		params := strings.Split(coding, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		if name != "gzip" && name != "x-gzip" && name != "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) == 2 && strings.ToLower(strings.TrimSpace(kv[0])) == "q" {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if name != "*" {
+			// An explicit gzip coding overrides the wildcard
+			return q > 0
+		}
+		accepted = q > 0
+	}
+	return accepted
+}
+
+type exportResponse struct {
+	w       http.ResponseWriter
+	gz      *gzip.Writer
+	started bool
+}
+
+func (e *exportResponse) Write(p []byte) (int, error) {
+	if e.gz != nil {
+		return e.gz.Write(p)
+	}
+	return e.w.Write(p)
+}
+
+func (e *exportResponse) Flush() error {
+	if e.gz != nil {
+		if err := e.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func (e *exportResponse) Close() error {
+	if e.gz != nil {
+		return e.gz.Close()
+	}
+	return nil
+}
+
+// Parse the start and end times and the limit of a page request
+func parsePageVars(vars map[string]string) (start int64, end int64, limit int, err error) {
+	start, end, err = parseTimeRangeVars(vars)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	limit, err = strconv.Atoi(vars["limit"])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Error converting the limit to an integer: %v", err)
+	}
+	return start, end, limit, nil
+}
+
+// Parse the start and end times of a request
+func parseTimeRangeVars(vars map[string]string) (start int64, end int64, err error) {
+	start, err = strconv.ParseInt(vars["start"], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Error converting the start time to an integer: %v", err)
+	}
+	end, err = strconv.ParseInt(vars["end"], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Error converting the end time to an integer: %v", err)
+	}
+	return start, end, nil
+}
+
 // Get events by creation time
 // {start} - start time, {end} - end time, {limit} - max number of results
 // Sort the events by creation date
@@ -599,6 +1047,84 @@ func scrubHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Callback handler
+// Metadata calls back when a device, or the profile, device service or addressable of devices, changes
+// The affected devices are removed from the device cache so that they are fetched again
+// api/v1/callback
+func callbackHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var alert models.CallbackAlert
+	if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+		LoggingClient.Error("Error decoding the callback alert: " + err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	removed := devices.invalidate(alert)
+	LoggingClient.Debug(fmt.Sprintf("Callback for %s %s removed %d cached devices", alert.ActionType, alert.Id, removed))
+	w.WriteHeader(http.StatusOK)
+}
+
+// Retention handler
+// GET the report of the latest retention pass
+// POST to run a retention pass now and get its report
+// api/v1/retention
+func retentionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		encode(getRetentionReport(), w)
+	case http.MethodPost:
+		LoggingClient.Info("Running retention on request")
+		encode(runRetention(), w)
+	}
+}
+
+// Downsampling handler
+// GET the downsampling rules and the report of the latest downsampling pass
+// POST to run a downsampling pass now and get its report
+// api/v1/downsampling
+func downsamplingHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		encode(getDownsamplingState(), w)
+	case http.MethodPost:
+		LoggingClient.Info("Running downsampling on request")
+		encode(runDownsampling(), w)
+	}
+}
+
+// Rate limit handler
+// GET the number of events of each device accepted, throttled by the rate limits and rejected for
+// holding too many readings
+// api/v1/ratelimit
+func rateLimitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+
+	encode(limiter.stats(), w)
+}
+
+// Event buffer handler
+// GET the depth and counters of the buffer of the events accepted while the database is unavailable
+// api/v1/buffer
+func bufferHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+
+	encode(getBufferStats(), w)
+}
+
 // Test if the service is working
 func pingHandler(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "text/plain")
@@ -904,19 +1430,7 @@ func readingByUomLabelHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the value descriptors
-	vList, err := getValueDescriptorsByUomLabel(uomLabel)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	var vNames []string
-	for _, v := range vList {
-		vNames = append(vNames, v.Name)
-	}
-
-	readings, err := getReadingsByValueDescriptorNames(vNames, limit)
+	readings, err := getReadingsByUomLabel(uomLabel, limit)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -954,18 +1468,7 @@ func readingByLabelHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the value descriptors
-	vdList, err := getValueDescriptorsByLabel(label)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	var vdNames []string
-	for _, vd := range vdList {
-		vdNames = append(vdNames, vd.Name)
-	}
-
-	readings, err := getReadingsByValueDescriptorNames(vdNames, limit)
+	readings, err := getReadingsByLabel(label, limit)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1003,22 +1506,9 @@ func readingByTypeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the value descriptors
-	vdList, err := getValueDescriptorsByType(t)
+	readings, err := getReadingsByType(t, limit)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
-		LoggingClient.Error(err.Error())
-		return
-	}
-	var vdNames []string
-	for _, vd := range vdList {
-		vdNames = append(vdNames, vd.Name)
-	}
-
-	readings, err := getReadingsByValueDescriptorNames(vdNames, limit)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		LoggingClient.Error(err.Error())
 		return
 	}
 
@@ -1068,6 +1558,116 @@ func readingByCreationTimeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Return min/max/avg/count/first/last of the numeric readings for the value descriptor,
+// grouped per device into buckets of interval milliseconds between start and end
+// 400 - the interval is 0 or the end is not after the start
+// 413 - the number of buckets or readings exceeds the max limit
+// api/v1/reading/aggregate/name/{name}/{start}/{end}/{interval}
+// api/v1/reading/aggregate/name/{name}/device/{device}/{start}/{end}/{interval}
+func readingAggregateHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	vars := mux.Vars(r)
+
+	name, err := url.QueryUnescape(vars["name"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error("Error unescaping the value descriptor name: " + err.Error())
+		return
+	}
+	device, err := url.QueryUnescape(vars["device"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error("Error unescaping the device: " + err.Error())
+		return
+	}
+	start, err := strconv.ParseInt(vars["start"], 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error("Error converting the start time to an integer: " + err.Error())
+		return
+	}
+	end, err := strconv.ParseInt(vars["end"], 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error("Error converting the end time to an integer: " + err.Error())
+		return
+	}
+	interval, err := strconv.ParseInt(vars["interval"], 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error("Error converting the interval to an integer: " + err.Error())
+		return
+	}
+	if interval <= 0 || end <= start {
+		http.Error(w, "The interval must be positive and the end after the start", http.StatusBadRequest)
+		LoggingClient.Error(fmt.Sprintf("Invalid aggregation range %d-%d with interval %d", start, end, interval))
+		return
+	}
+
+	// Check device
+	if device != "" {
+		if err := checkDevice(device); err != nil {
+			LoggingClient.Error(fmt.Sprintf("error checking device %s %v", device, err))
+			switch err := err.(type) {
+			case *types.ErrServiceClient:
+				http.Error(w, err.Error(), err.StatusCode)
+				return
+			default: //return an error on everything else.
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+		}
+	}
+
+	aggregates, err := getReadingAggregates(name, device, start, end, interval)
+	if err != nil {
+		switch err.(type) {
+		case *errors.ErrLimitExceeded:
+			http.Error(w, maxExceededString, http.StatusRequestEntityTooLarge)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	encode(aggregates, w)
+}
+
+// Get a page of readings by creation time
+// {start} - start time, {end} - end time, {limit} - max number of results in the page
+// ?cursor= - the next cursor of the previous page, omitted for the first page
+// 400 - invalid cursor
+// 413 - limit exceeds the max limit
+// 500 - unanticipated issues
+// api/v1/reading/page/{start}/{end}/{limit}
+func readingPageHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	start, end, limit, err := parsePageVars(mux.Vars(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		LoggingClient.Error(err.Error())
+		return
+	}
+	if err = checkMaxLimit(limit); err != nil {
+		http.Error(w, maxExceededString, http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	page, err := getReadingsPage(start, end, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if err == db.ErrInvalidCursor {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	encode(page, w)
+}
+
 // Return a list of redings associated with the device and value descriptor
 // Limit exceeded exception 413 if the limit exceeds the max limit
 // api/v1/reading/name/{name}/device/{device}/{limit}
@@ -1186,6 +1786,9 @@ func valueDescriptorHandler(w http.ResponseWriter, r *http.Request) {
 			case *errors.ErrValueDescriptorInUse:
 				http.Error(w, err.Error(), http.StatusConflict)
 				return
+			case *errors.ErrValueDescriptorInvalid:
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
 			default:
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
@@ -0,0 +1,151 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const (
+	// Number of events buffered for a stream subscriber before events are dropped for it
+	streamBufferSize = 100
+	// Time between keep-alive messages on an idle stream
+	streamKeepAlive = 15 * time.Second
+	// Time allowed to write a message to a WebSocket
+	streamWriteTimeout = 10 * time.Second
+)
+
+// Selects the events sent to a stream subscriber. Empty fields match everything.
+type streamFilter struct {
+	device   string
+	readings map[string]bool
+}
+
+// Build a filter from the device and reading names of a stream request.
+// Reading names may be repeated or given as a comma separated list.
+func newStreamFilter(device string, readings []string) streamFilter {
+	return streamFilter{device: device, readings: splitNames(readings)}
+}
+
+// Collect the names of query parameters which may be repeated or given as comma separated lists.
+// Return nil when there are no names.
+func splitNames(values []string) map[string]bool {
+	var names map[string]bool
+	for _, v := range values {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			if names == nil {
+				names = make(map[string]bool)
+			}
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// Apply the filter to the event. When reading names are filtered, only the matching readings are kept
+// and events without any of them are rejected.
+func (f streamFilter) apply(e contract.Event) (contract.Event, bool) {
+	if f.device != "" && e.Device != f.device {
+		return e, false
+	}
+	if f.readings == nil {
+		return e, true
+	}
+
+	readings := []contract.Reading{}
+	for _, r := range e.Readings {
+		if f.readings[r.Name] {
+			readings = append(readings, r)
+		}
+	}
+	if len(readings) == 0 {
+		return e, false
+	}
+	e.Readings = readings
+	return e, true
+}
+
+// A live stream of events, fed by the event broadcaster
+type eventStream struct {
+	filter  streamFilter
+	events  chan contract.Event
+	dropped int
+}
+
+// Fans accepted events out to the live streams
+type eventBroadcaster struct {
+	mutex   sync.Mutex
+	streams map[*eventStream]bool
+}
+
+var streams = &eventBroadcaster{streams: make(map[*eventStream]bool)}
+
+// Open a stream receiving the events that match the filter
+func (b *eventBroadcaster) subscribe(f streamFilter) *eventStream {
+	s := &eventStream{filter: f, events: make(chan contract.Event, streamBufferSize)}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.streams[s] = true
+	return s
+}
+
+// Close a stream, its events channel is closed once it no longer receives events
+func (b *eventBroadcaster) unsubscribe(s *eventStream) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.streams[s] {
+		delete(b.streams, s)
+		close(s.events)
+	}
+}
+
+// Send the event to every stream it matches. A stream that does not keep up misses events
+// rather than slowing down ingestion.
+func (b *eventBroadcaster) publish(e contract.Event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for s := range b.streams {
+		filtered, ok := s.filter.apply(e)
+		if !ok {
+			continue
+		}
+		select {
+		case s.events <- filtered:
+		default:
+			s.dropped++
+			if s.dropped == 1 || s.dropped%streamBufferSize == 0 {
+				LoggingClient.Warn("Event stream is not keeping up, events dropped: " + strconv.Itoa(s.dropped))
+			}
+		}
+	}
+}
+
+// Close every stream, ending their requests
+func (b *eventBroadcaster) closeAll() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for s := range b.streams {
+		delete(b.streams, s)
+		close(s.events)
+	}
+}
@@ -0,0 +1,179 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/gorilla/websocket"
+)
+
+func TestStreamFilter(t *testing.T) {
+	reset()
+	tests := []struct {
+		name     string
+		device   string
+		readings []string
+		match    bool
+		count    int
+	}{
+		{"No filter", "", nil, true, 2},
+		{"Device", testDeviceName, nil, true, 2},
+		{"Other device", "Other", nil, false, 0},
+		{"Reading", "", []string{"Temperature"}, true, 1},
+		{"Reading list", testDeviceName, []string{"Temperature, Pressure"}, true, 2},
+		{"Repeated reading", "", []string{"Temperature", "Pressure"}, true, 2},
+		{"Other reading", "", []string{"Humidity"}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := newStreamFilter(tt.device, tt.readings).apply(testEvent)
+			if ok != tt.match {
+				t.Fatalf("expected match %v, got %v", tt.match, ok)
+			}
+			if ok && len(e.Readings) != tt.count {
+				t.Errorf("expected %d readings, got %d", tt.count, len(e.Readings))
+			}
+		})
+	}
+	if len(testEvent.Readings) != 2 {
+		t.Errorf("filtering modified the original event")
+	}
+}
+
+func TestEventBroadcaster(t *testing.T) {
+	reset()
+	b := &eventBroadcaster{streams: make(map[*eventStream]bool)}
+	all := b.subscribe(newStreamFilter("", nil))
+	other := b.subscribe(newStreamFilter("Other", nil))
+
+	for i := 0; i < streamBufferSize+5; i++ {
+		b.publish(testEvent)
+	}
+
+	if len(all.events) != streamBufferSize {
+		t.Errorf("expected %d buffered events, got %d", streamBufferSize, len(all.events))
+	}
+	if all.dropped != 5 {
+		t.Errorf("expected 5 dropped events, got %d", all.dropped)
+	}
+	if len(other.events) != 0 {
+		t.Errorf("expected no events for the other device, got %d", len(other.events))
+	}
+
+	b.unsubscribe(all)
+	b.unsubscribe(all)
+	b.closeAll()
+	if _, ok := <-other.events; ok {
+		t.Errorf("expected the stream to be closed")
+	}
+	if len(b.streams) != 0 {
+		t.Errorf("expected no streams, got %d", len(b.streams))
+	}
+}
+
+// Wait for the stream handlers to subscribe before publishing
+func waitForStreams(t *testing.T, count int) {
+	for i := 0; i < 100; i++ {
+		streams.mutex.Lock()
+		n := len(streams.streams)
+		streams.mutex.Unlock()
+		if n == count {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d streams", count)
+}
+
+func TestEventStreamSSEHandler(t *testing.T) {
+	reset()
+	server := httptest.NewServer(testRoutes)
+	defer server.Close()
+
+	rsp, err := http.Get(server.URL + "/api/v1/event/stream/sse?reading=Pressure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rsp.StatusCode)
+	}
+	if ct := rsp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("unexpected content type %s", ct)
+	}
+
+	waitForStreams(t, 1)
+	streams.publish(testEvent)
+
+	reader := bufio.NewReader(rsp.Body)
+	lines := []string{}
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	if lines[0] != "id: "+testEvent.ID || lines[1] != "event: event" {
+		t.Errorf("unexpected message %v", lines)
+	}
+	var e models.Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &e); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Readings) != 1 || e.Readings[0].Name != "Pressure" {
+		t.Errorf("unexpected readings %v", e.Readings)
+	}
+
+	streams.closeAll()
+}
+
+func TestEventStreamWebSocketHandler(t *testing.T) {
+	reset()
+	server := httptest.NewServer(testRoutes)
+	defer server.Close()
+
+	query := url.Values{"device": {testDeviceName}}.Encode()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v1/event/stream/ws?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	waitForStreams(t, 1)
+	streams.publish(models.Event{Device: "Other"})
+	streams.publish(testEvent)
+
+	var e models.Event
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.ReadJSON(&e); err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != testEvent.ID || len(e.Readings) != 2 {
+		t.Errorf("unexpected event %v", e)
+	}
+
+	conn.Close()
+	waitForStreams(t, 0)
+}
@@ -0,0 +1,171 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package data
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Apply the property value transforms of the device profile to the readings of the event when enabled.
+// Readings without a matching device resource are left untouched, readings failing the assertion of
+// their resource are flagged.
+func transformReadings(e contract.Event) (contract.Event, error) {
+	if !Configuration.Writable.TransformReadings {
+		return e, nil
+	}
+
+	device, err := getDevice(e.Device)
+	if err != nil {
+		return e, err
+	}
+	properties := make(map[string]contract.PropertyValue)
+	for _, resource := range device.Profile.DeviceResources {
+		properties[resource.Name] = resource.Properties.Value
+	}
+
+	readings := make([]contract.Reading, len(e.Readings))
+	for i, reading := range e.Readings {
+		pv, ok := properties[reading.Name]
+		if ok {
+			reading.Value, err = transformValue(reading.Value, pv)
+			if err != nil {
+				return e, errors.NewErrValueDescriptorInvalid(reading.Name, err)
+			}
+			if !assertionHolds(reading.Value, pv.Assertion) {
+				LoggingClient.Warn(fmt.Sprintf("Reading %s of device %s failed the assertion %s with the value %s", reading.Name, e.Device, pv.Assertion, reading.Value))
+				reading.AssertionFailed = true
+			}
+		}
+		readings[i] = reading
+	}
+	e.Readings = readings
+	return e, nil
+}
+
+// Transform a raw value as described by the property value. The transforms are applied in order:
+// mask, shift (left when positive, right when negative), base (base ^ value), scale, offset.
+// Mask and shift require an integer value, the other transforms any number.
+// Precision sets the number of decimals of the transformed value.
+func transformValue(value string, pv contract.PropertyValue) (string, error) {
+	var err error
+	if isTransform(pv.Mask, "0") || isTransform(pv.Shift, "0") {
+		if value, err = transformBits(value, pv); err != nil {
+			return value, err
+		}
+	}
+	if !isTransform(pv.Base, "0") && !isTransform(pv.Scale, "1") && !isTransform(pv.Offset, "0") && pv.Precision == "" {
+		return value, nil
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value, err
+	}
+	if isTransform(pv.Base, "0") {
+		base, err := strconv.ParseFloat(pv.Base, 64)
+		if err != nil {
+			return value, fmt.Errorf("invalid base %s", pv.Base)
+		}
+		v = math.Pow(base, v)
+	}
+	if isTransform(pv.Scale, "1") {
+		scale, err := strconv.ParseFloat(pv.Scale, 64)
+		if err != nil {
+			return value, fmt.Errorf("invalid scale %s", pv.Scale)
+		}
+		v *= scale
+	}
+	if isTransform(pv.Offset, "0") {
+		offset, err := strconv.ParseFloat(pv.Offset, 64)
+		if err != nil {
+			return value, fmt.Errorf("invalid offset %s", pv.Offset)
+		}
+		v += offset
+	}
+
+	precision := -1
+	if pv.Precision != "" {
+		if precision, err = strconv.Atoi(pv.Precision); err != nil || precision < 0 {
+			return value, fmt.Errorf("invalid precision %s", pv.Precision)
+		}
+	}
+	return strconv.FormatFloat(v, 'f', precision, 64), nil
+}
+
+// Apply the mask and shift to an integer value, signed or not as the property says
+func transformBits(value string, pv contract.PropertyValue) (string, error) {
+	var mask uint64 = math.MaxUint64
+	var shift int64
+	var err error
+	if isTransform(pv.Mask, "0") {
+		if mask, err = strconv.ParseUint(pv.Mask, 0, 64); err != nil {
+			return value, fmt.Errorf("invalid mask %s", pv.Mask)
+		}
+	}
+	if isTransform(pv.Shift, "0") {
+		if shift, err = strconv.ParseInt(pv.Shift, 10, 8); err != nil || shift < -63 || shift > 63 {
+			return value, fmt.Errorf("invalid shift %s", pv.Shift)
+		}
+	}
+
+	if pv.Signed {
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return value, err
+		}
+		v &= int64(mask)
+		if shift > 0 {
+			v <<= uint(shift)
+		} else {
+			v >>= uint(-shift)
+		}
+		return strconv.FormatInt(v, 10), nil
+	}
+
+	v, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return value, err
+	}
+	v &= mask
+	if shift > 0 {
+		v <<= uint(shift)
+	} else {
+		v >>= uint(-shift)
+	}
+	return strconv.FormatUint(v, 10), nil
+}
+
+// Whether the property sets a transform, rather than leaving it empty or at its neutral value
+func isTransform(property string, neutral string) bool {
+	return property != "" && property != neutral
+}
+
+// Whether the value satisfies the assertion, an empty assertion always holds.
+// Numbers are compared by value so that 1.0 satisfies the assertion 1.
+func assertionHolds(value string, assertion string) bool {
+	if assertion == "" || value == assertion {
+		return true
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	a, err := strconv.ParseFloat(assertion, 64)
+	return err == nil && v == a
+}
@@ -0,0 +1,142 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package data
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/metadata/mocks"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransformValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		pv       models.PropertyValue
+		expected string
+		err      bool
+	}{
+		{"No transform", "45", models.PropertyValue{}, "45", false},
+		{"Neutral transforms", "abc", models.PropertyValue{Mask: "0", Shift: "0", Base: "0", Scale: "1", Offset: "0"}, "abc", false},
+		{"Mask", "255", models.PropertyValue{Mask: "0x0F"}, "15", false},
+		{"Mask and right shift", "171", models.PropertyValue{Mask: "0xF0", Shift: "-4"}, "10", false},
+		{"Left shift", "3", models.PropertyValue{Shift: "2"}, "12", false},
+		{"Signed shift", "-16", models.PropertyValue{Shift: "-2", Signed: true}, "-4", false},
+		{"Base", "3", models.PropertyValue{Base: "2"}, "8", false},
+		{"Scale and offset", "20", models.PropertyValue{Scale: "0.5", Offset: "-3"}, "7", false},
+		{"All", "1027", models.PropertyValue{Mask: "0xFF", Shift: "-1", Base: "2", Scale: "10", Offset: "1"}, "21", false},
+		{"Precision", "1", models.PropertyValue{Scale: "0.333333", Precision: "2"}, "0.33", false},
+		{"Mask on float", "1.5", models.PropertyValue{Mask: "0xFF"}, "", true},
+		{"Scale on string", "abc", models.PropertyValue{Scale: "2"}, "", true},
+		{"Invalid mask", "1", models.PropertyValue{Mask: "zz"}, "", true},
+		{"Invalid shift", "1", models.PropertyValue{Shift: "64"}, "", true},
+		{"Invalid precision", "1", models.PropertyValue{Precision: "-1"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := transformValue(tt.value, tt.pv)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected an error, got %s", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if value != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestAssertionHolds(t *testing.T) {
+	tests := []struct {
+		value     string
+		assertion string
+		expected  bool
+	}{
+		{"45", "", true},
+		{"true", "true", true},
+		{"1.0", "1", true},
+		{"2", "1", false},
+		{"false", "true", false},
+	}
+	for _, tt := range tests {
+		if holds := assertionHolds(tt.value, tt.assertion); holds != tt.expected {
+			t.Errorf("value %s with assertion %s: expected %v, got %v", tt.value, tt.assertion, tt.expected, holds)
+		}
+	}
+}
+
+func newTransformMockDeviceClient() *mocks.DeviceClient {
+	client := &mocks.DeviceClient{}
+	device := models.Device{Name: testDeviceName}
+	device.Profile.DeviceResources = []models.DeviceObject{
+		{Name: "Temperature", Properties: models.ProfileProperty{Value: models.PropertyValue{Scale: "0.1", Assertion: "4.5"}}},
+		{Name: "Pressure", Properties: models.ProfileProperty{Value: models.PropertyValue{Offset: "1", Assertion: "1"}}},
+		{Name: "Humidity", Properties: models.ProfileProperty{Value: models.PropertyValue{Mask: "0xFF"}}},
+	}
+	client.On("CheckForDevice", testDeviceName).Return(device, nil)
+	client.On("CheckForDevice", mock.Anything).Return(models.Device{}, types.NewErrServiceClient(http.StatusNotFound, []byte{}))
+	return client
+}
+
+func TestTransformReadings(t *testing.T) {
+	reset()
+	defer func() { mdc = newMockDeviceClient() }()
+	mdc = newTransformMockDeviceClient()
+
+	// Disabled by default
+	e, err := transformReadings(testEvent)
+	if err != nil || e.Readings[0].Value != "45" {
+		t.Fatalf("expected the readings to be left untouched, got %v %v", e.Readings, err)
+	}
+
+	Configuration.Writable.TransformReadings = true
+	e, err = transformReadings(testEvent)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if e.Readings[0].Value != "4.5" || e.Readings[0].AssertionFailed {
+		t.Errorf("unexpected temperature %v", e.Readings[0])
+	}
+	if e.Readings[1].Value != "2.01325" || !e.Readings[1].AssertionFailed {
+		t.Errorf("unexpected pressure %v", e.Readings[1])
+	}
+	if testEvent.Readings[0].Value != "45" {
+		t.Errorf("transforming modified the original event")
+	}
+
+	invalid := testEvent
+	invalid.Readings = []models.Reading{{Name: "Humidity", Value: "12.5"}}
+	if _, err = transformReadings(invalid); err == nil {
+		t.Errorf("expected an error transforming a float with a mask")
+	} else if _, ok := err.(*errors.ErrValueDescriptorInvalid); !ok {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	unknown := testEvent
+	unknown.Device = "Unknown"
+	if _, err = transformReadings(unknown); err == nil {
+		t.Errorf("expected an error for an unknown device")
+	}
+}
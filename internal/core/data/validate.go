@@ -8,9 +8,11 @@
 package data
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"unicode/utf8"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
 	models "github.com/edgexfoundry/edgex-go/pkg/models"
@@ -19,16 +21,27 @@ import (
 func isValidValueDescriptor(vd models.ValueDescriptor, reading models.Reading) error {
 	var err error
 	switch vd.Type {
-	case "B": // boolean
+	case models.ValueTypeBool:
 		err = validBoolean(reading)
-	case "F": // floating point
+	case models.ValueTypeFloat:
 		err = validFloat(reading, vd)
-	case "I": // integer
+	case models.ValueTypeInteger, models.ValueTypeInteger8, models.ValueTypeInteger16, models.ValueTypeInteger32, models.ValueTypeInteger64:
 		err = validInteger(reading, vd)
-	case "S": // string or character data
+	case models.ValueTypeUnsigned, models.ValueTypeUnsigned8, models.ValueTypeUnsigned16, models.ValueTypeUnsigned32, models.ValueTypeUnsigned64:
+		err = validUnsigned(reading, vd)
+	case models.ValueTypeString:
 		err = validString(reading)
-	case "J": // JSON data
+		if err == nil {
+			err = checkLimits(vd, compareLength(utf8.RuneCountInString(reading.Value)))
+		}
+	case models.ValueTypeJSON:
 		err = validJSON(reading)
+	case models.ValueTypeBinary:
+		err = validBinary(reading, vd)
+	case models.ValueTypeArray:
+		err = validArray(reading, vd)
+	case models.ValueTypeEnumeration:
+		err = validEnumeration(reading, vd)
 	default:
 		err = fmt.Errorf("Unknown type")
 	}
@@ -49,55 +62,114 @@ func validFloat(reading models.Reading, vd models.ValueDescriptor) error {
 		return err
 	}
 
-	if (vd.Max != nil) && (vd.Max != "") {
-		max, err := strconv.ParseFloat(vd.Max.(string), 64)
+	return checkLimits(vd, compareFloat(value))
+}
+
+// Integers are 64 bit wide unless the type of the value descriptor sets the width
+func validInteger(reading models.Reading, vd models.ValueDescriptor) error {
+	bitSize := typeBitSize(vd.Type)
+	value, err := strconv.ParseInt(reading.Value, 10, bitSize)
+	if err != nil {
+		return err
+	}
+
+	return checkLimits(vd, func(limit string) (int, error) {
+		l, err := strconv.ParseInt(limit, 10, bitSize)
 		if err != nil {
-			return err
+			return 0, err
 		}
+		return compare(value > l, value < l), nil
+	})
+}
 
-		if value > max {
-			return fmt.Errorf("Value is over the limits")
-		}
+// Unsigned integers are 64 bit wide unless the type of the value descriptor sets the width
+func validUnsigned(reading models.Reading, vd models.ValueDescriptor) error {
+	bitSize := typeBitSize(vd.Type)
+	value, err := strconv.ParseUint(reading.Value, 10, bitSize)
+	if err != nil {
+		return err
 	}
 
-	if (vd.Min != nil) && (vd.Min != "") {
-		min, err := strconv.ParseFloat(vd.Min.(string), 64)
+	return checkLimits(vd, func(limit string) (int, error) {
+		l, err := strconv.ParseUint(limit, 10, bitSize)
 		if err != nil {
-			return err
+			return 0, err
 		}
+		return compare(value > l, value < l), nil
+	})
+}
 
-		if value < min {
-			return fmt.Errorf("Value is under the limits")
-		}
+func validString(reading models.Reading) error {
+	if reading.Value == "" {
+		return fmt.Errorf("Value is empty")
 	}
 
 	return nil
 }
 
-func validInteger(reading models.Reading, vd models.ValueDescriptor) error {
-	value, err := strconv.ParseInt(reading.Value, 10, 64)
+func validJSON(reading models.Reading) error {
+	var js interface{}
+	return json.Unmarshal([]byte(reading.Value), &js)
+}
+
+// The limits of binary data apply to the number of bytes once decoded, or of the raw bytes when given
+func validBinary(reading models.Reading, vd models.ValueDescriptor) error {
+	if len(reading.BinaryValue) > 0 {
+		return checkLimits(vd, compareLength(len(reading.BinaryValue)))
+	}
+
+	data, err := base64.StdEncoding.DecodeString(reading.Value)
 	if err != nil {
 		return err
 	}
 
-	if (vd.Max != nil) && (vd.Max != "") {
-		max, err := strconv.ParseInt(vd.Max.(string), 10, 64)
-		if err != nil {
+	return checkLimits(vd, compareLength(len(data)))
+}
+
+// The limits of arrays apply to each of their numbers
+func validArray(reading models.Reading, vd models.ValueDescriptor) error {
+	var values []float64
+	if err := json.Unmarshal([]byte(reading.Value), &values); err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		if err := checkLimits(vd, compareFloat(value)); err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		if value > max {
-			return fmt.Errorf("Value is over the limits")
+func validEnumeration(reading models.Reading, vd models.ValueDescriptor) error {
+	for _, allowed := range vd.AllowedValues {
+		if reading.Value == allowed {
+			return nil
 		}
 	}
 
-	if (vd.Min != nil) && (vd.Min != "") {
-		min, err := strconv.ParseInt(vd.Min.(string), 10, 64)
+	return fmt.Errorf("Value is not one of the allowed values")
+}
+
+// Check a value against the limits of the value descriptor. Compare parses a limit as the value
+// and tells whether the value is above (1), below (-1) or at (0) the limit.
+func checkLimits(vd models.ValueDescriptor, compare func(limit string) (int, error)) error {
+	if max, ok := limitString(vd.Max); ok {
+		c, err := compare(max)
 		if err != nil {
 			return err
 		}
+		if c > 0 {
+			return fmt.Errorf("Value is over the limits")
+		}
+	}
 
-		if value < min {
+	if min, ok := limitString(vd.Min); ok {
+		c, err := compare(min)
+		if err != nil {
+			return err
+		}
+		if c < 0 {
 			return fmt.Errorf("Value is under the limits")
 		}
 	}
@@ -105,15 +177,61 @@ func validInteger(reading models.Reading, vd models.ValueDescriptor) error {
 	return nil
 }
 
-func validString(reading models.Reading) error {
-	if reading.Value == "" {
-		return fmt.Errorf("Value is empty")
+// Get a limit of the value descriptor as a string, limits decoded from JSON numbers being float64
+func limitString(limit interface{}) (string, bool) {
+	switch l := limit.(type) {
+	case nil:
+		return "", false
+	case string:
+		return l, l != ""
+	case float64:
+		return strconv.FormatFloat(l, 'f', -1, 64), true
+	default:
+		return fmt.Sprint(l), true
 	}
+}
 
-	return nil
+func compareFloat(value float64) func(string) (int, error) {
+	return func(limit string) (int, error) {
+		l, err := strconv.ParseFloat(limit, 64)
+		if err != nil {
+			return 0, err
+		}
+		return compare(value > l, value < l), nil
+	}
 }
 
-func validJSON(reading models.Reading) error {
-	var js interface{}
-	return json.Unmarshal([]byte(reading.Value), &js)
+// Compare a length to limits that must be non negative integers
+func compareLength(length int) func(string) (int, error) {
+	return func(limit string) (int, error) {
+		l, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return compare(uint64(length) > l, uint64(length) < l), nil
+	}
+}
+
+func compare(above bool, below bool) int {
+	if above {
+		return 1
+	}
+	if below {
+		return -1
+	}
+	return 0
+}
+
+// Get the width of the integers of the value descriptor type
+func typeBitSize(valueType string) int {
+	switch valueType {
+	case models.ValueTypeInteger8, models.ValueTypeUnsigned8:
+		return 8
+	case models.ValueTypeInteger16, models.ValueTypeUnsigned16:
+		return 16
+	case models.ValueTypeInteger32, models.ValueTypeUnsigned32:
+		return 32
+	default:
+		return 64
+	}
 }
@@ -256,3 +256,40 @@ func TestIsValidValueDescriptor_private(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidValueDescriptorTypes(t *testing.T) {
+	var tests = []struct {
+		name  string
+		vd    models.ValueDescriptor
+		value string
+		err   bool
+	}{
+		{"I8", models.ValueDescriptor{Type: "I8"}, "127", false},
+		{"I8 overflow", models.ValueDescriptor{Type: "I8"}, "128", true},
+		{"I16 max", models.ValueDescriptor{Type: "I16", Max: "100"}, "101", true},
+		{"U", models.ValueDescriptor{Type: "U"}, "18446744073709551615", false},
+		{"U negative", models.ValueDescriptor{Type: "U32"}, "-1", true},
+		{"U8 overflow", models.ValueDescriptor{Type: "U8"}, "256", true},
+		{"U16 min", models.ValueDescriptor{Type: "U16", Min: "10"}, "9", true},
+		{"JSON number limit", models.ValueDescriptor{Type: "F", Max: float64(10)}, "10.5", true},
+		{"String length", models.ValueDescriptor{Type: "S", Max: "4"}, "test", false},
+		{"String too long", models.ValueDescriptor{Type: "S", Max: "4"}, "tests", true},
+		{"Binary", models.ValueDescriptor{Type: "BIN", Max: "3"}, "AQID", false},
+		{"Binary too long", models.ValueDescriptor{Type: "BIN", Max: "2"}, "AQID", true},
+		{"Binary not base64", models.ValueDescriptor{Type: "BIN"}, "not base64!", true},
+		{"Array", models.ValueDescriptor{Type: "A", Min: "0", Max: "10"}, "[1, 2.5, 10]", false},
+		{"Array over", models.ValueDescriptor{Type: "A", Max: "10"}, "[1, 11]", true},
+		{"Array not numbers", models.ValueDescriptor{Type: "A"}, "[\"a\"]", true},
+		{"Enumeration", models.ValueDescriptor{Type: "E", AllowedValues: []string{"on", "off"}}, "on", false},
+		{"Enumeration not allowed", models.ValueDescriptor{Type: "E", AllowedValues: []string{"on", "off"}}, "dim", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := isValidValueDescriptor(tt.vd, models.Reading{Value: tt.value})
+			if (err != nil) != tt.err {
+				t.Errorf("Expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/errors"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
@@ -29,21 +31,31 @@ const (
 	maxExceededString string = "Error, exceeded the max limit as defined in config"
 )
 
-// Check if the value descriptor matches the format string regular expression
+var formatSpecifierRegexp = regexp.MustCompile(formatSpecifier)
+
+// Conversions allowed in the format string for each value descriptor type, s and v being allowed for all
+var formatConversions = map[string]string{
+	contract.ValueTypeBool:        "bt",
+	contract.ValueTypeFloat:       "eEfFgGaAxX",
+	contract.ValueTypeInteger:     "bcdoOxXqU",
+	contract.ValueTypeUnsigned:    "bcdoOxXqU",
+	contract.ValueTypeString:      "qxX",
+	contract.ValueTypeJSON:        "qxX",
+	contract.ValueTypeBinary:      "qxX",
+	contract.ValueTypeArray:       "",
+	contract.ValueTypeEnumeration: "qxX",
+}
+
+// Check if the value descriptor matches the format string regular expression.
+// Every % of the format string must start a specifier and at least one value must be formatted.
 func validateFormatString(v contract.ValueDescriptor) error {
 	// No formatting specified
 	if v.Formatting == "" {
 		return nil
 	}
 
-	match, err := regexp.MatchString(formatSpecifier, v.Formatting)
-
+	_, err := formatConversionsOf(v.Formatting)
 	if err != nil {
-		LoggingClient.Error("Error checking for format string for value descriptor " + v.Name)
-		return err
-	}
-	if !match {
-		err = fmt.Errorf("format is not a valid printf format")
 		LoggingClient.Error(fmt.Sprintf("Error posting value descriptor. %s", err.Error()))
 		return errors.NewErrValueDescriptorInvalid(v.Name, err)
 	}
@@ -51,6 +63,108 @@ func validateFormatString(v contract.ValueDescriptor) error {
 	return nil
 }
 
+// Get the conversions of a format string, failing if it is not a valid printf format. As before the
+// conversions were checked, a format needs a specifier, which may be a literal %% without conversions.
+func formatConversionsOf(format string) ([]string, error) {
+	var conversions []string
+	rest := format
+	specifiers := formatSpecifierRegexp.FindAllStringSubmatchIndex(format, -1)
+	for _, m := range specifiers {
+		rest = strings.Replace(rest, format[m[0]:m[1]], "", 1)
+		conversion := format[m[12]:m[13]]
+		if conversion == "%" {
+			continue
+		}
+		// Date and time prefixes
+		if m[10] >= 0 {
+			conversion = format[m[10]:m[11]] + conversion
+		}
+		conversions = append(conversions, conversion)
+	}
+
+	if strings.Contains(rest, "%") || len(specifiers) == 0 {
+		return nil, fmt.Errorf("format is not a valid printf format")
+	}
+	return conversions, nil
+}
+
+// Check the value descriptor type, limits, allowed values and formatting are consistent
+func validateValueDescriptor(v contract.ValueDescriptor) error {
+	kind := valueTypeKind(v.Type)
+	allowed, ok := formatConversions[kind]
+	if !ok {
+		// Value descriptors without a type, or with a type of their own such as the ones of device
+		// profiles, only have their formatting checked, as before the types were defined
+		return validateFormatString(v)
+	}
+
+	// Check the limits parse as values of the type
+	var parse func(limit string) error
+	switch kind {
+	case contract.ValueTypeFloat, contract.ValueTypeArray:
+		parse = func(limit string) error {
+			_, err := strconv.ParseFloat(limit, 64)
+			return err
+		}
+	case contract.ValueTypeInteger:
+		parse = func(limit string) error {
+			_, err := strconv.ParseInt(limit, 10, typeBitSize(v.Type))
+			return err
+		}
+	case contract.ValueTypeUnsigned, contract.ValueTypeString, contract.ValueTypeBinary:
+		parse = func(limit string) error {
+			_, err := strconv.ParseUint(limit, 10, typeBitSize(v.Type))
+			return err
+		}
+	}
+	if parse != nil {
+		err := checkLimits(v, func(limit string) (int, error) {
+			return 0, parse(limit)
+		})
+		if err != nil {
+			return errors.NewErrValueDescriptorInvalid(v.Name, fmt.Errorf("invalid limits: %s", err.Error()))
+		}
+	}
+
+	if kind == contract.ValueTypeEnumeration && len(v.AllowedValues) == 0 {
+		return errors.NewErrValueDescriptorInvalid(v.Name, fmt.Errorf("enumerations require allowed values"))
+	}
+
+	if v.Formatting == "" {
+		return nil
+	}
+	conversions, err := formatConversionsOf(v.Formatting)
+	if err != nil {
+		return errors.NewErrValueDescriptorInvalid(v.Name, err)
+	}
+	for _, c := range conversions {
+		// Date and time conversions only format integers
+		if len(c) > 1 {
+			if kind != contract.ValueTypeInteger {
+				return errors.NewErrValueDescriptorInvalid(v.Name, fmt.Errorf("format %%%s does not fit type %s", c, v.Type))
+			}
+			continue
+		}
+		if c != "s" && c != "v" && !strings.Contains(allowed, c) {
+			return errors.NewErrValueDescriptorInvalid(v.Name, fmt.Errorf("format %%%s does not fit type %s", c, v.Type))
+		}
+	}
+
+	return nil
+}
+
+// Group the fixed width integer types with their generic type
+func valueTypeKind(valueType string) string {
+	switch valueType {
+	case contract.ValueTypeInteger8, contract.ValueTypeInteger16, contract.ValueTypeInteger32, contract.ValueTypeInteger64:
+		return contract.ValueTypeInteger
+	case contract.ValueTypeUnsigned8, contract.ValueTypeUnsigned16, contract.ValueTypeUnsigned32, contract.ValueTypeUnsigned64:
+		return contract.ValueTypeUnsigned
+	default:
+		return valueType
+	}
+}
+
 func getValueDescriptorByName(name string) (vd contract.ValueDescriptor, err error) {
 	vd, err = dbClient.ValueDescriptorByName(name)
 
@@ -205,6 +319,12 @@ func decodeValueDescriptor(reader io.ReadCloser) (vd contract.ValueDescriptor, e
 }
 
 func addValueDescriptor(vd contract.ValueDescriptor) (id string, err error) {
+	err = validateValueDescriptor(vd)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		return "", err
+	}
+
 	id, err = dbClient.AddValueDescriptor(vd)
 	if err != nil {
 		LoggingClient.Error(err.Error())
@@ -232,15 +352,6 @@ func updateValueDescriptor(from contract.ValueDescriptor) error {
 		to.DefaultValue = from.DefaultValue
 	}
 	if from.Formatting != "" {
-		match, err := regexp.MatchString(formatSpecifier, from.Formatting)
-		if err != nil {
-			LoggingClient.Error("Error checking formatting for updated value descriptor")
-			return err
-		}
-		if !match {
-			LoggingClient.Error("value descriptor's format string doesn't fit the required pattern: " + formatSpecifier)
-			return errors.NewErrValueDescriptorInvalid(from.Name, err)
-		}
 		to.Formatting = from.Formatting
 	}
 	if from.Labels != nil {
@@ -278,6 +389,16 @@ func updateValueDescriptor(from contract.ValueDescriptor) error {
 	if from.UomLabel != "" {
 		to.UomLabel = from.UomLabel
 	}
+	if from.AllowedValues != nil {
+		to.AllowedValues = from.AllowedValues
+	}
+
+	// Check the updated value descriptor as a whole, the new type may not fit the old limits
+	err = validateValueDescriptor(to)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		return err
+	}
 
 	// Push the updated valuedescriptor to the database
 	err = dbClient.UpdateValueDescriptor(to)
@@ -39,6 +39,82 @@ func TestValidateFormatStringInvalid(t *testing.T) {
 	}
 }
 
+func TestValidateFormatStringSpecifiers(t *testing.T) {
+	reset()
+	var tests = []struct {
+		format string
+		err    bool
+	}{
+		{"%.2f degrees", false},
+		{"%5d%%", false},
+		{"%%", false},
+		{"100%% humidity", false},
+		{"degrees", true},
+		{"%d and %", true},
+		{"%-10s|", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			err := validateFormatString(models.ValueDescriptor{Formatting: tt.format})
+			if (err != nil) != tt.err {
+				t.Errorf("Expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateValueDescriptor(t *testing.T) {
+	reset()
+	var tests = []struct {
+		name string
+		vd   models.ValueDescriptor
+		err  bool
+	}{
+		{"No type", models.ValueDescriptor{Formatting: "%d"}, false},
+		{"Unknown type", models.ValueDescriptor{Type: "X"}, false},
+		{"Unknown type with format", models.ValueDescriptor{Type: "Int32", Formatting: "%s", Min: "low"}, false},
+		{"Unknown type with invalid format", models.ValueDescriptor{Type: "Float64", Formatting: "%"}, true},
+		{"Float format", models.ValueDescriptor{Type: "F", Formatting: "%.2f", Min: "-1.5", Max: float64(2)}, false},
+		{"Float integer format", models.ValueDescriptor{Type: "F", Formatting: "%d"}, true},
+		{"Float format without conversion", models.ValueDescriptor{Type: "F", Formatting: "%%"}, false},
+		{"Integer limits", models.ValueDescriptor{Type: "I8", Min: "-128", Max: "127"}, false},
+		{"Integer limits overflow", models.ValueDescriptor{Type: "I8", Max: "128"}, true},
+		{"Integer float limit", models.ValueDescriptor{Type: "I", Max: "1.5"}, true},
+		{"Unsigned negative limit", models.ValueDescriptor{Type: "U", Min: "-1"}, true},
+		{"Integer date format", models.ValueDescriptor{Type: "I64", Formatting: "%tc"}, false},
+		{"String date format", models.ValueDescriptor{Type: "S", Formatting: "%tc"}, true},
+		{"String length limit", models.ValueDescriptor{Type: "S", Max: "-1"}, true},
+		{"String format", models.ValueDescriptor{Type: "S", Formatting: "%q"}, false},
+		{"Enumeration", models.ValueDescriptor{Type: "E", AllowedValues: []string{"on", "off"}}, false},
+		{"Enumeration without values", models.ValueDescriptor{Type: "E"}, true},
+		{"Array format", models.ValueDescriptor{Type: "A", Formatting: "%v"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateValueDescriptor(tt.vd)
+			if (err != nil) != tt.err {
+				t.Errorf("Expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestAddValueDescriptorInvalid(t *testing.T) {
+	reset()
+	dbClient = &mocks.DBClient{}
+
+	_, err := addValueDescriptor(models.ValueDescriptor{Name: "invalid", Type: "B", Formatting: "%f"})
+
+	switch err.(type) {
+	case *errors.ErrValueDescriptorInvalid:
+		return
+	default:
+		t.Errorf("Expected an error of type *ErrValueDescriptorInvalid")
+	}
+}
+
 func TestGetValueDescriptorByName(t *testing.T) {
 	reset()
 	myMock := &mocks.DBClient{}
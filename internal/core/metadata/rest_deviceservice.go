@@ -22,6 +22,7 @@ import (
 	"strconv"
 
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"github.com/gorilla/mux"
 )
@@ -863,6 +864,10 @@ func updateServiceLastReported(ds models.DeviceService, lr int64, w http.Respons
 // Notify associates (associated device services)
 // This function is called when an object changes in metadata
 func notifyAssociates(deviceServices []models.DeviceService, id string, action string, actionType models.ActionType) error {
+	if err := notifyCoreData(id, action, actionType); err != nil {
+		return err
+	}
+
 	for _, ds := range deviceServices {
 		if err := callback(ds, id, action, actionType); err != nil {
 			return err
@@ -872,6 +877,27 @@ func notifyAssociates(deviceServices []models.DeviceService, id string, action s
 	return nil
 }
 
+// Make the callback for core data, when configured as a client, so that it drops the devices it cached
+func notifyCoreData(id string, action string, actionType models.ActionType) error {
+	info, ok := Configuration.Clients["CoreData"]
+	if !ok {
+		return nil
+	}
+
+	body, err := getBody(id, actionType)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(action, info.Url()+clients.ApiCallbackRoute, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	go makeRequest(&http.Client{}, req)
+	return nil
+}
+
 // Make the callback for the device service
 func callback(service models.DeviceService, id string, action string, actionType models.ActionType) error {
 	client := &http.Client{}
@@ -19,12 +19,14 @@ import (
 	"runtime"
 
 	"github.com/edgexfoundry/edgex-go/internal"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
 	"github.com/edgexfoundry/edgex-go/pkg/clients"
 	"github.com/gorilla/mux"
 )
 
 func LoadRestRoutes() *mux.Router {
 	r := mux.NewRouter()
+	r.Use(metrics.Middleware)
 
 	// Ping Resource
 	r.HandleFunc(clients.ApiPingRoute, pingHandler).Methods(http.MethodGet)
@@ -34,6 +36,7 @@ func LoadRestRoutes() *mux.Router {
 
 	// Metrics
 	r.HandleFunc(clients.ApiMetricsRoute, metricsHandler).Methods(http.MethodGet)
+	r.HandleFunc(clients.ApiPrometheusRoute, metrics.Handler).Methods(http.MethodGet)
 
 	b := r.PathPrefix(clients.ApiBase).Subrouter()
 
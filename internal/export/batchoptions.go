@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package export
+
+import (
+	"fmt"
+)
+
+// BatchOptions - Specifies how the events of a registration are gathered
+// in batches, formatted and sent together. Batching is disabled when
+// none of the limits is set.
+type BatchOptions struct {
+	// MaxEvents sends the batch once it holds this number of events
+	MaxEvents int `bson:"maxEvents,omitempty" json:"maxEvents,omitempty"`
+	// MaxBytes sends the batch once the names and values of its readings reach this size
+	MaxBytes int `bson:"maxBytes,omitempty" json:"maxBytes,omitempty"`
+	// Window sends the batch this time, in milliseconds, after its first event.
+	// It is required, so that the last events of a burst are not held back.
+	Window int `bson:"window,omitempty" json:"window,omitempty"`
+}
+
+// Enabled tells whether the events are sent in batches.
+func (opts BatchOptions) Enabled() bool {
+	return opts.MaxEvents > 0 || opts.MaxBytes > 0 || opts.Window > 0
+}
+
+// Validate checks the limits of the options, and that the format and
+// destination of the registration can handle batches. Line protocol payloads,
+// batched or not, are written to InfluxDB as is, so they can be neither
+// compressed nor encrypted.
+func (opts BatchOptions) Validate(format string, destination string, compression string, encryption string) error {
+	if format == FormatInfluxDBLine {
+		if compression != "" && compression != CompNone {
+			return fmt.Errorf("Compression not supported with format %s: %s", format, compression)
+		}
+		if encryption != "" && encryption != EncNone {
+			return fmt.Errorf("Encryption not supported with format %s: %s", format, encryption)
+		}
+	}
+
+	if opts.MaxEvents < 0 || opts.MaxBytes < 0 || opts.Window < 0 {
+		return fmt.Errorf("Batch limits invalid: %d events, %d bytes, %d ms", opts.MaxEvents, opts.MaxBytes, opts.Window)
+	}
+	if !opts.Enabled() {
+		return nil
+	}
+	if opts.Window == 0 {
+		return fmt.Errorf("Batch window is required")
+	}
+
+	if format != FormatJSON &&
+		format != FormatSerialized &&
+		format != FormatCSV &&
+		format != FormatInfluxDBLine &&
+		format != FormatNOOP {
+		return fmt.Errorf("Format does not support batches: %s", format)
+	}
+
+	if destination != DestRest &&
+		destination != DestInfluxDB {
+		return fmt.Errorf("Destination does not support batches: %s", destination)
+	}
+
+	return nil
+}
@@ -208,6 +208,9 @@ func updateReg(w http.ResponseWriter, r *http.Request) {
 	if fromReg.Format != "" {
 		toReg.Format = fromReg.Format
 	}
+	if fromReg.Template != "" {
+		toReg.Template = fromReg.Template
+	}
 	if fromReg.Filter.DeviceIDs != nil {
 		toReg.Filter.DeviceIDs = fromReg.Filter.DeviceIDs
 	}
@@ -231,6 +234,30 @@ func updateReg(w http.ResponseWriter, r *http.Request) {
 	if objmap["enable"] != nil {
 		toReg.Enable = fromReg.Enable
 	}
+	if objmap["csv"] != nil {
+		toReg.CSV = fromReg.CSV
+	}
+	if objmap["batch"] != nil {
+		toReg.Batch = fromReg.Batch
+	}
+	if objmap["filter"] != nil {
+		// The same goes for the optional fields of the filter, which an update may remove
+		var filtermap map[string]*json.RawMessage
+		if err := json.Unmarshal(*objmap["filter"], &filtermap); err != nil {
+			LoggingClient.Error(fmt.Sprintf("Failed to unmarshal update registration filter. Error: %s", err.Error()))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := filtermap["tags"]; ok {
+			toReg.Filter.Tags = fromReg.Filter.Tags
+		}
+		if _, ok := filtermap["expression"]; ok {
+			toReg.Filter.Expression = fromReg.Filter.Expression
+		}
+		if _, ok := filtermap["deadband"]; ok {
+			toReg.Filter.Deadband = fromReg.Filter.Deadband
+		}
+	}
 
 	if valid, err := toReg.Validate(); !valid {
 		LoggingClient.Error(fmt.Sprintf("Failed to validate registrations fields: %X. Error: %s", data, err.Error()))
@@ -13,6 +13,7 @@ import (
 	"runtime"
 
 	"github.com/edgexfoundry/edgex-go/internal"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
 	"github.com/edgexfoundry/edgex-go/pkg/clients"
 	"github.com/gorilla/mux"
 )
@@ -69,6 +70,7 @@ func metricsHandler(w http.ResponseWriter, _ *http.Request) {
 // HTTPServer function
 func httpServer() http.Handler {
 	r := mux.NewRouter()
+	r.Use(metrics.Middleware)
 
 	// Ping Resource
 	r.HandleFunc(clients.ApiPingRoute, pingHandler).Methods(http.MethodGet)
@@ -78,6 +80,7 @@ func httpServer() http.Handler {
 
 	// Metrics
 	r.HandleFunc(clients.ApiMetricsRoute, metricsHandler).Methods(http.MethodGet)
+	r.HandleFunc(clients.ApiPrometheusRoute, metrics.Handler).Methods(http.MethodGet)
 
 	// Registration
 	r.HandleFunc(clients.ApiRegistrationRoute, getAllReg).Methods(http.MethodGet)
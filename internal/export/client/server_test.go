@@ -82,6 +82,9 @@ func TestRegistrationAdd(t *testing.T) {
 		{"invalidJSON", "aa", http.StatusBadRequest},
 		{"ok", `{"origin":1471806386919,"name":"NAME","addressable":{"origin":1471806386919,"name":"AnotherName","method":"POST","protocol":"TCP","address":"127.0.0.1","port":1883,"publisher":"SomePublisher","user":"dummy","password":"dummy","topic":"SomeTopic"},"format":"JSON","enable":true, "destination":"MQTT_TOPIC","compression":"NONE"}`, http.StatusOK},
 		{"ok", regJson, http.StatusOK},
+		{"template", `{"name":"TEMPLATE","format":"TEMPLATE","template":"{\"id\":{{json .Device}}}","enable":true,"destination":"REST_ENDPOINT"}`, http.StatusOK},
+		{"invalidTemplate", `{"name":"INVALID_TEMPLATE","format":"TEMPLATE","template":"{{.Device","enable":true,"destination":"REST_ENDPOINT"}`, http.StatusBadRequest},
+		{"missingTemplate", `{"name":"MISSING_TEMPLATE","format":"TEMPLATE","enable":true,"destination":"REST_ENDPOINT"}`, http.StatusBadRequest},
 	}
 
 	ts := prepareTest(t)
@@ -152,6 +155,8 @@ func TestRegistrationUpdate(t *testing.T) {
 		{"updById", `{"id":"%s", "compression":"INVALID"}`, http.StatusBadRequest},
 		{"updByName", regJson, http.StatusOK},
 		{"updByName", `{"Name":"OSIClient", "compression":"INVALID"}`, http.StatusBadRequest},
+		{"updTemplate", `{"Name":"OSIClient", "format":"TEMPLATE", "template":"{{.Device}}"}`, http.StatusOK},
+		{"updTemplate", `{"Name":"OSIClient", "format":"TEMPLATE", "template":"{{unknown .Device}}"}`, http.StatusBadRequest},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -180,6 +185,55 @@ func TestRegistrationUpdate(t *testing.T) {
 	}
 }
 
+func TestRegistrationUpdateFilter(t *testing.T) {
+	var tests = []struct {
+		name       string
+		data       string
+		expression string
+		tags       bool
+		deadband   bool
+	}{
+		{"keepFilter", `{"Name":"OSIClient", "filter":{"deviceIdentifiers":["livingroomthermosat"]}}`, "temperature > 80", true, true},
+		{"setExpression", `{"Name":"OSIClient", "filter":{"expression":"humidity < 20"}}`, "humidity < 20", true, true},
+		{"removeExpression", `{"Name":"OSIClient", "filter":{"expression":""}}`, "", true, true},
+		{"removeTags", `{"Name":"OSIClient", "filter":{"tags":null}}`, "temperature > 80", false, true},
+		{"removeDeadband", `{"Name":"OSIClient", "filter":{"deadband":null}}`, "temperature > 80", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := prepareTest(t)
+			defer ts.Close()
+
+			createRegistration(t, ts.URL)
+			response := requestMethod(t, http.MethodPut, ts.URL+clients.ApiRegistrationRoute,
+				bytes.NewBufferString(`{"Name":"OSIClient", "filter":{"expression":"temperature > 80",
+					"tags":{"site":"plant1"}, "deadband":{"threshold":0.5}}}`))
+			response.Body.Close()
+
+			response = requestMethod(t, http.MethodPut, ts.URL+clients.ApiRegistrationRoute,
+				bytes.NewBufferString(tt.data))
+			defer response.Body.Close()
+			if response.StatusCode != http.StatusOK {
+				t.Fatalf("Returned status %d, should be %d", response.StatusCode, http.StatusOK)
+			}
+
+			reg, err := dbClient.RegistrationByName("OSIClient")
+			if err != nil {
+				t.Fatalf("Error querying the registration %v", err)
+			}
+			if reg.Filter.Expression != tt.expression {
+				t.Errorf("Expression is %q, should be %q", reg.Filter.Expression, tt.expression)
+			}
+			if (reg.Filter.Tags != nil) != tt.tags {
+				t.Errorf("Tags are %v, should be kept: %v", reg.Filter.Tags, tt.tags)
+			}
+			if (reg.Filter.Deadband != nil) != tt.deadband {
+				t.Errorf("Deadband is %v, should be kept: %v", reg.Filter.Deadband, tt.deadband)
+			}
+		})
+	}
+}
+
 func TestRegistrationDelByName(t *testing.T) {
 	ts := prepareTest(t)
 	defer ts.Close()
@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package export
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// CSV layouts
+const (
+	CSVRowPerReading = "READING"
+	CSVRowPerEvent   = "EVENT"
+)
+
+// CSV timestamp formats, any other format is a Go time layout
+const (
+	CSVTimeMillis  = "MILLIS"
+	CSVTimeRFC3339 = "RFC3339"
+)
+
+// CSVOptions - Specifies the layout of the CSV export data
+// per client request
+type CSVOptions struct {
+	// Layout is CSVRowPerReading, the default, or CSVRowPerEvent
+	Layout string `bson:"layout,omitempty" json:"layout,omitempty"`
+	// Header starts the data with a row holding the column names
+	Header bool `bson:"header,omitempty" json:"header,omitempty"`
+	// Delimiter separates the columns, a comma by default
+	Delimiter string `bson:"delimiter,omitempty" json:"delimiter,omitempty"`
+	// TimestampFormat is CSVTimeMillis, the default, CSVTimeRFC3339 or a Go time layout
+	TimestampFormat string `bson:"timestampFormat,omitempty" json:"timestampFormat,omitempty"`
+}
+
+// Validate checks the layout and the delimiter of the options.
+func (opts CSVOptions) Validate() error {
+	if opts.Layout != "" &&
+		opts.Layout != CSVRowPerReading &&
+		opts.Layout != CSVRowPerEvent {
+		return fmt.Errorf("CSV layout invalid: %s", opts.Layout)
+	}
+
+	if opts.Delimiter != "" {
+		r, size := utf8.DecodeRuneInString(opts.Delimiter)
+		if size != len(opts.Delimiter) || r == utf8.RuneError ||
+			r == '"' || r == '\r' || r == '\n' {
+			return fmt.Errorf("CSV delimiter invalid: %q", opts.Delimiter)
+		}
+	}
+
+	return nil
+}
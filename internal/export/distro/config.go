@@ -20,12 +20,27 @@ type ConfigurationStruct struct {
 	Clients        map[string]config.ClientInfo
 	Logging        config.LoggingInfo
 	MessageQueue   config.MessageQueueInfo
+	Queue          QueueInfo
 	AnalyticsQueue config.MessageQueueInfo
 	Registry       config.RegistryInfo
 	Service        config.ServiceInfo
 	MarkPushed     bool
 }
 
+// QueueInfo configures the on-disk queues of the payloads the registrations failed to send.
+type QueueInfo struct {
+	// Enabled turns queuing on
+	Enabled bool
+	// Path is the file holding the queues of all the registrations
+	Path string
+	// MaxPayloads is the maximum number of payloads queued per registration, beyond which payloads are dropped
+	MaxPayloads int
+	// InitialBackoff is the time, in milliseconds, before sending again after a failure
+	InitialBackoff int
+	// MaxBackoff is the maximum time, in milliseconds, between two attempts, the time doubling after each failure
+	MaxBackoff int
+}
+
 type CertificateInfo struct {
 	Cert string
 	Key  string
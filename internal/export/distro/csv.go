@@ -0,0 +1,150 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package distro
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/edgexfoundry/edgex-go/internal/export"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Columns of the CSV rows, the readings of the row per event layout follow the timestamp
+const (
+	csvDeviceColumn    = "device"
+	csvTimestampColumn = "timestamp"
+	csvNameColumn      = "name"
+	csvValueColumn     = "value"
+)
+
+// csvFormatter writes events as CSV, either one row per reading or one row per event with a
+// column per reading
+type csvFormatter struct {
+	perEvent        bool
+	header          bool
+	delimiter       rune
+	timestampFormat string
+}
+
+// newCSVFormatter creates the formatter of the options, which must be valid
+func newCSVFormatter(opts export.CSVOptions) csvFormatter {
+	csvFmt := csvFormatter{
+		perEvent:        opts.Layout == export.CSVRowPerEvent,
+		header:          opts.Header,
+		delimiter:       ',',
+		timestampFormat: opts.TimestampFormat,
+	}
+	if opts.Delimiter != "" {
+		csvFmt.delimiter, _ = utf8.DecodeRuneInString(opts.Delimiter)
+	}
+	return csvFmt
+}
+
+func (csvFmt csvFormatter) Format(event *models.Event) []byte {
+	return csvFmt.FormatBatch([]*models.Event{event})
+}
+
+// Format the rows of all the events, after a single header
+func (csvFmt csvFormatter) FormatBatch(events []*models.Event) []byte {
+	var rows [][]string
+	if csvFmt.perEvent {
+		rows = csvFmt.eventRows(events)
+	} else {
+		rows = csvFmt.readingRows(events)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = csvFmt.delimiter
+	if err := w.WriteAll(rows); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error writing CSV. Error: %s", err.Error()))
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// One row per reading, timestamped with the origin of the reading or else of the event
+func (csvFmt csvFormatter) readingRows(events []*models.Event) [][]string {
+	var rows [][]string
+	if csvFmt.header {
+		rows = append(rows, []string{csvDeviceColumn, csvTimestampColumn, csvNameColumn, csvValueColumn})
+	}
+	for _, event := range events {
+		for _, reading := range event.Readings {
+			origin := reading.Origin
+			if origin == 0 {
+				origin = event.Origin
+			}
+			rows = append(rows, []string{event.Device, csvFmt.timestamp(origin), reading.Name, csvValue(reading)})
+		}
+	}
+	return rows
+}
+
+// One row per event with a column per reading name, sorted by name. The columns are the names of
+// the readings of all the events, left empty for the events without them. When an event holds
+// several readings with the same name, the last one is kept.
+func (csvFmt csvFormatter) eventRows(events []*models.Event) [][]string {
+	eventValues := make([]map[string]string, len(events))
+	columns := make(map[string]bool)
+	for i, event := range events {
+		eventValues[i] = make(map[string]string)
+		for _, reading := range event.Readings {
+			eventValues[i][reading.Name] = csvValue(reading)
+			columns[reading.Name] = true
+		}
+	}
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rows [][]string
+	if csvFmt.header {
+		rows = append(rows, append([]string{csvDeviceColumn, csvTimestampColumn}, names...))
+	}
+	for i, event := range events {
+		row := []string{event.Device, csvFmt.timestamp(event.Origin)}
+		for _, name := range names {
+			row = append(row, eventValues[i][name])
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Format a timestamp in milliseconds since the epoch
+func (csvFmt csvFormatter) timestamp(millis int64) string {
+	switch csvFmt.timestampFormat {
+	case "", export.CSVTimeMillis:
+		return strconv.FormatInt(millis, 10)
+	case export.CSVTimeRFC3339:
+		return millisToTime(millis).Format(time.RFC3339Nano)
+	default:
+		return millisToTime(millis).Format(csvFmt.timestampFormat)
+	}
+}
+
+func millisToTime(millis int64) time.Time {
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC()
+}
+
+// Binary readings are written in base64
+func csvValue(reading models.Reading) string {
+	if len(reading.BinaryValue) > 0 {
+		return base64.StdEncoding.EncodeToString(reading.BinaryValue)
+	}
+	return reading.Value
+}
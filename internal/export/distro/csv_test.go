@@ -0,0 +1,85 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package distro
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/export"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func csvEvent() *models.Event {
+	return &models.Event{
+		Device: devID1,
+		Origin: 1540000000123,
+		Readings: []models.Reading{
+			{Name: readingName1, Value: readingValue1},
+			{Name: "label", Value: "a,\"b\"", Origin: 1540000000456},
+			{Name: "image", BinaryValue: []byte{1, 2, 3}},
+		},
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var tests = []struct {
+		name     string
+		opts     export.CSVOptions
+		expected string
+	}{
+		{"perReading", export.CSVOptions{},
+			"id1,1540000000123,sensor1,123.45\n" +
+				"id1,1540000000456,label,\"a,\"\"b\"\"\"\n" +
+				"id1,1540000000123,image,AQID\n"},
+		{"perReadingHeader", export.CSVOptions{Layout: export.CSVRowPerReading, Header: true, Delimiter: ";"},
+			"device;timestamp;name;value\n" +
+				"id1;1540000000123;sensor1;123.45\n" +
+				"id1;1540000000456;label;\"a,\"\"b\"\"\"\n" +
+				"id1;1540000000123;image;AQID\n"},
+		{"perEvent", export.CSVOptions{Layout: export.CSVRowPerEvent, TimestampFormat: export.CSVTimeRFC3339},
+			"id1,2018-10-20T01:46:40.123Z,AQID,\"a,\"\"b\"\"\",123.45\n"},
+		{"perEventHeader", export.CSVOptions{Layout: export.CSVRowPerEvent, Header: true, Delimiter: "\t",
+			TimestampFormat: "2006-01-02 15:04:05"},
+			"device\ttimestamp\timage\tlabel\tsensor1\n" +
+				"id1\t2018-10-20 01:46:40\tAQID\t\"a,\"\"b\"\"\"\t123.45\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := newCSVFormatter(tt.opts).Format(csvEvent())
+			if string(out) != tt.expected {
+				t.Errorf("CSV should be\n%s\ninstead of\n%s", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestCSVNoReadings(t *testing.T) {
+	out := newCSVFormatter(export.CSVOptions{Header: true}).Format(&models.Event{Device: devID1})
+	if string(out) != "device,timestamp,name,value\n" {
+		t.Errorf("Only the header should be written: %s", out)
+	}
+}
+
+func TestCSVBatch(t *testing.T) {
+	events := []*models.Event{
+		{Device: "dev1", Origin: 1, Readings: []models.Reading{{Name: "b", Value: "1"}}},
+		{Device: "dev2", Origin: 2, Readings: []models.Reading{{Name: "a", Value: "2"}, {Name: "b", Value: "3"}}},
+	}
+
+	out := newCSVFormatter(export.CSVOptions{Header: true}).FormatBatch(events)
+	expected := "device,timestamp,name,value\ndev1,1,b,1\ndev2,2,a,2\ndev2,2,b,3\n"
+	if string(out) != expected {
+		t.Errorf("CSV should be\n%s\ninstead of\n%s", expected, out)
+	}
+
+	out = newCSVFormatter(export.CSVOptions{Layout: export.CSVRowPerEvent, Header: true}).FormatBatch(events)
+	expected = "device,timestamp,a,b\ndev1,1,,1\ndev2,2,2,3\n"
+	if string(out) != expected {
+		t.Errorf("CSV should be\n%s\ninstead of\n%s", expected, out)
+	}
+}
@@ -8,8 +8,11 @@ package distro
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/edgexfoundry/edgex-go/internal/export"
+	"github.com/edgexfoundry/edgex-go/internal/export/expression"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
@@ -40,6 +43,33 @@ func (filter devIdFilterDetails) Filter(event *models.Event) (bool, *models.Even
 	return false, event
 }
 
+type tagFilterDetails struct {
+	tags map[string]string
+}
+
+func newTagFilter(filter export.Filter) filterer {
+	filterer := tagFilterDetails{
+		tags: filter.Tags,
+	}
+	return filterer
+}
+
+// Accept the events holding every tag of the filter, with the same value
+func (filter tagFilterDetails) Filter(event *models.Event) (bool, *models.Event) {
+
+	if event == nil {
+		return false, nil
+	}
+
+	for key, value := range filter.tags {
+		if tag, ok := event.Tags[key]; !ok || tag != value {
+			return false, event
+		}
+	}
+	LoggingClient.Debug(fmt.Sprintf("Event accepted by tags: %s", event.Device))
+	return true, event
+}
+
 type valueDescFilterDetails struct {
 	valueDescIDs []string
 }
@@ -64,6 +94,7 @@ func (filter valueDescFilterDetails) Filter(event *models.Event) (bool, *models.
 		Modified: event.Modified,
 		Origin:   event.Origin,
 		Readings: []models.Reading{},
+		Tags:     event.Tags,
 	}
 
 	for _, filterId := range filter.valueDescIDs {
@@ -76,3 +107,97 @@ func (filter valueDescFilterDetails) Filter(event *models.Event) (bool, *models.
 	}
 	return len(auxEvent.Readings) > 0, auxEvent
 }
+
+type expressionFilterDetails struct {
+	expression *expression.Expression
+}
+
+func newExpressionFilter(filter export.Filter) (filterer, error) {
+	e, err := expression.Parse(filter.Expression)
+	if err != nil {
+		return nil, err
+	}
+	filterer := expressionFilterDetails{
+		expression: e,
+	}
+	return filterer, nil
+}
+
+// Accept the events satisfying the expression
+func (filter expressionFilterDetails) Filter(event *models.Event) (bool, *models.Event) {
+
+	if event == nil {
+		return false, nil
+	}
+
+	if !filter.expression.Match(event) {
+		return false, event
+	}
+	LoggingClient.Debug(fmt.Sprintf("Event accepted by expression: %s", event.Device))
+	return true, event
+}
+
+type deadbandFilterDetails struct {
+	threshold    float64
+	readingNames map[string]bool   // All the readings when empty
+	lastValues   map[string]string // Value last exported, by device and reading name
+}
+
+func newDeadbandFilter(filter export.Filter) filterer {
+	filterer := &deadbandFilterDetails{
+		threshold:    filter.Deadband.Threshold,
+		readingNames: make(map[string]bool),
+		lastValues:   make(map[string]string),
+	}
+	for _, name := range filter.Deadband.ReadingNames {
+		filterer.readingNames[name] = true
+	}
+	return filterer
+}
+
+// Keep the readings whose value moved beyond the threshold from the value last exported for the
+// device and reading name, and the readings the deadband does not apply to. The filter remembers
+// the values it keeps, it must be the last filter of the registration.
+func (filter *deadbandFilterDetails) Filter(event *models.Event) (bool, *models.Event) {
+
+	if event == nil {
+		return false, nil
+	}
+
+	auxEvent := &models.Event{
+		ID:       event.ID,
+		Pushed:   event.Pushed,
+		Device:   event.Device,
+		Created:  event.Created,
+		Modified: event.Modified,
+		Origin:   event.Origin,
+		Readings: []models.Reading{},
+		Tags:     event.Tags,
+	}
+
+	for _, reading := range event.Readings {
+		if len(filter.readingNames) > 0 && !filter.readingNames[reading.Name] {
+			auxEvent.Readings = append(auxEvent.Readings, reading)
+			continue
+		}
+
+		key := event.Device + "/" + reading.Name
+		if last, ok := filter.lastValues[key]; ok && !filter.moved(last, reading.Value) {
+			LoggingClient.Debug(fmt.Sprintf("Reading within the deadband: %s", reading.Name))
+			continue
+		}
+		filter.lastValues[key] = reading.Value
+		auxEvent.Readings = append(auxEvent.Readings, reading)
+	}
+	return len(auxEvent.Readings) > 0, auxEvent
+}
+
+// Tell whether the value moved beyond the threshold, or changed when it is not numeric
+func (filter *deadbandFilterDetails) moved(last string, value string) bool {
+	l, lerr := strconv.ParseFloat(last, 64)
+	v, verr := strconv.ParseFloat(value, 64)
+	if lerr != nil || verr != nil {
+		return last != value
+	}
+	return math.Abs(v-l) > filter.threshold
+}
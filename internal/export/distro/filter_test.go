@@ -129,3 +129,141 @@ func TestFilterValue(t *testing.T) {
 		t.Fatal("Event should be one reading, there are ", len(res.Readings))
 	}
 }
+
+func TestFilterTags(t *testing.T) {
+	// Filter only accepting events of batch 42 from site A
+	f := export.Filter{Tags: map[string]string{"batch": "42", "site": "A"}}
+	filter := newTagFilter(f)
+
+	accepted, _ := filter.Filter(nil)
+	if accepted {
+		t.Fatal("Event should be filtered out")
+	}
+
+	event := models.Event{Tags: map[string]string{"batch": "42", "site": "A", "line": "3"}}
+	accepted, res := filter.Filter(&event)
+	if !accepted {
+		t.Fatal("Event should be accepted")
+	}
+	if res != &event {
+		t.Fatal("Event should be the same")
+	}
+
+	events := []models.Event{
+		{},
+		{Tags: map[string]string{"batch": "42"}},
+		{Tags: map[string]string{"batch": "42", "site": "B"}},
+	}
+	for i := range events {
+		accepted, _ = filter.Filter(&events[i])
+		if accepted {
+			t.Fatalf("Event %d should be filtered out", i)
+		}
+	}
+}
+
+func TestFilterValueKeepsTags(t *testing.T) {
+	f := export.Filter{ValueDescriptorIDs: []string{descriptor1}}
+	event := models.Event{Tags: map[string]string{"batch": "42"}}
+	event.Readings = append(event.Readings, models.Reading{Name: descriptor1})
+
+	accepted, res := newValueDescFilter(f).Filter(&event)
+	if !accepted {
+		t.Fatal("Event should be accepted")
+	}
+	if res.Tags["batch"] != "42" {
+		t.Fatal("Event should keep its tags, got ", res.Tags)
+	}
+}
+
+func TestFilterExpression(t *testing.T) {
+	f := export.Filter{Expression: `temperature > 80 && device =~ "^line1-"`}
+	filter, err := newExpressionFilter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if accepted, _ := filter.Filter(nil); accepted {
+		t.Fatal("Event should be filtered out")
+	}
+
+	tests := []struct {
+		device      string
+		temperature string
+		accepted    bool
+	}{
+		{"line1-press", "85", true},
+		{"line1-press", "75", false},
+		{"line2-press", "85", false},
+	}
+	for _, tt := range tests {
+		event := models.Event{Device: tt.device, Readings: []models.Reading{{Name: "temperature", Value: tt.temperature}}}
+		if accepted, _ := filter.Filter(&event); accepted != tt.accepted {
+			t.Errorf("Event from %s at %s should be accepted: %v", tt.device, tt.temperature, tt.accepted)
+		}
+	}
+
+	if _, err := newExpressionFilter(export.Filter{Expression: `temperature >`}); err == nil {
+		t.Error("An invalid expression should not make a filter")
+	}
+}
+
+func TestFilterDeadband(t *testing.T) {
+	f := export.Filter{Deadband: &export.Deadband{Threshold: 1, ReadingNames: []string{descriptor1, "state"}}}
+	filter := newDeadbandFilter(f)
+
+	if accepted, _ := filter.Filter(nil); accepted {
+		t.Fatal("Event should be filtered out")
+	}
+
+	tests := []struct {
+		device   string
+		name     string
+		value    string
+		exported bool
+	}{
+		{deviceID1, descriptor1, "10", true},
+		{deviceID1, descriptor1, "10.5", false},
+		{deviceID1, descriptor1, "11", false},
+		{deviceID1, descriptor1, "11.5", true},
+		{deviceID1, descriptor1, "10.2", true},
+		{deviceID2, descriptor1, "10.2", true},
+		{deviceID1, "state", "on", true},
+		{deviceID1, "state", "on", false},
+		{deviceID1, "state", "off", true},
+		{deviceID1, descriptor2, "10", true},
+		{deviceID1, descriptor2, "10", true},
+	}
+	for i, tt := range tests {
+		event := models.Event{ID: "id", Device: tt.device, Readings: []models.Reading{{Name: tt.name, Value: tt.value}}}
+		accepted, res := filter.Filter(&event)
+		if accepted != tt.exported {
+			t.Errorf("Reading %d of %s at %s should be exported: %v", i, tt.name, tt.value, tt.exported)
+		}
+		if accepted && res.ID != event.ID {
+			t.Errorf("Event should keep its id, got %s", res.ID)
+		}
+	}
+}
+
+func TestFilterDeadbandChangeOnly(t *testing.T) {
+	filter := newDeadbandFilter(export.Filter{Deadband: &export.Deadband{}})
+
+	event := models.Event{Device: deviceID1, Readings: []models.Reading{
+		{Name: descriptor1, Value: "1"},
+		{Name: descriptor2, Value: "2"},
+	}}
+	if accepted, res := filter.Filter(&event); !accepted || len(res.Readings) != 2 {
+		t.Fatal("The first readings should be exported")
+	}
+
+	event.Readings[1].Value = "2.001"
+	accepted, res := filter.Filter(&event)
+	if !accepted || len(res.Readings) != 1 || res.Readings[0].Name != descriptor2 {
+		t.Fatalf("Only the reading that changed should be exported: %v", res.Readings)
+	}
+
+	if accepted, _ := filter.Filter(&event); accepted {
+		t.Fatal("An event without changes should be filtered out")
+	}
+}
@@ -9,13 +9,18 @@
 package distro
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/edgexfoundry/edgex-go/internal/export/templates"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"github.com/google/uuid"
 )
@@ -43,11 +48,39 @@ func (jsonTr jsonFormatter) Format(event *models.Event) []byte {
 	return b
 }
 
+// Format the events as a JSON array
+func (jsonTr jsonFormatter) FormatBatch(events []*models.Event) []byte {
+
+	b, err := json.Marshal(events)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error parsing JSON. Error: %s", err.Error()))
+		return nil
+	}
+	return b
+}
+
 type xmlFormatter struct {
 }
 
+// Tag of an event, as an XML element with the key as attribute
+type xmlTag struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
 func (xmlTr xmlFormatter) Format(event *models.Event) []byte {
-	b, err := xml.Marshal(event)
+	// encoding/xml does not marshal maps, the tags are marshaled as a list sorted by key
+	e := struct {
+		XMLName xml.Name `xml:"Event"`
+		*models.Event
+		Tags []xmlTag `xml:"Tags>Tag,omitempty"`
+	}{Event: event}
+	for key, value := range event.Tags {
+		e.Tags = append(e.Tags, xmlTag{Key: key, Value: value})
+	}
+	sort.Slice(e.Tags, func(i, j int) bool { return e.Tags[i].Key < e.Tags[j].Key })
+
+	b, err := xml.Marshal(e)
 	if err != nil {
 		LoggingClient.Error(fmt.Sprintf("Error parsing XML. Error: %s", err.Error()))
 		return nil
@@ -150,6 +183,9 @@ func (af azureFormatter) Format(event *models.Event) []byte {
 	}
 	am.ConnDevID = event.Device
 	am.UserID = string(event.Origin)
+	for key, value := range event.Tags {
+		am.AddProperty(key, value)
+	}
 	data, err := json.Marshal(event)
 	if err != nil {
 		LoggingClient.Error(fmt.Sprintf("Error parsing Event data: %s", err))
@@ -207,6 +243,28 @@ func (af awsFormatter) Format(event *models.Event) []byte {
 	return msg
 }
 
+// templateFormatter renders the event with the template of the registration
+type templateFormatter struct {
+	template *template.Template
+}
+
+func newTemplateFormatter(text string) (formatter, error) {
+	t, err := templates.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return templateFormatter{template: t}, nil
+}
+
+func (tf templateFormatter) Format(event *models.Event) []byte {
+	var buf bytes.Buffer
+	if err := tf.template.Execute(&buf, event); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error executing template. Error: %s", err.Error()))
+		return nil
+	}
+	return buf.Bytes()
+}
+
 type noopFormatter struct {
 }
 
@@ -214,6 +272,80 @@ func (noopFmt noopFormatter) Format(event *models.Event) []byte {
 	return []byte{}
 }
 
+func (noopFmt noopFormatter) FormatBatch(events []*models.Event) []byte {
+	return []byte{}
+}
+
+// InfluxDB measurement the readings are written to
+const lineProtocolMeasurement = "readings"
+
+// Escapes the commas, equal signs and spaces of the tags in line protocol
+var lineProtocolTagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// lineProtocolFormatter renders the numeric readings as InfluxDB line protocol,
+// one line per reading, timestamped with the origin of the reading
+type lineProtocolFormatter struct {
+	// Timestamp the readings with the time they are written instead, as InfluxDB
+	// destinations always did for the registrations of the other formats
+	writeTime bool
+}
+
+func (lpFmt lineProtocolFormatter) Format(event *models.Event) []byte {
+	return lpFmt.FormatBatch([]*models.Event{event})
+}
+
+func (lpFmt lineProtocolFormatter) FormatBatch(events []*models.Event) []byte {
+	var buf bytes.Buffer
+	for _, event := range events {
+		for _, reading := range event.Readings {
+			value, err := strconv.ParseFloat(reading.Value, 64)
+			if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+				// not a valid numerical reading value, just ignore it
+				continue
+			}
+
+			device := reading.Device
+			if device == "" {
+				device = event.Device
+			}
+			buf.WriteString(lineProtocolMeasurement)
+			writeLineProtocolTag(&buf, "device", device)
+			writeLineProtocolTag(&buf, "event_id", event.ID)
+			writeLineProtocolTag(&buf, "resource_name", reading.Name)
+			fmt.Fprintf(&buf, " value=%s,created=%di,origin=%di",
+				strconv.FormatFloat(value, 'f', -1, 64), reading.Created, reading.Origin)
+
+			if lpFmt.writeTime {
+				// Truncated to the microseconds the points used to be written with
+				fmt.Fprintf(&buf, " %d\n", time.Now().Truncate(time.Microsecond).UnixNano())
+				continue
+			}
+
+			// Origins are in milliseconds, line protocol timestamps in nanoseconds
+			timestamp := reading.Origin
+			if timestamp == 0 {
+				timestamp = event.Origin
+			}
+			if timestamp == 0 {
+				timestamp = reading.Created
+			}
+			if timestamp != 0 {
+				fmt.Fprintf(&buf, " %d", timestamp*int64(time.Millisecond))
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	return append([]byte{}, buf.Bytes()...)
+}
+
+// Tags without a value cannot be written in line protocol
+func writeLineProtocolTag(buf *bytes.Buffer, key string, value string) {
+	if value == "" {
+		return
+	}
+	buf.WriteString("," + key + "=" + lineProtocolTagEscaper.Replace(value))
+}
+
 // BIoTMessage represents Brightics IoT(Samsung SDS IoT platform)  messages.
 type BIoTMessage struct {
 	Version    string `json:"version"`
@@ -43,6 +43,24 @@ func TestJson(t *testing.T) {
 	}
 }
 
+func TestJsonBatch(t *testing.T) {
+	eventsIn := []*models.Event{{Device: devID1}, {Device: "id2"}}
+
+	jf := jsonFormatter{}
+	out := jf.FormatBatch(eventsIn)
+	if out == nil {
+		t.Fatal("out should not be nil")
+	}
+
+	var eventsOut []*models.Event
+	if err := json.Unmarshal(out, &eventsOut); err != nil {
+		t.Fatalf("Error unmarshalling events: %v", err)
+	}
+	if !reflect.DeepEqual(eventsIn, eventsOut) {
+		t.Fatalf("Objects should be equals: %v %v", eventsIn, eventsOut)
+	}
+}
+
 func TestXml(t *testing.T) {
 	eventIn := models.Event{
 		Device: devID1,
@@ -63,6 +81,23 @@ func TestXml(t *testing.T) {
 	}
 }
 
+func TestXmlTags(t *testing.T) {
+	eventIn := models.Event{
+		Device: devID1,
+		Tags:   map[string]string{"site": "A", "batch": "42"},
+	}
+
+	xf := xmlFormatter{}
+	out := xf.Format(&eventIn)
+	if out == nil {
+		t.Fatal("out should not be nil")
+	}
+	expected := `<Tags><Tag key="batch">42</Tag><Tag key="site">A</Tag></Tags>`
+	if !strings.Contains(string(out), expected) {
+		t.Fatalf("Tags should be marshaled as %s: %s", expected, out)
+	}
+}
+
 func TestThingsBoardJson(t *testing.T) {
 	eventIn := models.Event{
 		Device: devID1,
@@ -148,3 +183,64 @@ func TestBIoT(t *testing.T) {
 		t.Fatalf("Error unmarshal the formatted string: %v %v", err, out)
 	}
 }
+
+func TestTemplate(t *testing.T) {
+	eventIn := models.Event{
+		Device: devID1,
+		Origin: 1540000000123,
+		Readings: []models.Reading{
+			{Name: readingName1, Value: readingValue1},
+			{Name: "state", Value: "on"},
+		},
+	}
+
+	tf, err := newTemplateFormatter(`{"id":{{json .Device}},"ts":{{json (timestamp .Origin "RFC3339")}},"values":{{"{"}}` +
+		`{{range $i, $r := .Readings}}{{if $i}},{{end}}{{json $r.Name}}:{{if isNumber $r.Value}}{{number $r.Value}}{{else}}{{json $r.Value}}{{end}}{{end}}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := tf.Format(&eventIn)
+
+	expected := `{"id":"id1","ts":"2018-10-20T01:46:40.123Z","values":{"sensor1":123.45,"state":"on"}}`
+	if string(out) != expected {
+		t.Fatalf("Template should render %s instead of %s", expected, out)
+	}
+	var v interface{}
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatalf("Template should render JSON: %v", err)
+	}
+
+	tf, _ = newTemplateFormatter(`{{number .Device}}`)
+	if out := tf.Format(&eventIn); out != nil {
+		t.Fatalf("A failed execution should not render a payload: %s", out)
+	}
+
+	if _, err := newTemplateFormatter(`{{.Device`); err == nil {
+		t.Fatal("An invalid template should not make a formatter")
+	}
+}
+
+func TestLineProtocol(t *testing.T) {
+	eventsIn := []*models.Event{
+		{ID: "e1", Device: "line 1,a", Origin: 1540000000000, Readings: []models.Reading{
+			{Name: readingName1, Value: readingValue1, Created: 1540000000100, Origin: 1540000000050},
+			{Name: "state", Value: "on"},
+			{Name: "count", Value: "7", Created: 1540000000100},
+		}},
+		{Device: devID1, Readings: []models.Reading{{Device: devID1, Name: readingName1, Value: "-1", Created: 1540000000200}}},
+	}
+
+	out := lineProtocolFormatter{}.FormatBatch(eventsIn)
+	expected := `readings,device=line\ 1\,a,event_id=e1,resource_name=sensor1 value=123.45,created=1540000000100i,origin=1540000000050i 1540000000050000000
+readings,device=line\ 1\,a,event_id=e1,resource_name=count value=7,created=1540000000100i,origin=0i 1540000000000000000
+readings,device=id1,resource_name=sensor1 value=-1,created=1540000000200i,origin=0i 1540000000200000000
+`
+	if string(out) != expected {
+		t.Fatalf("Line protocol should be\n%s\ninstead of\n%s", expected, out)
+	}
+
+	out = lineProtocolFormatter{}.Format(&models.Event{Device: devID1, Readings: []models.Reading{{Name: "state", Value: "on"}}})
+	if out == nil || len(out) != 0 {
+		t.Fatalf("An event without numeric readings should render an empty payload: %v", out)
+	}
+}
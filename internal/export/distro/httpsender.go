@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
@@ -21,9 +22,15 @@ import (
 type httpSender struct {
 	url    string
 	method string
+	client *http.Client
 }
 
-const mimeTypeJSON = "application/json"
+const (
+	mimeTypeJSON = "application/json"
+
+	// A destination that does not answer in time is treated as unavailable, so that it cannot hold up its registration
+	httpSenderTimeout = 30000
+)
 
 // newHTTPSender - create http sender
 func newHTTPSender(addr models.Addressable) sender {
@@ -31,26 +38,54 @@ func newHTTPSender(addr models.Addressable) sender {
 	sender := httpSender{
 		url:    addr.Protocol + "://" + addr.Address + ":" + strconv.Itoa(addr.Port) + addr.Path,
 		method: addr.HTTPMethod,
+		client: &http.Client{Timeout: time.Duration(httpSenderTimeout) * time.Millisecond},
 	}
 	return sender
 }
 
 func (sender httpSender) Send(data []byte, event *models.Event) bool {
+	return sender.SendChecked(data, []*models.Event{event}) == sendSucceeded
+}
+
+// The payload of a batch is posted like the payload of a single event
+func (sender httpSender) SendBatch(data []byte, events []*models.Event) bool {
+	return sender.SendChecked(data, events) == sendSucceeded
+}
+
+func (sender httpSender) SendChecked(data []byte, events []*models.Event) sendOutcome {
 
 	switch sender.method {
 	case http.MethodPost:
-		response, err := http.Post(sender.url, mimeTypeJSON, bytes.NewReader(data))
+		response, err := sender.client.Post(sender.url, mimeTypeJSON, bytes.NewReader(data))
 		if err != nil {
 			LoggingClient.Error(err.Error())
-			return false
+			return sendFailed
 		}
 		defer response.Body.Close()
 		LoggingClient.Info(fmt.Sprintf("Response: %s", response.Status))
+		if outcome := httpOutcome(response.StatusCode); outcome != sendSucceeded {
+			return outcome
+		}
 	default:
 		LoggingClient.Info(fmt.Sprintf("Unsupported method: %s", sender.method))
-		return false
+		return sendRejected
 	}
 
 	LoggingClient.Info(fmt.Sprintf("Sent data: %X", data))
-	return true
+	return sendSucceeded
+}
+
+// Error statuses telling that the destination may accept the payload later, such as the ones of a proxy
+// in front of an unavailable destination, are failures to retry. Other client errors reject the payload.
+func httpOutcome(status int) sendOutcome {
+	switch {
+	case status >= http.StatusOK && status < http.StatusMultipleChoices:
+		return sendSucceeded
+	case status == http.StatusRequestTimeout, status == http.StatusTooManyRequests, status >= http.StatusInternalServerError:
+		return sendFailed
+	case status >= http.StatusBadRequest:
+		return sendRejected
+	default:
+		return sendFailed
+	}
 }
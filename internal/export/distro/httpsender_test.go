@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
@@ -103,3 +104,53 @@ func TestHttpSender(t *testing.T) {
 		})
 	}
 }
+
+func TestHttpSenderStatus(t *testing.T) {
+	var tests = []struct {
+		name    string
+		status  int
+		sent    bool
+		outcome sendOutcome
+	}{
+		{"ok", http.StatusOK, true, sendSucceeded},
+		{"noContent", http.StatusNoContent, true, sendSucceeded},
+		{"badRequest", http.StatusBadRequest, false, sendRejected},
+		{"unauthorized", http.StatusUnauthorized, false, sendRejected},
+		{"notFound", http.StatusNotFound, false, sendRejected},
+		{"requestTimeout", http.StatusRequestTimeout, false, sendFailed},
+		{"tooManyRequests", http.StatusTooManyRequests, false, sendFailed},
+		{"badGateway", http.StatusBadGateway, false, sendFailed},
+		{"unavailable", http.StatusServiceUnavailable, false, sendFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer ts.Close()
+
+			sender := httpSender{url: ts.URL, method: http.MethodPost, client: &http.Client{}}
+			if sent := sender.Send([]byte("test message"), nil); sent != tt.sent {
+				t.Errorf("Expected sent to be %v for status %d", tt.sent, tt.status)
+			}
+			if outcome := sender.SendChecked([]byte("test message"), nil); outcome != tt.outcome {
+				t.Errorf("Expected outcome %d for status %d, got %d", tt.outcome, tt.status, outcome)
+			}
+		})
+	}
+}
+
+func TestHttpSenderTimeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	sender := httpSender{url: ts.URL, method: http.MethodPost, client: &http.Client{Timeout: 50 * time.Millisecond}}
+	if outcome := sender.SendChecked([]byte("test message"), nil); outcome != sendFailed {
+		t.Errorf("Expected a destination that does not answer to be retried, got %d", outcome)
+	}
+}
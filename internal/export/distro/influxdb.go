@@ -7,111 +7,83 @@
 package distro
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/edgexfoundry/edgex-go/pkg/models"
-
-	"github.com/influxdata/influxdb/client/v2"
 )
 
 const (
 	influxDBTimeout = 60000
 )
 
+// influxdbSender writes the readings to InfluxDB as line protocol
 type influxdbSender struct {
-	client   client.Client
-	httpInfo client.HTTPConfig
-	database string
+	url      string
+	username string
+	password string
+	client   *http.Client
+	// The payload is already line protocol, otherwise the readings of the events are written
+	// timestamped with the time of the write
+	lineProtocol bool
 }
 
-func newInfluxDBSender(addr models.Addressable) sender {
-	connStr := "http://" + addr.Address + ":" + strconv.Itoa(addr.Port)
-
-	influxdbHTTPInfo := client.HTTPConfig{
-		Addr:     connStr,
-		Timeout:  time.Duration(influxDBTimeout) * time.Millisecond,
-		Username: addr.User,
-		Password: addr.Password,
-	}
-
-	db := addr.Topic
+func newInfluxDBSender(addr models.Addressable, lineProtocol bool) sender {
+	query := url.Values{}
+	query.Set("db", addr.Topic)
 
 	sender := &influxdbSender{
-		client:   nil,
-		httpInfo: influxdbHTTPInfo,
-		database: db,
+		url:          "http://" + addr.Address + ":" + strconv.Itoa(addr.Port) + "/write?" + query.Encode(),
+		username:     addr.User,
+		password:     addr.Password,
+		client:       &http.Client{Timeout: time.Duration(influxDBTimeout) * time.Millisecond},
+		lineProtocol: lineProtocol,
 	}
 
 	return sender
 }
 
 func (sender *influxdbSender) Send(data []byte, event *models.Event) bool {
-	if sender.client == nil {
-		LoggingClient.Info("Connecting to InfluxDB server")
-		c, err := client.NewHTTPClient(sender.httpInfo)
+	return sender.SendChecked(data, []*models.Event{event}) == sendSucceeded
+}
 
-		if err != nil {
-			LoggingClient.Error(fmt.Sprintf("Failed to connect to InfluxDB server: %s", err))
-			return false
-		}
+func (sender *influxdbSender) SendBatch(data []byte, events []*models.Event) bool {
+	return sender.SendChecked(data, events) == sendSucceeded
+}
 
-		sender.client = c
+// Write the readings of all the events in a single request
+func (sender *influxdbSender) SendChecked(data []byte, events []*models.Event) sendOutcome {
+	if !sender.lineProtocol {
+		data = lineProtocolFormatter{writeTime: true}.FormatBatch(events)
+	}
+	if len(data) == 0 {
+		// No numeric reading to write
+		return sendSucceeded
 	}
 
-	bp, err := client.NewBatchPoints(client.BatchPointsConfig{
-		Database:  sender.database,
-		Precision: "us",
-	})
-
+	request, err := http.NewRequest(http.MethodPost, sender.url, bytes.NewReader(data))
 	if err != nil {
-		LoggingClient.Error(fmt.Sprintf("Failed to craete batch points: %s", err))
-		return false
+		LoggingClient.Error(fmt.Sprintf("Failed to create InfluxDB write request: %s", err))
+		return sendRejected
 	}
-
-	for _, reading := range event.Readings {
-		value, err := strconv.ParseFloat(reading.Value, 64)
-
-		if err != nil {
-			// not a valid numerical reading value, just ignore it
-			continue
-		}
-
-		fields := map[string]interface{}{
-			"created": reading.Created,
-			"origin":  reading.Origin,
-			"value":   value,
-		}
-
-		tags := map[string]string{
-			"device":        reading.Device,
-			"resource_name": reading.Name,
-			"event_id":      event.ID,
-		}
-
-		pt, err := client.NewPoint(
-			"readings",
-			tags,
-			fields,
-			time.Now(),
-		)
-
-		if err != nil {
-			LoggingClient.Error(fmt.Sprintf("Failed to add data point: %s", err))
-			return false
-		}
-
-		bp.AddPoint(pt)
+	request.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if sender.username != "" {
+		request.SetBasicAuth(sender.username, sender.password)
 	}
 
-	err = sender.client.Write(bp)
-
+	response, err := sender.client.Do(request)
 	if err != nil {
 		LoggingClient.Error(fmt.Sprintf("Failed to write data points to InfluxDB server: %s", err))
-		sender.client = nil // Reset the client
-		return false
+		return sendFailed
 	}
-
-	return true
+	defer response.Body.Close()
+	outcome := httpOutcome(response.StatusCode)
+	if outcome != sendSucceeded {
+		LoggingClient.Error(fmt.Sprintf("Failed to write data points to InfluxDB server: %s", response.Status))
+	}
+	return outcome
 }
@@ -0,0 +1,75 @@
+//
+// Copyright (c) 2018
+// IOTech
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package distro
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestInfluxDBSender(t *testing.T) {
+	var body string
+	status := http.StatusNoContent
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/write" || r.URL.Query().Get("db") != "edgex" {
+			t.Errorf("Unexpected write to %s", r.URL)
+		}
+		if user, password, _ := r.BasicAuth(); user != "user" || password != "secret" {
+			t.Errorf("Unexpected credentials %s %s", user, password)
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	host, p, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(p)
+	addr := models.Addressable{Address: host, Port: port, Topic: "edgex", User: "user", Password: "secret"}
+	event := &models.Event{Device: devID1, Readings: []models.Reading{{Name: readingName1, Value: readingValue1, Origin: 1540000000000}}}
+
+	// The readings of the other formats are written as line protocol, timestamped with the time of the write
+	sender := newInfluxDBSender(addr, false)
+	before := time.Now().Truncate(time.Microsecond).UnixNano()
+	if !sender.Send([]byte("ignored"), event) {
+		t.Fatal("The readings should have been written")
+	}
+	expected := "readings,device=id1,resource_name=sensor1 value=123.45,created=0i,origin=1540000000000i "
+	if !strings.HasPrefix(body, expected) {
+		t.Fatalf("Unexpected line protocol %s", body)
+	}
+	if timestamp, err := strconv.ParseInt(strings.TrimSpace(body[len(expected):]), 10, 64); err != nil ||
+		timestamp < before || timestamp > time.Now().UnixNano() {
+		t.Errorf("The readings should be timestamped with the time of the write: %s", body)
+	}
+
+	// A line protocol payload is written as is
+	sender = newInfluxDBSender(addr, true)
+	if !sender.Send([]byte("payload"), event) || body != "payload" {
+		t.Errorf("The payload should have been written instead of %s", body)
+	}
+
+	status = http.StatusServiceUnavailable
+	if sender.Send([]byte("payload"), event) {
+		t.Error("A write answered with an error status should fail")
+	}
+
+	status = http.StatusBadRequest
+	if outcome := sender.(checkedSender).SendChecked([]byte("payload"), []*models.Event{event}); outcome != sendRejected {
+		t.Errorf("A write InfluxDB cannot parse should be rejected, got %d", outcome)
+	}
+}
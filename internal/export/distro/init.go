@@ -67,6 +67,12 @@ func Init(useConsul bool) bool {
 	if Configuration == nil {
 		return false
 	}
+	if Configuration.Queue.Enabled {
+		if err := openQueues(Configuration.Queue.Path); err != nil {
+			LoggingClient.Error(fmt.Sprintf("Error opening the registration queues: %s", err.Error()))
+			return false
+		}
+	}
 	if useConsul {
 		chConfig = make(chan interface{})
 		go listenForConfigChanges()
@@ -79,6 +85,7 @@ func Destruct() {
 		close(chConfig)
 		chConfig = nil
 	}
+	closeQueues()
 }
 
 func connectToConsul(conf *ConfigurationStruct) (*ConfigurationStruct, error) {
@@ -16,6 +16,8 @@ import (
 	"strings"
 
 	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/encoding"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/mqtt"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
@@ -82,3 +84,33 @@ func (sender *mqttSender) Send(data []byte, event *models.Event) bool {
 		return true
 	}
 }
+
+// MQTTReceiver receives the events core data publishes through an MQTT broker
+func MQTTReceiver(eventCh chan *models.Event) {
+	queue := Configuration.MessageQueue
+	topic := mqtt.SubscriptionTopic(queue)
+
+	opts := mqtt.NewClientOptions(queue)
+	// Subscriptions are lost with the session when the connection drops, subscribe on every connection
+	opts.SetOnConnectHandler(func(client MQTT.Client) {
+		LoggingClient.Info("Connected to incoming MQTT at: " + queue.Uri())
+		token := client.Subscribe(topic, byte(queue.Qos), func(client MQTT.Client, msg MQTT.Message) {
+			// MQTT messages have no headers, the encoding of the event is told by its first byte
+			if event := parseEvent(msg.Payload(), encoding.ContentTypeOf(msg.Payload())); event != nil {
+				LoggingClient.Info(fmt.Sprintf("Event received on %s from device: %s", msg.Topic(), event.Device))
+				eventCh <- event
+			}
+		})
+		if token.Wait() && token.Error() != nil {
+			LoggingClient.Error(fmt.Sprintf("Could not subscribe to %s: %s", topic, token.Error().Error()))
+		}
+	})
+	opts.SetConnectionLostHandler(func(client MQTT.Client, err error) {
+		LoggingClient.Warn(fmt.Sprintf("Lost the connection to incoming MQTT, reconnecting: %s", err.Error()))
+	})
+
+	LoggingClient.Info("Connecting to incoming MQTT at: " + queue.Uri())
+	go mqtt.Connect(MQTT.NewClient(opts), func(err error) {
+		LoggingClient.Error(fmt.Sprintf("Could not connect to incoming MQTT: %s", err.Error()))
+	})
+}
@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package distro
+
+import (
+	"testing"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/encoding"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/mqtt"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/mqtt/test"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestMQTTReceiver(t *testing.T) {
+	broker, err := test.NewBroker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	Configuration.MessageQueue = broker.MessageQueue()
+	Configuration.MessageQueue.Topic = "events"
+	Configuration.MessageQueue.TopicPerDevice = true
+	Configuration.MessageQueue.ClientId = "export-distro"
+
+	eventCh := make(chan *models.Event, 10)
+	MQTTReceiver(eventCh)
+
+	opts := mqtt.NewClientOptions(Configuration.MessageQueue)
+	opts.SetClientID("publisher")
+	publisher := MQTT.NewClient(opts)
+	if token := publisher.Connect(); token.Wait() && token.Error() != nil {
+		t.Fatal(token.Error())
+	}
+	defer publisher.Disconnect(0)
+
+	// Publish until the receiver has subscribed, the broker does not retain messages
+	for i := 0; i < 50; i++ {
+		publisher.Publish("events/Thermostat", 0, false, `{"device":"Thermostat"}`).Wait()
+		publisher.Publish("events/Thermostat", 0, false, `not an event`).Wait()
+		select {
+		case e := <-eventCh:
+			if e.Device != "Thermostat" {
+				t.Errorf("unexpected event %v", e)
+			}
+			expectCBOREvent(t, publisher, eventCh)
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	t.Fatal("timed out waiting for the event")
+}
+
+// Events published as CBOR are told from JSON ones by their first byte
+func expectCBOREvent(t *testing.T, publisher MQTT.Client, eventCh chan *models.Event) {
+	data, err := encoding.Marshal(models.Event{Device: "Meter", Readings: []models.Reading{{Name: "Spectrum", BinaryValue: []byte{0, 1, 2}}}}, clients.ContentCbor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publisher.Publish("events/Meter", 0, false, data).Wait()
+
+	select {
+	case e := <-eventCh:
+		if e.Device != "Meter" || len(e.Readings) != 1 || len(e.Readings[0].BinaryValue) != 3 {
+			t.Errorf("unexpected event %v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the CBOR event")
+	}
+}
@@ -0,0 +1,424 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package distro
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/export"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"go.etcd.io/bbolt"
+)
+
+// Times, in milliseconds, between the retries of the queued payloads when none are configured
+const (
+	defaultInitialBackoff = 1000
+	defaultMaxBackoff     = 60000
+)
+
+var errQueueFull = errors.New("queue full")
+
+// Holds a bucket per registration, nil when queuing is disabled
+var queueDB *bbolt.DB
+
+// Payload a registration failed to send, along with the events it was made from
+type queuedPayload struct {
+	Data   []byte         `json:"data"`
+	Events []models.Event `json:"events"`
+	// The event overflowed the registration and is yet to be filtered and formatted
+	Unformatted bool `json:"unformatted,omitempty"`
+}
+
+func (p queuedPayload) eventRefs() []*models.Event {
+	events := make([]*models.Event, len(p.Events))
+	for i := range p.Events {
+		events[i] = &p.Events[i]
+	}
+	return events
+}
+
+// Payloads of a registration waiting to be sent, kept on disk in the order they were made
+type sendQueue struct {
+	bucket []byte
+	max    int
+	mutex  sync.Mutex
+	depth  int
+}
+
+// Open the queue file, keeping the payloads queued before a restart
+func openQueues(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0700)); err != nil {
+		return err
+	}
+	b, err := bbolt.Open(path, os.FileMode(0600), &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return err
+	}
+	queueDB = b
+	return nil
+}
+
+func closeQueues() {
+	if queueDB != nil {
+		queueDB.Close()
+		queueDB = nil
+	}
+}
+
+// Delete the queues of the registrations removed while export distro was not running
+func pruneQueues(regs []export.Registration) {
+	if queueDB == nil {
+		return
+	}
+	names := make(map[string]bool)
+	for _, reg := range regs {
+		names[reg.Name] = true
+	}
+
+	var stale []string
+	queueDB.View(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bbolt.Bucket) error {
+			if !names[string(name)] {
+				stale = append(stale, string(name))
+			}
+			return nil
+		})
+	})
+	for _, name := range stale {
+		LoggingClient.Info(fmt.Sprintf("Deleting the queue of the removed registration %s", name))
+		deleteSendQueue(name)
+	}
+}
+
+// Open the queue of the registration
+func newSendQueue(name string, max int) (*sendQueue, error) {
+	q := &sendQueue{bucket: []byte(name), max: max}
+	err := queueDB.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(q.bucket)
+		if err != nil {
+			return err
+		}
+		q.depth = bucket.Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func deleteSendQueue(name string) error {
+	if queueDB == nil {
+		return nil
+	}
+	return queueDB.Update(func(tx *bbolt.Tx) error {
+		err := tx.DeleteBucket([]byte(name))
+		if err == bbolt.ErrBucketNotFound {
+			return nil
+		}
+		return err
+	})
+}
+
+// Number of payloads waiting to be sent
+func (q *sendQueue) size() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.depth
+}
+
+// Append the payload to the queue, unless the queue is full
+func (q *sendQueue) push(data []byte, events []*models.Event) error {
+	p := queuedPayload{Data: data, Events: make([]models.Event, len(events))}
+	for i, event := range events {
+		p.Events[i] = *event
+	}
+	return q.put(p)
+}
+
+// Append an event that overflowed the registration, formatted once the payloads before it are sent
+func (q *sendQueue) pushUnformatted(event *models.Event) error {
+	return q.put(queuedPayload{Events: []models.Event{*event}, Unformatted: true})
+}
+
+func (q *sendQueue) put(p queuedPayload) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.max > 0 && q.depth >= q.max {
+		return errQueueFull
+	}
+
+	value, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	err = queueDB.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(q.bucket)
+		if bucket == nil {
+			return bbolt.ErrBucketNotFound
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		return bucket.Put(key, value)
+	})
+	if err != nil {
+		return err
+	}
+	q.depth++
+	return nil
+}
+
+// Read the oldest payload. The key is nil when the queue is empty, and set along with the error
+// when the payload cannot be read back.
+func (q *sendQueue) peek() ([]byte, queuedPayload, error) {
+	var key []byte
+	var p queuedPayload
+	err := queueDB.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(q.bucket)
+		if bucket == nil {
+			return bbolt.ErrBucketNotFound
+		}
+		k, v := bucket.Cursor().First()
+		if k == nil {
+			return nil
+		}
+		key = append([]byte{}, k...)
+		return json.Unmarshal(v, &p)
+	})
+	return key, p, err
+}
+
+// Replace the payload queued under the key
+func (q *sendQueue) replace(key []byte, p queuedPayload) error {
+	value, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return queueDB.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(q.bucket)
+		if bucket == nil {
+			return bbolt.ErrBucketNotFound
+		}
+		return bucket.Put(key, value)
+	})
+}
+
+func (q *sendQueue) remove(key []byte) error {
+	err := queueDB.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(q.bucket)
+		if bucket == nil {
+			return bbolt.ErrBucketNotFound
+		}
+		return bucket.Delete(key)
+	})
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	q.depth--
+	q.mutex.Unlock()
+	return nil
+}
+
+// Open the queue of the registration and start sending its payloads in the background
+func (reg *registrationInfo) startQueue() {
+	if queueDB == nil || reg.queue != nil {
+		return
+	}
+	q, err := newSendQueue(reg.registration.Name, Configuration.Queue.MaxPayloads)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error opening the queue of registration %s: %s", reg.registration.Name, err.Error()))
+		return
+	}
+	if depth := q.size(); depth > 0 {
+		LoggingClient.Info(fmt.Sprintf("%d queued payloads to send for registration %s", depth, reg.registration.Name))
+	}
+
+	reg.mutex.Lock()
+	reg.queue = q
+	reg.chQueued = make(chan struct{}, 1)
+	reg.mutex.Unlock()
+	reg.chStopQueue = make(chan struct{})
+	reg.queueDone = make(chan struct{})
+	go reg.forward(q, reg.chQueued, reg.chStopQueue, reg.queueDone)
+	reg.wakeQueue()
+}
+
+// Stop sending the queued payloads, they are kept on disk
+func (reg *registrationInfo) stopQueue() {
+	if reg.queue == nil {
+		return
+	}
+	close(reg.chStopQueue)
+	<-reg.queueDone
+	reg.mutex.Lock()
+	reg.queue = nil
+	reg.mutex.Unlock()
+}
+
+func (reg *registrationInfo) wakeQueue() {
+	select {
+	case reg.chQueued <- struct{}{}:
+	default:
+	}
+}
+
+// Queue the payload behind the ones waiting to be sent
+func (reg *registrationInfo) enqueue(data []byte, events []*models.Event) {
+	count := float64(len(events))
+	if err := reg.queue.push(data, events); err != nil {
+		LoggingClient.Warn(fmt.Sprintf("Dropping %d events for registration %s: %s", len(events), reg.registration.Name, err.Error()))
+		exportSends.Add(count, reg.registration.Name, "dropped")
+		return
+	}
+	exportSends.Add(count, reg.registration.Name, "queued")
+	reg.wakeQueue()
+}
+
+// Queue an event the registration has no room for, dropping it when queuing is disabled.
+// Called by distro.Loop, which does not wait for the registration.
+func (reg *registrationInfo) overflow(name string, event *models.Event) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+	if reg.queue == nil {
+		LoggingClient.Warn(fmt.Sprintf("Dropping an event for registration %s: %d events waiting", name, registrationEventBuffer))
+		exportSends.Inc(name, "dropped")
+		return
+	}
+	if err := reg.queue.pushUnformatted(event); err != nil {
+		LoggingClient.Warn(fmt.Sprintf("Dropping an event for registration %s: %s", name, err.Error()))
+		exportSends.Inc(name, "dropped")
+		return
+	}
+	exportSends.Inc(name, "queued")
+	reg.wakeQueue()
+}
+
+// Filter, format and transform the event of an unformatted payload, as processEvent would.
+// The payload is not sent when the event is filtered out or cannot be formatted.
+func (reg *registrationInfo) formatQueued(name string, p queuedPayload) (queuedPayload, bool) {
+	event := reg.filterEvent(&p.Events[0])
+	if event == nil {
+		return p, false
+	}
+	formated := reg.formatPayload([]*models.Event{event})
+	if formated == nil {
+		LoggingClient.Warn(fmt.Sprintf("Events not formatted with registration: %s", name))
+		exportSends.Inc(name, "format_failed")
+		return p, false
+	}
+	return queuedPayload{Data: reg.transform(formated), Events: []models.Event{*event}}, true
+}
+
+// Send the queued payloads, oldest first. After a failure, sending is retried with a backoff
+// doubling up to the configured maximum.
+func (reg *registrationInfo) forward(q *sendQueue, chQueued <-chan struct{}, chStop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	name := string(q.bucket)
+	initial, max := Configuration.Queue.InitialBackoff, Configuration.Queue.MaxBackoff
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	if max < initial {
+		max = defaultMaxBackoff
+	}
+	backoff := initial
+
+	// Wait for the backoff, doubling it, unless the queue is stopped
+	wait := func() bool {
+		select {
+		case <-chStop:
+			return false
+		case <-time.After(time.Duration(backoff) * time.Millisecond):
+		}
+		if backoff *= 2; backoff > max {
+			backoff = max
+		}
+		return true
+	}
+
+	// Key of the payload sent, rejected or unreadable, removed from the queue before reading the next
+	// one. A payload that could not be removed is not sent again.
+	var handled []byte
+	for {
+		if handled != nil {
+			if err := q.remove(handled); err != nil {
+				LoggingClient.Error(fmt.Sprintf("Error removing a payload from the queue of registration %s, retrying in %d ms: %s", name, backoff, err.Error()))
+				if !wait() {
+					return
+				}
+				continue
+			}
+			handled = nil
+		}
+
+		key, p, err := q.peek()
+		if err != nil && key != nil {
+			LoggingClient.Error(fmt.Sprintf("Dropping unreadable payload queued for registration %s: %s", name, err.Error()))
+			handled = key
+			continue
+		}
+		if err != nil {
+			LoggingClient.Error(fmt.Sprintf("Error reading the queue of registration %s: %s", name, err.Error()))
+		} else if key == nil {
+			select {
+			case <-chStop:
+				return
+			case <-chQueued:
+			}
+			continue
+		} else {
+			if p.Unformatted {
+				var ok bool
+				if p, ok = reg.formatQueued(name, p); !ok {
+					handled = key
+					continue
+				}
+				// The filters are not applied twice when sending is retried
+				if err := q.replace(key, p); err != nil {
+					LoggingClient.Error(fmt.Sprintf("Error replacing a payload in the queue of registration %s: %s", name, err.Error()))
+				}
+			}
+			events := p.eventRefs()
+			switch sendEvents(reg.getSender(), p.Data, events) {
+			case sendSucceeded:
+				exportSends.Add(float64(len(events)), name, "sent")
+				markPushed(events)
+				handled = key
+				backoff = initial
+				continue
+			case sendRejected:
+				// Retrying would hold back the payloads queued behind
+				LoggingClient.Warn(fmt.Sprintf("Dropping a payload of %d events the destination of registration %s rejected", len(events), name))
+				exportSends.Add(float64(len(events)), name, "rejected")
+				handled = key
+				backoff = initial
+				continue
+			default:
+				exportSends.Add(float64(len(events)), name, "failed")
+				LoggingClient.Warn(fmt.Sprintf("Registration %s failed to send, %d payloads queued, retrying in %d ms", name, q.size(), backoff))
+			}
+		}
+
+		if !wait() {
+			return
+		}
+	}
+}
@@ -0,0 +1,292 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package distro
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/export"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func openTestQueues(t *testing.T) (path string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path = filepath.Join(dir, "queue.db")
+	if err := openQueues(path); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, func() {
+		closeQueues()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSendQueue(t *testing.T) {
+	path, cleanup := openTestQueues(t)
+	defer cleanup()
+
+	q, err := newSendQueue("reg", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, device := range []string{"dev1", "dev2"} {
+		if err := q.push([]byte(device), []*models.Event{{Device: device}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.push([]byte("dev3"), []*models.Event{{Device: "dev3"}}); err != errQueueFull {
+		t.Fatalf("The queue should be full: %v", err)
+	}
+
+	key, p, err := q.peek()
+	if err != nil || key == nil || string(p.Data) != "dev1" || p.Events[0].Device != "dev1" {
+		t.Fatalf("The oldest payload should be read first: %v %v", p, err)
+	}
+	if err := q.remove(key); err != nil {
+		t.Fatal(err)
+	}
+
+	// The payloads are kept across restarts
+	closeQueues()
+	if err := openQueues(path); err != nil {
+		t.Fatal(err)
+	}
+	q, err = newSendQueue("reg", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.size() != 1 {
+		t.Fatalf("One payload should be queued, got %d", q.size())
+	}
+	if _, p, _ = q.peek(); string(p.Data) != "dev2" {
+		t.Fatalf("The second payload should be queued: %s", p.Data)
+	}
+}
+
+func TestPruneQueues(t *testing.T) {
+	_, cleanup := openTestQueues(t)
+	defer cleanup()
+
+	for _, name := range []string{"kept", "removed"} {
+		q, err := newSendQueue(name, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q.push([]byte(name), []*models.Event{{}})
+	}
+
+	pruneQueues([]export.Registration{{Name: "kept"}})
+
+	if q, _ := newSendQueue("kept", 0); q.size() != 1 {
+		t.Error("The queue of an existing registration should be kept")
+	}
+	if q, _ := newSendQueue("removed", 0); q.size() != 0 {
+		t.Error("The queue of a removed registration should be deleted")
+	}
+}
+
+// Sender failing the first sends and recording the payloads sent afterwards
+type failingSender struct {
+	mutex    sync.Mutex
+	failures int
+	sent     []string
+}
+
+func (sender *failingSender) Send(data []byte, event *models.Event) bool {
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+	if sender.failures > 0 {
+		sender.failures--
+		return false
+	}
+	sender.sent = append(sender.sent, string(data))
+	return true
+}
+
+func (sender *failingSender) payloads() []string {
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+	return append([]string(nil), sender.sent...)
+}
+
+type deviceFormatter struct{}
+
+func (deviceFormatter) Format(event *models.Event) []byte {
+	return []byte(event.Device)
+}
+
+func TestRegistrationQueue(t *testing.T) {
+	_, cleanup := openTestQueues(t)
+	defer cleanup()
+	Configuration.Queue = QueueInfo{Enabled: true, InitialBackoff: 1, MaxBackoff: 4}
+	defer func() { Configuration.Queue = QueueInfo{} }()
+
+	sender := &failingSender{failures: 3}
+	ri := newRegistrationInfo()
+	ri.registration.Name = "queued"
+	ri.format = deviceFormatter{}
+	ri.sender = sender
+	ri.startQueue()
+	defer ri.stopQueue()
+
+	expected := []string{"dev1", "dev2", "dev3"}
+	for _, device := range expected {
+		ri.processEvent(&models.Event{Device: device})
+	}
+
+	for i := 0; i < 100 && len(sender.payloads()) < len(expected); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sent := sender.payloads(); !reflect.DeepEqual(sent, expected) {
+		t.Fatalf("The payloads should be sent in order once the destination is back: %v", sent)
+	}
+	if ri.queue.size() != 0 {
+		t.Errorf("The queue should be empty, got %d", ri.queue.size())
+	}
+	if v := exportSends.Value("queued", "queued"); v != 3 {
+		t.Errorf("expected 3 payloads queued, got %g", v)
+	}
+	if v := exportSends.Value("queued", "failed"); v != 3 {
+		t.Errorf("expected 3 failed sends, got %g", v)
+	}
+}
+
+// Sender rejecting the payloads of a device and recording the payloads sent
+type rejectingSender struct {
+	failingSender
+	rejected string
+}
+
+func (sender *rejectingSender) SendChecked(data []byte, events []*models.Event) sendOutcome {
+	if string(data) == sender.rejected {
+		return sendRejected
+	}
+	if !sender.Send(data, events[0]) {
+		return sendFailed
+	}
+	return sendSucceeded
+}
+
+func TestRegistrationQueueRejected(t *testing.T) {
+	_, cleanup := openTestQueues(t)
+	defer cleanup()
+	Configuration.Queue = QueueInfo{Enabled: true, InitialBackoff: 1, MaxBackoff: 4}
+	defer func() { Configuration.Queue = QueueInfo{} }()
+
+	sender := &rejectingSender{failingSender: failingSender{failures: 1}, rejected: "dev2"}
+	ri := newRegistrationInfo()
+	ri.registration.Name = "rejected"
+	ri.format = deviceFormatter{}
+	ri.sender = sender
+	ri.startQueue()
+	defer ri.stopQueue()
+
+	for _, device := range []string{"dev1", "dev2", "dev3"} {
+		ri.processEvent(&models.Event{Device: device})
+	}
+
+	expected := []string{"dev1", "dev3"}
+	for i := 0; i < 100 && len(sender.payloads()) < len(expected); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sent := sender.payloads(); !reflect.DeepEqual(sent, expected) {
+		t.Fatalf("The payloads queued behind a rejected payload should be sent: %v", sent)
+	}
+	if v := exportSends.Value("rejected", "rejected"); v != 1 {
+		t.Errorf("expected 1 event rejected, got %g", v)
+	}
+}
+
+// Sender deleting the queue of the registration, so that the payloads sent cannot be removed
+type queueDeletingSender struct {
+	failingSender
+	name string
+}
+
+func (sender *queueDeletingSender) Send(data []byte, event *models.Event) bool {
+	deleteSendQueue(sender.name)
+	return sender.failingSender.Send(data, event)
+}
+
+func TestRegistrationQueueRemoveError(t *testing.T) {
+	_, cleanup := openTestQueues(t)
+	defer cleanup()
+	Configuration.Queue = QueueInfo{Enabled: true, InitialBackoff: 1, MaxBackoff: 4}
+	defer func() { Configuration.Queue = QueueInfo{} }()
+
+	sender := &queueDeletingSender{name: "removeError"}
+	ri := newRegistrationInfo()
+	ri.registration.Name = "removeError"
+	ri.sender = sender
+	ri.startQueue()
+	defer ri.stopQueue()
+
+	ri.enqueue([]byte("dev1"), []*models.Event{{Device: "dev1"}})
+	time.Sleep(100 * time.Millisecond)
+
+	if sent := sender.payloads(); !reflect.DeepEqual(sent, []string{"dev1"}) {
+		t.Fatalf("A payload sent should not be sent again when it cannot be removed: %v", sent)
+	}
+}
+
+func TestRegistrationOverflow(t *testing.T) {
+	_, cleanup := openTestQueues(t)
+	defer cleanup()
+	Configuration.Queue = QueueInfo{Enabled: true, InitialBackoff: 1, MaxBackoff: 4}
+	defer func() { Configuration.Queue = QueueInfo{} }()
+
+	sender := &failingSender{failures: 1}
+	ri := newRegistrationInfo()
+	ri.registration.Name = "overflow"
+	ri.format = deviceFormatter{}
+	ri.sender = sender
+	ri.filter = []filterer{
+		newDevIdFilter(export.Filter{DeviceIDs: []string{"dev1", "dev2"}}),
+		newDeadbandFilter(export.Filter{Deadband: &export.Deadband{}}),
+	}
+	ri.startQueue()
+	defer ri.stopQueue()
+
+	reading := []models.Reading{{Name: "sensor", Value: "1"}}
+	ri.overflow("overflow", &models.Event{Device: "dev1", Readings: reading})
+	ri.overflow("overflow", &models.Event{Device: "filterOutDev", Readings: reading})
+	ri.overflow("overflow", &models.Event{Device: "dev2", Readings: reading})
+
+	// The first send fails, the event is sent again without going through the deadband twice
+	expected := []string{"dev1", "dev2"}
+	for i := 0; i < 100 && len(sender.payloads()) < len(expected); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sent := sender.payloads(); !reflect.DeepEqual(sent, expected) {
+		t.Fatalf("The events that overflowed should be filtered, formatted and sent: %v", sent)
+	}
+	if v := exportSends.Value("overflow", "queued"); v != 3 {
+		t.Errorf("expected 3 events queued, got %g", v)
+	}
+	if v := exportSends.Value("overflow", "filtered"); v != 1 {
+		t.Errorf("expected 1 event filtered, got %g", v)
+	}
+}
+
+func TestRegistrationOverflowDisabled(t *testing.T) {
+	ri := newRegistrationInfo()
+	ri.registration.Name = "overflowDisabled"
+	ri.overflow("overflowDisabled", &models.Event{Device: "dev1"})
+	if v := exportSends.Value("overflowDisabled", "dropped"); v != 1 {
+		t.Errorf("expected 1 event dropped, got %g", v)
+	}
+}
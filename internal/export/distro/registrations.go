@@ -10,26 +10,30 @@
 
 package distro
 
-// TODO:
-// - Event buffer management per sender(do not block distro.Loop on full
-//   registration channel)
-
 import (
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/edgexfoundry/edgex-go/internal/export"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
 const (
 	awsMQTTPort         int    = 8883
 	awsThingUpdateTopic string = "$aws/things/%s/shadow/update"
+
+	// Events waiting to be processed by a registration before distro.Loop queues them on disk
+	registrationEventBuffer int = 64
 )
 
 var registrationChanges chan models.NotifyUpdate = make(chan models.NotifyUpdate, 2)
 
+var exportSends = metrics.NewCounter("edgex_export_sends_total",
+	"Events processed by each registration, by outcome: filtered, format_failed, sent, failed, rejected, queued or dropped.", "registration", "outcome")
+
 // RegistrationInfo - registration info
 type registrationInfo struct {
 	registration export.Registration
@@ -38,11 +42,29 @@ type registrationInfo struct {
 	encrypt      transformer
 	sender       sender
 	filter       []filterer
+	// Guards the settings the queue uses while the registration is updated: the sender, and the filters,
+	// formatter and transformers of the events that overflowed the registration. Also guards the queue,
+	// which distro.Loop writes these events to.
+	mutex sync.Mutex
 
 	chRegistration chan *export.Registration
 	chEvent        chan *models.Event
 
+	// Payloads the sender failed to send, nil when queuing is disabled
+	queue       *sendQueue
+	chQueued    chan struct{}
+	chStopQueue chan struct{}
+	queueDone   chan struct{}
+
+	// Events waiting to be formatted and sent together when batching is enabled
+	batch         []*models.Event
+	batchBytes    int
+	batchTimer    *time.Timer
+	chBatchWindow <-chan time.Time
+
 	deleteFlag bool
+	// The registration was deleted, its queue is deleted once the goroutine stops sending it
+	removed bool
 }
 
 func RefreshRegistrations(update models.NotifyUpdate) {
@@ -54,11 +76,14 @@ func newRegistrationInfo() *registrationInfo {
 	reg := &registrationInfo{}
 
 	reg.chRegistration = make(chan *export.Registration)
-	reg.chEvent = make(chan *models.Event)
+	reg.chEvent = make(chan *models.Event, registrationEventBuffer)
 	return reg
 }
 
 func (reg *registrationInfo) update(newReg export.Registration) bool {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
 	reg.registration = newReg
 
 	reg.format = nil
@@ -76,15 +101,36 @@ func (reg *registrationInfo) update(newReg export.Registration) bool {
 	case export.FormatAWSJSON:
 		reg.format = awsFormatter{}
 	case export.FormatCSV:
-		// TODO reg.format = distro.NewCsvFormat()
+		if err := newReg.CSV.Validate(); err != nil {
+			LoggingClient.Warn(err.Error())
+			return false
+		}
+		reg.format = newCSVFormatter(newReg.CSV)
 	case export.FormatThingsBoardJSON:
 		reg.format = thingsboardJSONFormatter{}
 	case export.FormatNOOP:
 		reg.format = noopFormatter{}
+	case export.FormatInfluxDBLine:
+		reg.format = lineProtocolFormatter{}
+	case export.FormatTemplate:
+		f, err := newTemplateFormatter(newReg.Template)
+		if err != nil {
+			LoggingClient.Warn(fmt.Sprintf("Template not supported: %s", err.Error()))
+			return false
+		}
+		reg.format = f
 	default:
 		LoggingClient.Warn(fmt.Sprintf("Format not supported: %s", newReg.Format))
 		return false
 	}
+	if _, ok := reg.format.(batchFormatter); newReg.Batch.Enabled() && !ok {
+		LoggingClient.Warn(fmt.Sprintf("Format does not support batches: %s", newReg.Format))
+		return false
+	}
+	if err := newReg.Batch.Validate(newReg.Format, newReg.Destination, newReg.Compression, newReg.Encryption.Algo); err != nil {
+		LoggingClient.Warn(err.Error())
+		return false
+	}
 
 	reg.compression = nil
 	switch newReg.Compression {
@@ -122,7 +168,7 @@ func (reg *registrationInfo) update(newReg export.Registration) bool {
 	case export.DestXMPP:
 		reg.sender = newXMPPSender(newReg.Addressable)
 	case export.DestInfluxDB:
-		reg.sender = newInfluxDBSender(newReg.Addressable)
+		reg.sender = newInfluxDBSender(newReg.Addressable, newReg.Format == export.FormatInfluxDBLine)
 
 	default:
 		LoggingClient.Warn(fmt.Sprintf("Destination not supported: %s", newReg.Destination))
@@ -158,26 +204,90 @@ func (reg *registrationInfo) update(newReg export.Registration) bool {
 		LoggingClient.Debug(fmt.Sprintf("Value descriptor filter added: %s", newReg.Filter.ValueDescriptorIDs))
 	}
 
+	if len(newReg.Filter.Tags) > 0 {
+		reg.filter = append(reg.filter, newTagFilter(newReg.Filter))
+		LoggingClient.Debug(fmt.Sprintf("Tag filter added: %v", newReg.Filter.Tags))
+	}
+
+	if newReg.Filter.Expression != "" {
+		f, err := newExpressionFilter(newReg.Filter)
+		if err != nil {
+			LoggingClient.Warn(fmt.Sprintf("Filter expression not supported: %s", err.Error()))
+			return false
+		}
+		reg.filter = append(reg.filter, f)
+		LoggingClient.Debug(fmt.Sprintf("Expression filter added: %s", newReg.Filter.Expression))
+	}
+
+	// The deadband remembers the values exported, it comes after the other filters
+	if newReg.Filter.Deadband != nil {
+		reg.filter = append(reg.filter, newDeadbandFilter(newReg.Filter))
+		LoggingClient.Debug(fmt.Sprintf("Deadband filter added: %g", newReg.Filter.Deadband.Threshold))
+	}
+
 	return true
 }
 
-func (reg registrationInfo) processEvent(event *models.Event) {
+func (reg *registrationInfo) getSender() sender {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+	return reg.sender
+}
+
+func (reg *registrationInfo) processEvent(event *models.Event) {
 	// Valid Event Filter, needed?
 
+	if event = reg.filterEvent(event); event == nil {
+		return
+	}
+
+	if reg.format == nil {
+		LoggingClient.Warn("registrationInfo with nil format")
+		return
+	}
+
+	if reg.registration.Batch.Enabled() {
+		reg.addToBatch(event)
+		return
+	}
+	reg.sendPayload(reg.formatPayload([]*models.Event{event}), []*models.Event{event})
+}
+
+// Apply the filters to the event, nil when the event is filtered out
+func (reg *registrationInfo) filterEvent(event *models.Event) *models.Event {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
 	for _, f := range reg.filter {
 		var accepted bool
 		accepted, event = f.Filter(event)
 		if !accepted {
 			LoggingClient.Info("Event filtered")
-			return
+			exportSends.Inc(reg.registration.Name, "filtered")
+			return nil
 		}
 	}
+	return event
+}
+
+// Format the events in a single payload, as a batch when batching is enabled
+func (reg *registrationInfo) formatPayload(events []*models.Event) []byte {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
 
 	if reg.format == nil {
-		LoggingClient.Warn("registrationInfo with nil format")
-		return
+		return nil
+	}
+	if reg.registration.Batch.Enabled() {
+		return reg.format.(batchFormatter).FormatBatch(events)
 	}
-	formated := reg.format.Format(event)
+	return reg.format.Format(events[0])
+}
+
+// Compress and encrypt the payload
+func (reg *registrationInfo) transform(formated []byte) []byte {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
 
 	compressed := formated
 	if reg.compression != nil {
@@ -188,29 +298,143 @@ func (reg registrationInfo) processEvent(event *models.Event) {
 	if reg.encrypt != nil {
 		encrypted = reg.encrypt.Transform(compressed)
 	}
+	return encrypted
+}
 
-	if reg.sender.Send(encrypted, event) && Configuration.MarkPushed {
-		id := event.ID
-		err := ec.MarkPushed(id)
+// Add the event to the batch, sending the batch once it is full
+func (reg *registrationInfo) addToBatch(event *models.Event) {
+	opts := reg.registration.Batch
+	if len(reg.batch) == 0 && opts.Window > 0 {
+		reg.batchTimer = time.NewTimer(time.Duration(opts.Window) * time.Millisecond)
+		reg.chBatchWindow = reg.batchTimer.C
+	}
+	reg.batch = append(reg.batch, event)
+	reg.batchBytes += eventSize(event)
 
-		if err != nil {
-			LoggingClient.Error(fmt.Sprintf("Failed to mark event as pushed : event ID = %s: %s", id, err))
+	if (opts.MaxEvents > 0 && len(reg.batch) >= opts.MaxEvents) ||
+		(opts.MaxBytes > 0 && reg.batchBytes >= opts.MaxBytes) {
+		reg.flushBatch()
+	}
+}
+
+// Format and send the events of the batch
+func (reg *registrationInfo) flushBatch() {
+	if reg.batchTimer != nil {
+		reg.batchTimer.Stop()
+		reg.batchTimer = nil
+		reg.chBatchWindow = nil
+	}
+	if len(reg.batch) == 0 {
+		return
+	}
+	events := reg.batch
+	reg.batch = nil
+	reg.batchBytes = 0
+
+	LoggingClient.Debug(fmt.Sprintf("Sending a batch of %d events with registration: %s", len(events), reg.registration.Name))
+	reg.sendPayload(reg.formatPayload(events), events)
+}
+
+// Approximate size of the event, from the names and values of its readings
+func eventSize(event *models.Event) int {
+	size := 0
+	for _, reading := range event.Readings {
+		size += len(reading.Name) + len(reading.Value) + len(reading.BinaryValue)
+	}
+	return size
+}
+
+// Compress, encrypt and send the payload made from the events
+func (reg *registrationInfo) sendPayload(formated []byte, events []*models.Event) {
+	count := float64(len(events))
+	if formated == nil {
+		// The formatter already logged why the events could not be formatted
+		LoggingClient.Warn(fmt.Sprintf("Events not formatted with registration: %s", reg.registration.Name))
+		exportSends.Add(count, reg.registration.Name, "format_failed")
+		return
+	}
+
+	encrypted := reg.transform(formated)
+
+	// Keep the order of the events while payloads are waiting to be sent
+	if reg.queue != nil && reg.queue.size() > 0 {
+		reg.enqueue(encrypted, events)
+		return
+	}
+
+	switch sendEvents(reg.sender, encrypted, events) {
+	case sendFailed:
+		exportSends.Add(count, reg.registration.Name, "failed")
+		if reg.queue != nil {
+			reg.enqueue(encrypted, events)
 		}
+		return
+	case sendRejected:
+		LoggingClient.Warn(fmt.Sprintf("Destination of registration %s rejected %d events", reg.registration.Name, len(events)))
+		exportSends.Add(count, reg.registration.Name, "rejected")
+		return
 	}
+	exportSends.Add(count, reg.registration.Name, "sent")
+	markPushed(events)
 
 	LoggingClient.Debug(fmt.Sprintf("Sent event with registration: %s", reg.registration.Name))
 }
 
+// Send the payload of the events. Senders that do not send batches are given the first event, and
+// the payloads of senders that cannot tell rejections apart are sent again after any failure.
+func sendEvents(s sender, data []byte, events []*models.Event) sendOutcome {
+	if cs, ok := s.(checkedSender); ok {
+		return cs.SendChecked(data, events)
+	}
+	var sent bool
+	if bs, ok := s.(batchSender); ok && len(events) > 1 {
+		sent = bs.SendBatch(data, events)
+	} else {
+		sent = s.Send(data, events[0])
+	}
+	if !sent {
+		return sendFailed
+	}
+	return sendSucceeded
+}
+
+func markPushed(events []*models.Event) {
+	if Configuration.MarkPushed {
+		for _, event := range events {
+			id := event.ID
+			err := ec.MarkPushed(id)
+
+			if err != nil {
+				LoggingClient.Error(fmt.Sprintf("Failed to mark event as pushed : event ID = %s: %s", id, err))
+			}
+		}
+	}
+}
+
 func registrationLoop(reg *registrationInfo) {
 	LoggingClient.Info(fmt.Sprintf("registration loop started: %s", reg.registration.Name))
+	reg.startQueue()
+	defer reg.stopQueue()
+
 	for {
 		select {
 		case event := <-reg.chEvent:
 			reg.processEvent(event)
 
+		case <-reg.chBatchWindow:
+			reg.flushBatch()
+
 		case newReg := <-reg.chRegistration:
+			// Send the events batched with the former settings
+			reg.flushBatch()
 			if newReg == nil {
 				LoggingClient.Info("Terminating registration goroutine")
+				if reg.removed {
+					reg.stopQueue()
+					if err := deleteSendQueue(reg.registration.Name); err != nil {
+						LoggingClient.Error(fmt.Sprintf("Error deleting the queue of registration %s: %s", reg.registration.Name, err.Error()))
+					}
+				}
 				return
 			} else {
 				if reg.update(*newReg) {
@@ -232,6 +456,7 @@ func updateRunningRegistrations(running map[string]*registrationInfo,
 	case export.NotifyUpdateDelete:
 		for k, v := range running {
 			if k == update.Name {
+				v.removed = true
 				v.chRegistration <- nil
 				delete(running, k)
 				return nil
@@ -292,6 +517,8 @@ func Loop(errChan chan error, eventCh chan *models.Event) {
 		allRegs, err = getRegistrations()
 	}
 
+	pruneQueues(allRegs)
+
 	// Create new goroutines for each registration
 	for _, reg := range allRegs {
 		regInfo := newRegistrationInfo()
@@ -329,8 +556,12 @@ func Loop(errChan chan error, eventCh chan *models.Event) {
 				if reg.deleteFlag {
 					delete(registrations, k)
 				} else {
-					// TODO only sent event if it is not blocking
-					reg.chEvent <- event
+					select {
+					case reg.chEvent <- event:
+					default:
+						// The registration is busy sending, do not hold back the other registrations
+						reg.overflow(k, event)
+					}
 				}
 			}
 		}
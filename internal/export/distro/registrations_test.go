@@ -7,7 +7,9 @@
 package distro
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/edgex-go/internal/export"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
@@ -62,6 +64,49 @@ func TestRegistrationInfoUpdate(t *testing.T) {
 	if ri.update(r) {
 		t.Fatal("Registration with invalid fields")
 	}
+
+	r = validRegistration()
+	r.Format = export.FormatCSV
+	if !ri.update(r) || ri.format == nil {
+		t.Fatal("A CSV registration should have a formatter")
+	}
+
+	r.CSV.Delimiter = "\n"
+	if ri.update(r) {
+		t.Fatal("Registration with invalid fields")
+	}
+
+	r = validRegistration()
+	r.Filter.Expression = `temperature > 80`
+	r.Filter.Deadband = &export.Deadband{Threshold: 0.5}
+	if !ri.update(r) || len(ri.filter) != 4 {
+		t.Fatal("Registration should have the expression and deadband filters")
+	}
+
+	r.Filter.Expression = `temperature >`
+	if ri.update(r) {
+		t.Fatal("Registration with invalid fields")
+	}
+
+	r = validRegistration()
+	r.Format = export.FormatInfluxDBLine
+	r.Destination = export.DestInfluxDB
+	r.Compression = export.CompGzip
+	if ri.update(r) {
+		t.Fatal("Line protocol payloads cannot be compressed")
+	}
+
+	r = validRegistration()
+	r.Format = export.FormatTemplate
+	r.Template = `{{.Device}}`
+	if !ri.update(r) || ri.format == nil {
+		t.Fatal("A template registration should have a formatter")
+	}
+
+	r.Template = `{{.Device`
+	if ri.update(r) {
+		t.Fatal("Registration with invalid fields")
+	}
 }
 
 type dummyStruct struct {
@@ -117,6 +162,136 @@ func TestRegistrationInfoEvent(t *testing.T) {
 	}
 }
 
+func TestRegistrationInfoEventMetrics(t *testing.T) {
+	ri := newRegistrationInfo()
+	ri.registration.Name = "metrics"
+	dummy := &dummyStruct{}
+	ri.format = dummy
+	ri.sender = dummy
+
+	f := export.Filter{}
+	f.DeviceIDs = append(f.DeviceIDs, "dummyDev")
+	ri.filter = append(ri.filter, newDevIdFilter(f))
+
+	ri.processEvent(&models.Event{Device: "dummyDev"})
+	ri.processEvent(&models.Event{Device: "dummyDev"})
+	ri.processEvent(&models.Event{Device: "filterOutDev"})
+
+	if v := exportSends.Value("metrics", "sent"); v != 2 {
+		t.Errorf("expected 2 events sent, got %g", v)
+	}
+	if v := exportSends.Value("metrics", "filtered"); v != 1 {
+		t.Errorf("expected 1 event filtered, got %g", v)
+	}
+}
+
+func TestRegistrationInfoFormatFailed(t *testing.T) {
+	f, err := newTemplateFormatter(`{{number .Device}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ri := newRegistrationInfo()
+	ri.registration.Name = "formatFailed"
+	dummy := &dummyStruct{}
+	ri.format = f
+	ri.sender = dummy
+
+	ri.processEvent(&models.Event{Device: "12"})
+	ri.processEvent(&models.Event{Device: "dummyDev"})
+
+	if dummy.count != 1 {
+		t.Errorf("Only the formatted event should be sent, sent %d", dummy.count)
+	}
+	if v := exportSends.Value("formatFailed", "sent"); v != 1 {
+		t.Errorf("expected 1 event sent, got %g", v)
+	}
+	if v := exportSends.Value("formatFailed", "format_failed"); v != 1 {
+		t.Errorf("expected 1 event not formatted, got %g", v)
+	}
+}
+
+// Sender recording the events of each payload, sending batches at once
+type batchRecorder struct {
+	payloads [][]string
+}
+
+func (sender *batchRecorder) Send(data []byte, event *models.Event) bool {
+	return sender.SendBatch(data, []*models.Event{event})
+}
+
+func (sender *batchRecorder) SendBatch(data []byte, events []*models.Event) bool {
+	var devices []string
+	for _, event := range events {
+		devices = append(devices, event.Device)
+	}
+	sender.payloads = append(sender.payloads, devices)
+	return true
+}
+
+func TestRegistrationInfoBatch(t *testing.T) {
+	recorder := &batchRecorder{}
+	ri := newRegistrationInfo()
+	ri.registration.Name = "batch"
+	ri.registration.Batch = export.BatchOptions{MaxEvents: 2, MaxBytes: 10}
+	ri.format = jsonFormatter{}
+	ri.sender = recorder
+
+	ri.processEvent(&models.Event{Device: "dev1"})
+	ri.processEvent(&models.Event{Device: "dev2"})
+	ri.processEvent(&models.Event{Device: "dev3", Readings: []models.Reading{{Name: "sensor", Value: "12345"}}})
+	ri.processEvent(&models.Event{Device: "dev4"})
+	if len(recorder.payloads) != 2 {
+		t.Fatalf("Batches should be sent once full: %v", recorder.payloads)
+	}
+	if !reflect.DeepEqual(recorder.payloads[0], []string{"dev1", "dev2"}) {
+		t.Errorf("The batch should be sent once it holds 2 events: %v", recorder.payloads[0])
+	}
+	if !reflect.DeepEqual(recorder.payloads[1], []string{"dev3"}) {
+		t.Errorf("The batch should be sent once it holds 10 bytes: %v", recorder.payloads[1])
+	}
+
+	ri.flushBatch()
+	if len(recorder.payloads) != 3 || !reflect.DeepEqual(recorder.payloads[2], []string{"dev4"}) {
+		t.Errorf("The remaining events should be sent: %v", recorder.payloads)
+	}
+	ri.flushBatch()
+	if len(recorder.payloads) != 3 {
+		t.Errorf("An empty batch should not be sent: %v", recorder.payloads)
+	}
+	if v := exportSends.Value("batch", "sent"); v != 4 {
+		t.Errorf("expected 4 events sent, got %g", v)
+	}
+}
+
+func TestRegistrationInfoBatchWindow(t *testing.T) {
+	recorder := &batchRecorder{}
+	ri := newRegistrationInfo()
+	ri.registration.Batch = export.BatchOptions{MaxEvents: 100, Window: 10}
+	ri.format = jsonFormatter{}
+	ri.sender = recorder
+
+	ri.chEvent <- &models.Event{Device: "dev1"}
+	ri.chEvent <- &models.Event{Device: "dev2"}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		ri.chRegistration <- nil
+	}()
+	registrationLoop(ri)
+
+	if len(recorder.payloads) != 1 || !reflect.DeepEqual(recorder.payloads[0], []string{"dev1", "dev2"}) {
+		t.Errorf("The batch should be sent at the end of the window: %v", recorder.payloads)
+	}
+}
+
+func TestRegistrationInfoBatchFormat(t *testing.T) {
+	r := validRegistration()
+	r.Format = export.FormatXML
+	r.Batch.MaxEvents = 10
+	if newRegistrationInfo().update(r) {
+		t.Fatal("The XML format does not support batches")
+	}
+}
+
 func TestRegistrationInfoLoop(t *testing.T) {
 	ri := newRegistrationInfo()
 	ri.update(validRegistration())
@@ -191,6 +366,41 @@ func TestUpdateRunningRegistrations(t *testing.T) {
 
 }
 
+func TestUpdateRunningRegistrationsDelete(t *testing.T) {
+	_, cleanup := openTestQueues(t)
+	defer cleanup()
+	Configuration.Queue = QueueInfo{Enabled: true, InitialBackoff: 1000, MaxBackoff: 1000}
+	defer func() { Configuration.Queue = QueueInfo{} }()
+
+	ri := newRegistrationInfo()
+	ri.registration.Name = "deleted"
+	ri.format = deviceFormatter{}
+	ri.sender = &failingSender{failures: 1}
+	done := make(chan struct{})
+	go func() {
+		registrationLoop(ri)
+		close(done)
+	}()
+	ri.chEvent <- &models.Event{Device: "dev1"}
+	for i := 0; i < 100 && exportSends.Value("deleted", "queued") == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	running := map[string]*registrationInfo{"deleted": ri}
+	if err := updateRunningRegistrations(running, models.NotifyUpdate{Name: "deleted",
+		Operation: export.NotifyUpdateDelete}); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+
+	if _, ok := running["deleted"]; ok {
+		t.Error("The registration should not be running")
+	}
+	if q, _ := newSendQueue("deleted", 0); q.size() != 0 {
+		t.Error("The queue of the deleted registration should be deleted")
+	}
+}
+
 func BenchmarkProcessEvent(b *testing.B) {
 	var Dummy = &dummyStruct{}
 
@@ -16,6 +16,7 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/internal"
 	"github.com/edgexfoundry/edgex-go/internal/export"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
 	"github.com/edgexfoundry/edgex-go/pkg/clients"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"github.com/gorilla/mux"
@@ -106,6 +107,7 @@ func encode(i interface{}, w http.ResponseWriter) {
 // HTTPServer function
 func httpServer() http.Handler {
 	r := mux.NewRouter()
+	r.Use(metrics.Middleware)
 
 	// Ping Resource
 	r.HandleFunc(clients.ApiPingRoute, pingHandler).Methods(http.MethodGet)
@@ -115,6 +117,7 @@ func httpServer() http.Handler {
 
 	// Metrics
 	r.HandleFunc(clients.ApiMetricsRoute, metricsHandler).Methods(http.MethodGet)
+	r.HandleFunc(clients.ApiPrometheusRoute, metrics.Handler).Methods(http.MethodGet)
 
 	r.HandleFunc(clients.ApiNotifyRegistrationRoute, replyNotifyRegistrations).Methods(http.MethodPut)
 
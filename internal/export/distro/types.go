@@ -16,11 +16,37 @@ type sender interface {
 	Send(data []byte, event *models.Event) bool
 }
 
+// BatchSender - Send the payload of several events at once
+type batchSender interface {
+	SendBatch(data []byte, events []*models.Event) bool
+}
+
+// Outcome of sending a payload
+type sendOutcome int
+
+const (
+	sendSucceeded sendOutcome = iota
+	// The payload may be accepted when sent again
+	sendFailed
+	// The destination refused the payload, sending it again would not help
+	sendRejected
+)
+
+// CheckedSender - Send the payload of events, telling the rejected payloads apart from the failures to retry
+type checkedSender interface {
+	SendChecked(data []byte, events []*models.Event) sendOutcome
+}
+
 // Formatter - Format interface
 type formatter interface {
 	Format(event *models.Event) []byte
 }
 
+// BatchFormatter - Format several events in a single payload
+type batchFormatter interface {
+	FormatBatch(events []*models.Event) []byte
+}
+
 // Transformer - Transform interface
 type transformer interface {
 	Transform(data []byte) []byte
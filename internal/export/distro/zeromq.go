@@ -8,10 +8,11 @@
 package distro
 
 import (
-	"encoding/json"
 	"fmt"
 	"sync"
 
+	"github.com/edgexfoundry/edgex-go/internal/pkg/encoding"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	zmq "github.com/pebbe/zmq4"
 )
@@ -59,24 +60,42 @@ func initZmq(eventCh chan *models.Event) {
 	q.SetSubscribe("")
 
 	for {
-		msg, err := q.RecvMessage(0)
+		msg, err := q.RecvMessageBytes(0)
 		if err != nil {
 			id, _ := q.GetIdentity()
 			LoggingClient.Error(fmt.Sprintf("Error getting message %s", id))
 		} else {
-			for _, str := range msg {
-				event := parseEvent(str)
-				LoggingClient.Info(fmt.Sprintf("Event received: %s", str))
+			for _, event := range parseMessage(msg) {
+				LoggingClient.Info(fmt.Sprintf("Event received from device: %s", event.Device))
 				eventCh <- event
 			}
 		}
 	}
 }
 
-func parseEvent(str string) *models.Event {
+// Core data sends the content type of the event followed by the encoded event.
+// Each part of the messages without a content type is a JSON event.
+func parseMessage(msg [][]byte) []*models.Event {
+	var events []*models.Event
+	if len(msg) == 2 && (string(msg[0]) == clients.ContentJson || string(msg[0]) == clients.ContentCbor) {
+		if event := parseEvent(msg[1], string(msg[0])); event != nil {
+			events = append(events, event)
+		}
+		return events
+	}
+
+	for _, data := range msg {
+		if event := parseEvent(data, clients.ContentJson); event != nil {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func parseEvent(data []byte, contentType string) *models.Event {
 	event := models.Event{}
 
-	if err := json.Unmarshal([]byte(str), &event); err != nil {
+	if err := encoding.Unmarshal(data, contentType, &event); err != nil {
 		LoggingClient.Error(err.Error())
 		LoggingClient.Warn("Failed to parse event")
 		return nil
@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package distro
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/encoding"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestParseMessage(t *testing.T) {
+	event := models.Event{Device: "Camera", Readings: []models.Reading{{Name: "Image", BinaryValue: []byte{0xff, 0xd8, 0xff}}}}
+	cbor, err := encoding.Marshal(event, clients.ContentCbor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json, err := encoding.Marshal(event, clients.ContentJson)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		msg    [][]byte
+		events int
+	}{
+		{"cbor", [][]byte{[]byte(clients.ContentCbor), cbor}, 1},
+		{"json", [][]byte{[]byte(clients.ContentJson), json}, 1},
+		{"without content type", [][]byte{json, json}, 2},
+		{"invalid", [][]byte{[]byte(clients.ContentCbor), json}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := parseMessage(tt.msg)
+			if len(events) != tt.events {
+				t.Fatalf("expected %d events, got %d", tt.events, len(events))
+			}
+			for _, e := range events {
+				if e.Device != event.Device || len(e.Readings) != 1 || string(e.Readings[0].BinaryValue) != string(event.Readings[0].BinaryValue) {
+					t.Errorf("unexpected event %v", e)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseEvent(b *testing.B) {
+	event := models.Event{Device: "Accelerometer"}
+	event.Readings = append(event.Readings, models.Reading{Name: "Spectrum", BinaryValue: make([]byte, 64*1024)})
+
+	for name, contentType := range map[string]string{"json": clients.ContentJson, "cbor": clients.ContentCbor} {
+		data, err := encoding.Marshal(event, contentType)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if parseEvent(data, contentType) == nil {
+					b.Fatal("Error parsing event")
+				}
+			}
+			b.SetBytes(int64(len(data)))
+		})
+	}
+}
@@ -0,0 +1,361 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package expression parses the filter expressions of export registrations and evaluates them
+// against events.
+//
+// An expression compares event fields and reading values, e.g.
+//
+//	temperature > 80 && device =~ "line1-.*"
+//
+// The operands of a comparison are identifiers, numbers, double quoted strings, true and false.
+// The identifiers device, origin and created are the fields of the event, tags.<key> the value of
+// a tag and readings.<name> the value of the readings with the name. Any other identifier is the
+// name of a reading. Identifiers start with a letter or an underscore, followed by letters, digits,
+// underscores, dots and dashes, so readings.my-sensor is the reading my-sensor; a negative number
+// compared to an identifier is separated from it by an operator or a space. Comparisons are numeric when both operands are numbers and compare strings
+// otherwise, =~ and !~ match a regular expression. A comparison holds when it holds for any
+// reading with the name, and never holds when the event has no such reading or tag. Comparisons
+// are combined with &&, || and !, and grouped with parentheses. A bare operand holds when its
+// value is true.
+package expression
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Expression is a parsed filter expression.
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse parses the expression.
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %s at %d", t.text, t.pos)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// Match tells whether the event satisfies the expression.
+func (e *Expression) Match(event *models.Event) bool {
+	return e.root.eval(event)
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+type node interface {
+	eval(event *models.Event) bool
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) eval(event *models.Event) bool {
+	return n.left.eval(event) && n.right.eval(event)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) eval(event *models.Event) bool {
+	return n.left.eval(event) || n.right.eval(event)
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) eval(event *models.Event) bool {
+	return !n.operand.eval(event)
+}
+
+// An operand holding true
+type truthNode struct {
+	operand operand
+}
+
+func (n truthNode) eval(event *models.Event) bool {
+	for _, v := range n.operand.values(event) {
+		if b, err := strconv.ParseBool(v); err == nil && b {
+			return true
+		}
+	}
+	return false
+}
+
+type comparisonNode struct {
+	op          string
+	left, right operand
+	re          *regexp.Regexp // Set for =~ and !~
+}
+
+func (n comparisonNode) eval(event *models.Event) bool {
+	if n.re != nil {
+		for _, l := range n.left.values(event) {
+			if n.re.MatchString(l) == (n.op == "=~") {
+				return true
+			}
+		}
+		return false
+	}
+
+	rights := n.right.values(event)
+	for _, l := range n.left.values(event) {
+		for _, r := range rights {
+			if compare(n.op, l, r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Compare two values, as numbers when they both are
+func compare(op string, l string, r string) bool {
+	ln, lerr := strconv.ParseFloat(l, 64)
+	rn, rerr := strconv.ParseFloat(r, 64)
+	if lerr == nil && rerr == nil {
+		switch op {
+		case "==":
+			return ln == rn
+		case "!=":
+			return ln != rn
+		case "<":
+			return ln < rn
+		case "<=":
+			return ln <= rn
+		case ">":
+			return ln > rn
+		case ">=":
+			return ln >= rn
+		}
+		return false
+	}
+
+	c := strings.Compare(l, r)
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// The values of an operand for an event, none when the event does not hold it
+type operand interface {
+	values(event *models.Event) []string
+}
+
+type literal string
+
+func (l literal) values(event *models.Event) []string {
+	return []string{string(l)}
+}
+
+type field string
+
+func (f field) values(event *models.Event) []string {
+	switch f {
+	case "device":
+		return []string{event.Device}
+	case "origin":
+		return []string{strconv.FormatInt(event.Origin, 10)}
+	case "created":
+		return []string{strconv.FormatInt(event.Created, 10)}
+	}
+	return nil
+}
+
+type tag string
+
+func (t tag) values(event *models.Event) []string {
+	if v, ok := event.Tags[string(t)]; ok {
+		return []string{v}
+	}
+	return nil
+}
+
+type reading string
+
+func (r reading) values(event *models.Event) []string {
+	var values []string
+	for _, rd := range event.Readings {
+		if rd.Name == string(r) {
+			values = append(values, rd.Value)
+		}
+	}
+	return values
+}
+
+// Operand of an identifier
+func identifier(name string) operand {
+	switch {
+	case name == "device", name == "origin", name == "created":
+		return field(name)
+	case strings.HasPrefix(name, "tags."):
+		return tag(strings.TrimPrefix(name, "tags."))
+	case strings.HasPrefix(name, "readings."):
+		return reading(strings.TrimPrefix(name, "readings."))
+	}
+	return reading(name)
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) consume() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEnd {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().text == "||" && p.peek().kind == tokenOperator {
+		p.consume()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().text == "&&" && p.peek().kind == tokenOperator {
+		p.consume()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	if t.kind == tokenOperator && t.text == "!" {
+		p.consume()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	if t.kind == tokenOperator && t.text == "(" {
+		p.consume()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.consume(); t.text != ")" || t.kind != tokenOperator {
+			return nil, fmt.Errorf("expected ) at %d", t.pos)
+		}
+		return n, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind != tokenOperator || !isComparison(t.text) {
+		return truthNode{operand: left}, nil
+	}
+	p.consume()
+
+	if t.text == "=~" || t.text == "!~" {
+		pattern := p.consume()
+		if pattern.kind != tokenString {
+			return nil, fmt.Errorf("expected a regular expression string at %d", pattern.pos)
+		}
+		re, err := regexp.Compile(pattern.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression at %d: %s", pattern.pos, err.Error())
+		}
+		return comparisonNode{op: t.text, left: left, re: re}, nil
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return comparisonNode{op: t.text, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.consume()
+	switch t.kind {
+	case tokenIdentifier:
+		if t.text == "true" || t.text == "false" {
+			return literal(t.text), nil
+		}
+		return identifier(t.text), nil
+	case tokenNumber, tokenString:
+		return literal(t.text), nil
+	case tokenEnd:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %s at %d", t.text, t.pos)
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=", "=~", "!~":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var testEvent = &models.Event{
+	Device: "line1-press",
+	Origin: 1540000000000,
+	Tags:   map[string]string{"site": "A", "zone-id": "z1"},
+	Readings: []models.Reading{
+		{Name: "temperature", Value: "85.5"},
+		{Name: "temperature", Value: "70"},
+		{Name: "state", Value: "running"},
+		{Name: "alarm", Value: "true"},
+		{Name: "device", Value: "sensor"},
+		{Name: "my-sensor", Value: "12"},
+	},
+}
+
+func TestMatch(t *testing.T) {
+	var tests = []struct {
+		expression string
+		match      bool
+	}{
+		{`temperature > 80`, true},
+		{`temperature > 90`, false},
+		{`temperature < 75`, true},
+		{`temperature > 80 && device =~ "line1-.*"`, true},
+		{`temperature > 80 && device =~ "line2-.*"`, false},
+		{`temperature > 90 || device !~ "^line2"`, true},
+		{`state == "running"`, true},
+		{`state != "running"`, false},
+		{`!(state == "stopped")`, true},
+		{`pressure > 0`, false},
+		{`!(pressure > 0)`, true},
+		{`alarm`, true},
+		{`alarm == true`, true},
+		{`state`, false},
+		{`tags.site == "A"`, true},
+		{`tags.zone == "A"`, false},
+		{`readings.device == "sensor"`, true},
+		{`device == "sensor"`, false},
+		{`origin >= 1540000000000 && origin < 1.6e12`, true},
+		{`temperature > -10.5`, true},
+		{`(temperature > 90 || alarm) && !(state == "stopped")`, true},
+		{`state == "run\"ning"`, false},
+		{`my-sensor > 10`, true},
+		{`readings.my-sensor == 12`, true},
+		{`tags.zone-id == "z1"`, true},
+		{`my-sensor>-5`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			e, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if match := e.Match(testEvent); match != tt.match {
+				t.Errorf("Match should return %v instead of %v", tt.match, match)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expression := range []string{
+		``,
+		`temperature >`,
+		`temperature > 80 &&`,
+		`(temperature > 80`,
+		`temperature > 80)`,
+		`temperature = 80`,
+		`device =~ line1`,
+		`device =~ "("`,
+		`state == "running`,
+		`temperature > 1e`,
+		`temperature > 80 state`,
+	} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("Parsing %q should fail", expression)
+		}
+	}
+}
@@ -12,6 +12,8 @@ package export
 import (
 	"fmt"
 
+	"github.com/edgexfoundry/edgex-go/internal/export/expression"
+	"github.com/edgexfoundry/edgex-go/internal/export/templates"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 
 	"github.com/globalsign/mgo/bson"
@@ -47,6 +49,8 @@ const (
 	FormatCSV             = "CSV"
 	FormatThingsBoardJSON = "THINGSBOARD_JSON"
 	FormatNOOP            = "NOOP"
+	FormatTemplate        = "TEMPLATE"
+	FormatInfluxDBLine    = "INFLUXDB_LINE"
 )
 
 const (
@@ -65,7 +69,10 @@ type Registration struct {
 	Name        string             `json:"name,omitempty"`
 	Addressable models.Addressable `json:"addressable,omitempty"`
 	Format      string             `json:"format,omitempty"`
+	Template    string             `json:"template,omitempty"`
 	Filter      Filter             `json:"filter,omitempty"`
+	CSV         CSVOptions         `json:"csv,omitempty"`
+	Batch       BatchOptions       `json:"batch,omitempty"`
 	Encryption  EncryptionDetails  `json:"encryption,omitempty"`
 	Compression string             `json:"compression,omitempty"`
 	Enable      bool               `json:"enable"`
@@ -76,6 +83,22 @@ type Registration struct {
 type Filter struct {
 	DeviceIDs          []string `bson:"deviceIdentifiers,omitempty" json:"deviceIdentifiers,omitempty"`
 	ValueDescriptorIDs []string `bson:"valueDescriptorIdentifiers,omitempty" json:"valueDescriptorIdentifiers,omitempty"`
+	// Tags holds the tags, and their values, an event must all hold to be exported
+	Tags map[string]string `bson:"tags,omitempty" json:"tags,omitempty"`
+	// Expression an event must satisfy to be exported, such as temperature > 80 && device =~ "line1-.*"
+	Expression string `bson:"expression,omitempty" json:"expression,omitempty"`
+	// Deadband only exports the readings whose value changed since the last one exported
+	Deadband *Deadband `bson:"deadband,omitempty" json:"deadband,omitempty"`
+}
+
+// Deadband - Specifies how much the value of a reading must move from the
+// value last exported, for the same device and reading name, to be exported
+type Deadband struct {
+	// Threshold is the change a numeric value must exceed, 0 exports every change.
+	// Other values are exported whenever they change.
+	Threshold float64 `bson:"threshold,omitempty" json:"threshold,omitempty"`
+	// ReadingNames are the readings the deadband applies to, all of them when empty
+	ReadingNames []string `bson:"readingNames,omitempty" json:"readingNames,omitempty"`
 }
 
 func (reg Registration) Validate() (bool, error) {
@@ -102,10 +125,27 @@ func (reg Registration) Validate() (bool, error) {
 		reg.Format != FormatAWSJSON &&
 		reg.Format != FormatCSV &&
 		reg.Format != FormatThingsBoardJSON &&
-		reg.Format != FormatNOOP {
+		reg.Format != FormatNOOP &&
+		reg.Format != FormatTemplate &&
+		reg.Format != FormatInfluxDBLine {
 		return false, fmt.Errorf("Format invalid: %s", reg.Format)
 	}
 
+	if reg.Format == FormatTemplate {
+		if reg.Template == "" {
+			return false, fmt.Errorf("Template is required")
+		}
+		if _, err := templates.Parse(reg.Template); err != nil {
+			return false, fmt.Errorf("Template invalid: %s", err.Error())
+		}
+	}
+
+	if reg.Format == FormatCSV {
+		if err := reg.CSV.Validate(); err != nil {
+			return false, err
+		}
+	}
+
 	if reg.Destination != DestMQTT &&
 		reg.Destination != DestZMQ &&
 		reg.Destination != DestIotCoreMQTT &&
@@ -116,6 +156,20 @@ func (reg Registration) Validate() (bool, error) {
 		return false, fmt.Errorf("Destination invalid: %s", reg.Destination)
 	}
 
+	if reg.Filter.Expression != "" {
+		if _, err := expression.Parse(reg.Filter.Expression); err != nil {
+			return false, fmt.Errorf("Filter expression invalid: %s", err.Error())
+		}
+	}
+
+	if reg.Filter.Deadband != nil && reg.Filter.Deadband.Threshold < 0 {
+		return false, fmt.Errorf("Deadband threshold invalid: %g", reg.Filter.Deadband.Threshold)
+	}
+
+	if err := reg.Batch.Validate(reg.Format, reg.Destination, reg.Compression, reg.Encryption.Algo); err != nil {
+		return false, err
+	}
+
 	if reg.Encryption.Algo == "" {
 		reg.Encryption.Algo = EncNone
 	}
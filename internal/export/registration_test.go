@@ -29,6 +29,9 @@ func TestRegistrationValid(t *testing.T) {
 		{"wrongFormat", "reg", CompZip, "INVALID", DestMQTT, EncAes, false},
 		{"wrongDestination", "reg", CompZip, FormatJSON, "INVALID", EncAes, false},
 		{"wrongEncryption", "reg", CompZip, FormatJSON, DestMQTT, "INVALID", false},
+		{"lineProtocol", "reg", CompNone, FormatInfluxDBLine, DestInfluxDB, EncNone, true},
+		{"lineProtocolCompressed", "reg", CompGzip, FormatInfluxDBLine, DestInfluxDB, "", false},
+		{"lineProtocolEncrypted", "reg", "", FormatInfluxDBLine, DestInfluxDB, EncAes, false},
 	}
 
 	for _, tt := range tests {
@@ -60,3 +63,126 @@ func TestRegistrationValid(t *testing.T) {
 		})
 	}
 }
+
+func TestCSVOptionsValid(t *testing.T) {
+	var tests = []struct {
+		name  string
+		opts  CSVOptions
+		valid bool
+	}{
+		{"default", CSVOptions{}, true},
+		{"perReading", CSVOptions{Layout: CSVRowPerReading}, true},
+		{"perEvent", CSVOptions{Layout: CSVRowPerEvent, Header: true}, true},
+		{"semicolon", CSVOptions{Delimiter: ";"}, true},
+		{"tab", CSVOptions{Delimiter: "\t", TimestampFormat: CSVTimeRFC3339}, true},
+		{"wrongLayout", CSVOptions{Layout: "INVALID"}, false},
+		{"longDelimiter", CSVOptions{Delimiter: ";;"}, false},
+		{"quoteDelimiter", CSVOptions{Delimiter: "\""}, false},
+		{"newlineDelimiter", CSVOptions{Delimiter: "\n"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Registration{Name: "reg", Format: FormatCSV, Destination: DestRest, CSV: tt.opts}
+			if valid, err := r.Validate(); valid != tt.valid {
+				t.Errorf("Validate should return %v instead of %v. Options %v, err: %v",
+					tt.valid, valid, tt.opts, err)
+			}
+		})
+	}
+
+	opts := BatchOptions{MaxEvents: 100, Window: 1000}
+	if err := opts.Validate(FormatInfluxDBLine, DestInfluxDB, CompGzip, EncNone); err == nil {
+		t.Error("Compressed line protocol batches should be rejected")
+	}
+	if err := opts.Validate(FormatInfluxDBLine, DestInfluxDB, CompNone, EncAes); err == nil {
+		t.Error("Encrypted line protocol batches should be rejected")
+	}
+}
+
+func TestBatchOptionsValid(t *testing.T) {
+	var tests = []struct {
+		name        string
+		opts        BatchOptions
+		format      string
+		destination string
+		valid       bool
+	}{
+		{"disabled", BatchOptions{}, FormatXML, DestMQTT, true},
+		{"json", BatchOptions{MaxEvents: 100, Window: 1000}, FormatJSON, DestRest, true},
+		{"csv", BatchOptions{MaxBytes: 65536, Window: 1000}, FormatCSV, DestRest, true},
+		{"influxdb", BatchOptions{MaxEvents: 100, Window: 1000}, FormatNOOP, DestInfluxDB, true},
+		{"lineProtocol", BatchOptions{MaxEvents: 100, Window: 1000}, FormatInfluxDBLine, DestInfluxDB, true},
+		{"windowOnly", BatchOptions{Window: 1000}, FormatJSON, DestRest, true},
+		{"noWindow", BatchOptions{MaxEvents: 100}, FormatJSON, DestRest, false},
+		{"negative", BatchOptions{MaxEvents: -1}, FormatJSON, DestRest, false},
+		{"wrongFormat", BatchOptions{MaxEvents: 100, Window: 1000}, FormatXML, DestRest, false},
+		{"wrongDestination", BatchOptions{MaxEvents: 100, Window: 1000}, FormatJSON, DestMQTT, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Registration{Name: "reg", Format: tt.format, Destination: tt.destination, Batch: tt.opts}
+			if valid, err := r.Validate(); valid != tt.valid {
+				t.Errorf("Validate should return %v instead of %v. Options %v, err: %v",
+					tt.valid, valid, tt.opts, err)
+			}
+		})
+	}
+
+	opts := BatchOptions{MaxEvents: 100, Window: 1000}
+	if err := opts.Validate(FormatInfluxDBLine, DestInfluxDB, CompGzip, EncNone); err == nil {
+		t.Error("Compressed line protocol batches should be rejected")
+	}
+	if err := opts.Validate(FormatInfluxDBLine, DestInfluxDB, CompNone, EncAes); err == nil {
+		t.Error("Encrypted line protocol batches should be rejected")
+	}
+}
+
+func TestFilterValid(t *testing.T) {
+	var tests = []struct {
+		name   string
+		filter Filter
+		valid  bool
+	}{
+		{"empty", Filter{}, true},
+		{"expression", Filter{Expression: `temperature > 80 && device =~ "line1-.*"`}, true},
+		{"wrongExpression", Filter{Expression: `temperature >`}, false},
+		{"deadband", Filter{Deadband: &Deadband{Threshold: 0.5}}, true},
+		{"changeOnly", Filter{Deadband: &Deadband{}}, true},
+		{"wrongDeadband", Filter{Deadband: &Deadband{Threshold: -1}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Registration{Name: "reg", Format: FormatJSON, Destination: DestRest, Filter: tt.filter}
+			if valid, err := r.Validate(); valid != tt.valid {
+				t.Errorf("Validate should return %v instead of %v. Filter %v, err: %v",
+					tt.valid, valid, tt.filter, err)
+			}
+		})
+	}
+}
+
+func TestTemplateValid(t *testing.T) {
+	var tests = []struct {
+		name     string
+		template string
+		valid    bool
+	}{
+		{"valid", `{"device":{{json .Device}},"time":{{json (timestamp .Origin "RFC3339")}}}`, true},
+		{"missing", ``, false},
+		{"syntax", `{{.Device`, false},
+		{"unknownFunction", `{{unknown .Device}}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Registration{Name: "reg", Format: FormatTemplate, Destination: DestRest, Template: tt.template}
+			if valid, err := r.Validate(); valid != tt.valid {
+				t.Errorf("Validate should return %v instead of %v. Template %s, err: %v",
+					tt.valid, valid, tt.template, err)
+			}
+		})
+	}
+}
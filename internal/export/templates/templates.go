@@ -0,0 +1,72 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package templates parses the Go text/template export registrations render their payloads with.
+//
+// A template is executed with the event as data, and may call these functions besides the
+// text/template builtins:
+//
+//	timestamp MILLIS LAYOUT  format a time in milliseconds since the epoch, in UTC, with a Go time
+//	                         layout or "RFC3339"
+//	now                      the current time in milliseconds since the epoch
+//	number STRING            parse a number, failing the execution when it is not one
+//	isNumber STRING          tell whether the string is a number
+//	json VALUE               encode the value in JSON, strings being quoted and escaped
+//	lower STRING, upper STRING
+package templates
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const rfc3339 = "RFC3339"
+
+var funcs = template.FuncMap{
+	"timestamp": timestamp,
+	"now":       now,
+	"number":    number,
+	"isNumber":  isNumber,
+	"json":      toJSON,
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+}
+
+// Parse parses the template along with its functions.
+func Parse(text string) (*template.Template, error) {
+	return template.New("payload").Funcs(funcs).Option("missingkey=zero").Parse(text)
+}
+
+func timestamp(millis int64, layout string) string {
+	if layout == rfc3339 {
+		layout = time.RFC3339Nano
+	}
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC().Format(layout)
+}
+
+func now() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+func number(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(s), 64)
+}
+
+func isNumber(s string) bool {
+	_, err := number(s)
+	return err == nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
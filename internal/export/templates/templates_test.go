@@ -0,0 +1,75 @@
+//
+// Copyright (c) 2018 Dell Technologies, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package templates
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestExecute(t *testing.T) {
+	event := &models.Event{
+		Device: "line1 \"press\"",
+		Origin: 1540000000123,
+		Tags:   map[string]string{"site": "A"},
+		Readings: []models.Reading{
+			{Name: "temperature", Value: "85.5"},
+			{Name: "state", Value: "Running"},
+		},
+	}
+
+	var tests = []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"fields", `{{.Device}}@{{.Origin}}`, `line1 "press"@1540000000123`},
+		{"json", `{"device":{{json .Device}}}`, `{"device":"line1 \"press\""}`},
+		{"rfc3339", `{{timestamp .Origin "RFC3339"}}`, `2018-10-20T01:46:40.123Z`},
+		{"layout", `{{timestamp .Origin "2006-01-02"}}`, `2018-10-20`},
+		{"numbers", `{{range .Readings}}{{if isNumber .Value}}{{.Name}}={{number .Value}};{{end}}{{end}}`, `temperature=85.5;`},
+		{"lower", `{{range .Readings}}{{if not (isNumber .Value)}}{{lower .Value}}{{end}}{{end}}`, `running`},
+		{"tags", `{{.Tags.site}}{{.Tags.zone}}`, `A`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, event); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Template should render %s instead of %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{`{{.Device`, `{{unknown .Device}}`, `{{end}}`} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Parsing %q should fail", text)
+		}
+	}
+}
+
+func TestNumberError(t *testing.T) {
+	tmpl, err := Parse(`{{number .Device}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, &models.Event{Device: "press"}); err == nil {
+		t.Error("Executing should fail on a value that is not a number")
+	}
+}
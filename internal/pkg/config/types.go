@@ -23,6 +23,8 @@ import (
 // For now using const literals for values
 const (
 	Consul = "consul"
+	ZeroMQ = "zero"
+	MQTT   = "mqtt"
 )
 
 // ServiceInfo contains configuration settings necessary for the basic operation of any EdgeX service.
@@ -89,6 +91,17 @@ type MessageQueueInfo struct {
 	Protocol string
 	// Indicates the message queue platform being used.
 	Type string
+	// Topic events are published to and received from, if the platform uses topics.
+	Topic string
+	// TopicPerDevice publishes the events of each device to a sub-topic of Topic named after the device.
+	TopicPerDevice bool
+	// Qos is the MQTT quality of service used to publish and receive events.
+	Qos int
+	// ClientId identifies the service to the broker, if applicable.
+	ClientId string
+	// Username and Password authenticate the service to the broker, if applicable.
+	Username string
+	Password string
 }
 
 func (m MessageQueueInfo) Uri() string {
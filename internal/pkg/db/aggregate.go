@@ -0,0 +1,128 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package db
+
+import (
+	"sort"
+	"strconv"
+
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Summarizes the numeric readings of a value descriptor created between start (inclusive) and
+// end (exclusive) into fixed buckets of interval milliseconds, one series per device.
+// Readings must be added in creation order. Buckets without numeric readings are omitted.
+type ReadingAggregator struct {
+	name       string
+	start      int64
+	end        int64
+	interval   int64
+	index      map[aggregateKey]int
+	aggregates []contract.ReadingAggregate
+}
+
+type aggregateKey struct {
+	device string
+	bucket int64
+}
+
+func NewReadingAggregator(name string, start, end, interval int64) *ReadingAggregator {
+	return &ReadingAggregator{
+		name:       name,
+		start:      start,
+		end:        end,
+		interval:   interval,
+		index:      make(map[aggregateKey]int),
+		aggregates: []contract.ReadingAggregate{},
+	}
+}
+
+// Add the reading to the bucket of its device and creation time
+// Readings outside of the range and non numeric readings are ignored
+func (ra *ReadingAggregator) Add(r contract.Reading) {
+	if r.Created < ra.start || r.Created >= ra.end {
+		return
+	}
+	value, err := strconv.ParseFloat(r.Value, 64)
+	if err != nil {
+		// Non numeric readings cannot be aggregated
+		return
+	}
+
+	k := aggregateKey{device: r.Device, bucket: (r.Created - ra.start) / ra.interval}
+	i, ok := ra.index[k]
+	if !ok {
+		bucketStart := ra.start + k.bucket*ra.interval
+		bucketEnd := bucketStart + ra.interval
+		if bucketEnd > ra.end {
+			bucketEnd = ra.end
+		}
+		ra.aggregates = append(ra.aggregates, contract.ReadingAggregate{
+			Name:   ra.name,
+			Device: r.Device,
+			Start:  bucketStart,
+			End:    bucketEnd,
+			Min:    value,
+			Max:    value,
+			First:  value,
+		})
+		i = len(ra.aggregates) - 1
+		ra.index[k] = i
+	}
+
+	a := &ra.aggregates[i]
+	if s := r.Summary; s != nil {
+		// A summary of downsampled readings counts for every reading it replaced
+		if !ok || s.Min < a.Min {
+			a.Min = s.Min
+		}
+		if !ok || s.Max > a.Max {
+			a.Max = s.Max
+		}
+		if !ok {
+			a.First = s.First
+		}
+		a.Avg += s.Avg * float64(s.Count)
+		a.Last = s.Last
+		a.Count += s.Count
+		return
+	}
+	if value < a.Min {
+		a.Min = value
+	}
+	if value > a.Max {
+		a.Max = value
+	}
+	// Avg holds the running sum until Aggregates is called
+	a.Avg += value
+	a.Last = value
+	a.Count++
+}
+
+// Return the buckets ordered by device and then by start time
+func (ra *ReadingAggregator) Aggregates() []contract.ReadingAggregate {
+	aggregates := make([]contract.ReadingAggregate, len(ra.aggregates))
+	copy(aggregates, ra.aggregates)
+	for i := range aggregates {
+		aggregates[i].Avg /= float64(aggregates[i].Count)
+	}
+	sort.SliceStable(aggregates, func(i, j int) bool {
+		if aggregates[i].Device != aggregates[j].Device {
+			return aggregates[i].Device < aggregates[j].Device
+		}
+		return aggregates[i].Start < aggregates[j].Start
+	})
+	return aggregates
+}
@@ -0,0 +1,75 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestReadingAggregator(t *testing.T) {
+	readings := []contract.Reading{
+		{Device: "d1", Name: "Temperature", Value: "10", Created: 1000},
+		{Device: "d1", Name: "Temperature", Value: "not a number", Created: 1010},
+		{Device: "d2", Name: "Temperature", Value: "5", Created: 1020},
+		{Device: "d1", Name: "Temperature", Value: "20", Created: 1050},
+		{Device: "d1", Name: "Temperature", Value: "30", Created: 1099},
+		{Device: "d1", Name: "Temperature", Value: "40", Created: 1150},
+		{Device: "d1", Name: "Temperature", Value: "50", Created: 1200},
+	}
+
+	aggregator := NewReadingAggregator("Temperature", 1000, 1200, 100)
+	for _, r := range readings {
+		aggregator.Add(r)
+	}
+
+	expected := []contract.ReadingAggregate{
+		{Name: "Temperature", Device: "d1", Start: 1000, End: 1100, Count: 3, Min: 10, Max: 30, Avg: 20, First: 10, Last: 30},
+		{Name: "Temperature", Device: "d1", Start: 1100, End: 1200, Count: 1, Min: 40, Max: 40, Avg: 40, First: 40, Last: 40},
+		{Name: "Temperature", Device: "d2", Start: 1000, End: 1100, Count: 1, Min: 5, Max: 5, Avg: 5, First: 5, Last: 5},
+	}
+	if aggregates := aggregator.Aggregates(); !reflect.DeepEqual(aggregates, expected) {
+		t.Errorf("Unexpected reading aggregates %v", aggregates)
+	}
+}
+
+func TestReadingAggregatorSummaries(t *testing.T) {
+	readings := []contract.Reading{
+		{Device: "d1", Name: "Temperature", Value: "20", Created: 1000,
+			Summary: &contract.ReadingAggregate{Count: 3, Min: 5, Max: 35, Avg: 20, First: 5, Last: 35}},
+		{Device: "d1", Name: "Temperature", Value: "40", Created: 1050},
+	}
+
+	aggregator := NewReadingAggregator("Temperature", 1000, 1100, 100)
+	for _, r := range readings {
+		aggregator.Add(r)
+	}
+
+	expected := []contract.ReadingAggregate{
+		{Name: "Temperature", Device: "d1", Start: 1000, End: 1100, Count: 4, Min: 5, Max: 40, Avg: 25, First: 5, Last: 40},
+	}
+	if aggregates := aggregator.Aggregates(); !reflect.DeepEqual(aggregates, expected) {
+		t.Errorf("Unexpected reading aggregates %v", aggregates)
+	}
+}
+
+func TestReadingAggregatorEmpty(t *testing.T) {
+	aggregates := NewReadingAggregator("Temperature", 1000, 1100, 100).Aggregates()
+	if aggregates == nil || len(aggregates) != 0 {
+		t.Errorf("Expected an empty list of aggregates instead of %v", aggregates)
+	}
+}
@@ -0,0 +1,209 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// Buckets holding the records, keyed by ID
+var (
+	eventBucket           = []byte(db.EventsCollection)
+	readingBucket         = []byte(db.ReadingsCollection)
+	valueDescriptorBucket = []byte(db.ValueDescriptorCollection)
+)
+
+// Index buckets. The creation indexes are keyed by the creation time followed by the ID so that a
+// cursor walks the records in creation order; the name index maps value descriptor names to IDs.
+var (
+	eventCreatedIndex        = []byte(db.EventsCollection + "-created")
+	readingCreatedIndex      = []byte(db.ReadingsCollection + "-created")
+	valueDescriptorNameIndex = []byte(db.ValueDescriptorCollection + "-name")
+)
+
+type BoltClient struct {
+	db *bbolt.DB // Bolt database file
+}
+
+// Return a pointer to the BoltClient
+// The database name in the configuration is the path of the database file, which is created if
+// it does not exist yet. Host, port and credentials are not used by the embedded database.
+func NewClient(config db.Configuration) (*BoltClient, error) {
+	options := &bbolt.Options{Timeout: time.Duration(config.Timeout) * time.Millisecond}
+	b, err := bbolt.Open(config.DatabaseName, os.FileMode(0600), options)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.Update(func(tx *bbolt.Tx) error {
+		buckets := [][]byte{
+			eventBucket,
+			readingBucket,
+			valueDescriptorBucket,
+			eventCreatedIndex,
+			readingCreatedIndex,
+			valueDescriptorNameIndex,
+		}
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Close()
+		return nil, err
+	}
+
+	return &BoltClient{db: b}, nil
+}
+
+func (bc *BoltClient) CloseSession() {
+	if bc.db != nil {
+		bc.db.Close()
+		bc.db = nil
+	}
+}
+
+// Return the ID for a record. An empty ID means the record is being added, so a new UUID is generated.
+func toId(id string) (string, error) {
+	if id == "" {
+		return uuid.New().String(), nil
+	}
+	return checkId(id)
+}
+
+// Check that the ID can identify a record in the database
+func checkId(id string) (string, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return "", db.ErrInvalidObjectId
+	}
+	return id, nil
+}
+
+// Key of a record in a creation index
+func createdKey(created int64, id string) []byte {
+	key := make([]byte, 8, 8+len(id))
+	// Flip the sign bit so that negative timestamps still sort before positive ones
+	binary.BigEndian.PutUint64(key, uint64(created)^(1<<63))
+	return append(key, id...)
+}
+
+// Store a record and its creation index entry
+func putRecord(tx *bbolt.Tx, bucket []byte, index []byte, id string, created int64, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err = tx.Bucket(bucket).Put([]byte(id), data); err != nil {
+		return err
+	}
+	if index != nil {
+		return tx.Bucket(index).Put(createdKey(created, id), []byte(id))
+	}
+	return nil
+}
+
+// Load a record by ID
+func getRecord(tx *bbolt.Tx, bucket []byte, id string, v interface{}) error {
+	data := tx.Bucket(bucket).Get([]byte(id))
+	if data == nil {
+		return db.ErrNotFound
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Remove a record and its creation index entry
+func deleteRecord(tx *bbolt.Tx, bucket []byte, index []byte, id string, created int64) error {
+	if err := tx.Bucket(bucket).Delete([]byte(id)); err != nil {
+		return err
+	}
+	if index != nil {
+		return tx.Bucket(index).Delete(createdKey(created, id))
+	}
+	return nil
+}
+
+// Walk the records of a bucket in creation order, starting at the given creation time.
+// The walk stops at the end of the bucket or when fn returns false.
+func walkCreated(tx *bbolt.Tx, bucket []byte, index []byte, from int64, fn func(data []byte) (bool, error)) error {
+	return walkCreatedAfter(tx, bucket, index, createdKey(from, ""), fn)
+}
+
+// Walk the records of a bucket in creation order, starting after the given creation index key.
+// The walk stops at the end of the bucket or when fn returns false.
+func walkCreatedAfter(tx *bbolt.Tx, bucket []byte, index []byte, after []byte, fn func(data []byte) (bool, error)) error {
+	records := tx.Bucket(bucket)
+	c := tx.Bucket(index).Cursor()
+	for k, id := c.Seek(after); k != nil; k, id = c.Next() {
+		if bytes.Equal(k, after) {
+			continue
+		}
+		data := records.Get(id)
+		if data == nil {
+			// Stale index entry
+			continue
+		}
+		more, err := fn(data)
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+	}
+	return nil
+}
+
+// Creation index key to resume a walk after the position of a cursor, or at start when there is none.
+// Positions before start resume at start.
+func pageKey(start int64, cursor string) ([]byte, error) {
+	if cursor == "" {
+		return createdKey(start, ""), nil
+	}
+
+	c, err := db.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := checkId(c.Id); err != nil {
+		return nil, db.ErrInvalidCursor
+	}
+	if c.Created < start {
+		return createdKey(start, ""), nil
+	}
+	return createdKey(c.Created, c.Id), nil
+}
+
+// Remove every record of the buckets
+func clearBuckets(tx *bbolt.Tx, buckets ...[]byte) error {
+	for _, name := range buckets {
+		if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package bolt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db/test"
+)
+
+// The embedded database needs no running server, so unlike the mongo tests these always run
+func newTestClient(t testing.TB) (*BoltClient, func()) {
+	dir, err := ioutil.TempDir("", "edgex-bolt")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+
+	config := db.Configuration{
+		DatabaseName: filepath.Join(dir, "coredata.db"),
+		Timeout:      1000,
+	}
+	bolt, err := NewClient(config)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Could not open database: %v", err)
+	}
+	return bolt, func() { os.RemoveAll(dir) }
+}
+
+func TestBoltDB(t *testing.T) {
+	bolt, cleanup := newTestClient(t)
+	defer cleanup()
+
+	test.TestDataDB(t, bolt)
+}
+
+func BenchmarkBoltDB(b *testing.B) {
+	bolt, cleanup := newTestClient(b)
+	defer cleanup()
+
+	test.BenchmarkDB(b, bolt)
+}
@@ -0,0 +1,1028 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package bolt
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+	"go.etcd.io/bbolt"
+)
+
+/*
+Core data client
+Has functions for interacting with the core data bolt database
+*/
+
+// Stored form of an event. Readings are stored in their own bucket and referenced by ID,
+// the same way the mongo client links an event to its readings.
+type event struct {
+	ID       string            `json:"id"`
+	Pushed   int64             `json:"pushed"`
+	Device   string            `json:"device"`
+	Created  int64             `json:"created"`
+	Modified int64             `json:"modified"`
+	Origin   int64             `json:"origin"`
+	Event    string            `json:"event"`
+	Readings []string          `json:"readings"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+func (e *event) fromContract(from contract.Event) {
+	e.ID = from.ID
+	e.Pushed = from.Pushed
+	e.Device = from.Device
+	e.Created = from.Created
+	e.Modified = from.Modified
+	e.Origin = from.Origin
+	e.Event = from.Event
+	e.Tags = from.Tags
+	e.Readings = []string{}
+	for _, r := range from.Readings {
+		e.Readings = append(e.Readings, r.Id)
+	}
+}
+
+func (e *event) toContract(readings []contract.Reading) contract.Event {
+	return contract.Event{
+		ID:       e.ID,
+		Pushed:   e.Pushed,
+		Device:   e.Device,
+		Created:  e.Created,
+		Modified: e.Modified,
+		Origin:   e.Origin,
+		Event:    e.Event,
+		Readings: readings,
+		Tags:     e.Tags,
+	}
+}
+
+// ******************************* EVENTS **********************************
+
+// Return all the events
+// UnexpectedError - failed to retrieve events from the database
+// Sort the events in ascending order by creation time
+func (bc *BoltClient) Events() ([]contract.Event, error) {
+	return bc.getEvents(math.MinInt64, math.MaxInt64, -1, nil)
+}
+
+// Return events up to the max number specified
+// UnexpectedError - failed to retrieve events from the database
+// Sort the events in ascending order by creation time
+func (bc *BoltClient) EventsWithLimit(limit int) ([]contract.Event, error) {
+	return bc.getEvents(math.MinInt64, math.MaxInt64, limit, nil)
+}
+
+// Add a new event
+// UnexpectedError - failed to add to database
+// NoValueDescriptor - no existing value descriptor for a reading in the event
+func (bc *BoltClient) AddEvent(e contract.Event) (string, error) {
+	var err error
+	e.ID, err = toId(e.ID)
+	if err != nil {
+		return e.ID, err
+	}
+	if e.Created == 0 {
+		e.Created = db.MakeTimestamp()
+	}
+
+	err = bc.db.Update(func(tx *bbolt.Tx) error {
+		//Add the readings
+		for i := range e.Readings {
+			r := &e.Readings[i]
+			r.Id, err = toId(r.Id)
+			if err != nil {
+				return err
+			}
+			if r.Created == 0 {
+				r.Created = e.Created
+			}
+			if err = putRecord(tx, readingBucket, readingCreatedIndex, r.Id, r.Created, r); err != nil {
+				return err
+			}
+		}
+
+		// Add the event
+		stored := event{}
+		stored.fromContract(e)
+		return putRecord(tx, eventBucket, eventCreatedIndex, stored.ID, stored.Created, stored)
+	})
+	return e.ID, err
+}
+
+// Update an event - do NOT update readings
+// UnexpectedError - problem updating in database
+// NotFound - no event with the ID was found
+func (bc *BoltClient) UpdateEvent(e contract.Event) error {
+	id, err := checkId(e.ID)
+	if err != nil {
+		return err
+	}
+
+	return bc.db.Update(func(tx *bbolt.Tx) error {
+		existing := event{}
+		if err := getRecord(tx, eventBucket, id, &existing); err != nil {
+			return err
+		}
+
+		updated := event{}
+		updated.fromContract(e)
+		updated.Readings = existing.Readings
+		updated.Modified = db.MakeTimestamp()
+		if updated.Created == 0 {
+			updated.Created = existing.Created
+		}
+
+		if err := deleteRecord(tx, eventBucket, eventCreatedIndex, id, existing.Created); err != nil {
+			return err
+		}
+		return putRecord(tx, eventBucket, eventCreatedIndex, id, updated.Created, updated)
+	})
+}
+
+// Get an event by id
+func (bc *BoltClient) EventById(id string) (contract.Event, error) {
+	id, err := checkId(id)
+	if err != nil {
+		return contract.Event{}, err
+	}
+
+	var result contract.Event
+	err = bc.db.View(func(tx *bbolt.Tx) error {
+		stored := event{}
+		if err := getRecord(tx, eventBucket, id, &stored); err != nil {
+			return err
+		}
+		readings, err := getReadingsForEvent(tx, stored)
+		if err != nil {
+			return err
+		}
+		result = stored.toContract(readings)
+		return nil
+	})
+	if err != nil {
+		return contract.Event{}, err
+	}
+	return result, nil
+}
+
+// Get the number of events in bolt
+func (bc *BoltClient) EventCount() (int, error) {
+	return bc.count(eventBucket)
+}
+
+// Get the number of events in bolt for the device
+func (bc *BoltClient) EventCountByDeviceId(id string) (int, error) {
+	events, err := bc.getEvents(math.MinInt64, math.MaxInt64, -1, func(e *event) bool {
+		return e.Device == id
+	})
+	return len(events), err
+}
+
+// Delete an event by ID. Readings are deleted by the caller, as with the mongo client.
+// 404 - Event not found
+// 503 - Unexpected problems
+func (bc *BoltClient) DeleteEventById(id string) error {
+	id, err := checkId(id)
+	if err != nil {
+		return err
+	}
+
+	return bc.db.Update(func(tx *bbolt.Tx) error {
+		stored := event{}
+		if err := getRecord(tx, eventBucket, id, &stored); err != nil {
+			return err
+		}
+		return deleteRecord(tx, eventBucket, eventCreatedIndex, id, stored.Created)
+	})
+}
+
+// Get a list of events based on the device id and limit
+func (bc *BoltClient) EventsForDeviceLimit(id string, limit int) ([]contract.Event, error) {
+	return bc.getEvents(math.MinInt64, math.MaxInt64, limit, func(e *event) bool {
+		return e.Device == id
+	})
+}
+
+// Get a list of events based on the device id
+func (bc *BoltClient) EventsForDevice(id string) ([]contract.Event, error) {
+	return bc.getEvents(math.MinInt64, math.MaxInt64, -1, func(e *event) bool {
+		return e.Device == id
+	})
+}
+
+// Get a list of events holding the tag with the value, limited by the limit
+func (bc *BoltClient) EventsByTag(key, value string, limit int) ([]contract.Event, error) {
+	return bc.getEvents(math.MinInt64, math.MaxInt64, limit, func(e *event) bool {
+		v, ok := e.Tags[key]
+		return ok && v == value
+	})
+}
+
+// Delete all of the events by the device id (and the readings)
+func (bc *BoltClient) DeleteEventsByDeviceId(id string) (int, error) {
+	return bc.deleteEvents(math.MaxInt64, 0, func(e *event) bool {
+		return e.Device == id
+	})
+}
+
+// Return a list of events whos creation time is between startTime and endTime
+// Limit the number of results by limit
+func (bc *BoltClient) EventsByCreationTime(startTime, endTime int64, limit int) ([]contract.Event, error) {
+	return bc.getEvents(startTime, endTime, limit, nil)
+}
+
+// Return a page of events whos creation time is between startTime and endTime, of the devices if any,
+// starting after the cursor
+func (bc *BoltClient) EventsPage(startTime, endTime int64, devices []string, cursor string, limit int) ([]contract.Event, string, error) {
+	after, err := pageKey(startTime, cursor)
+	if err != nil {
+		return []contract.Event{}, "", err
+	}
+	if limit <= 0 {
+		return []contract.Event{}, "", nil
+	}
+
+	var match func(e *event) bool
+	if len(devices) > 0 {
+		names := make(map[string]bool, len(devices))
+		for _, d := range devices {
+			names[d] = true
+		}
+		match = func(e *event) bool {
+			return names[e.Device]
+		}
+	}
+
+	// Fetch one more than the page to find out if there is a next page
+	events, err := bc.getEventsAfter(after, endTime, limit+1, match)
+	if err != nil {
+		return []contract.Event{}, "", err
+	}
+
+	next := ""
+	if len(events) > limit {
+		events = events[:limit]
+		next = db.Cursor{Created: events[limit-1].Created, Id: events[limit-1].ID}.Encode()
+	}
+	return events, next, nil
+}
+
+// Get Events that are older than the given age (defined by age = now - created)
+func (bc *BoltClient) EventsOlderThanAge(age int64) ([]contract.Event, error) {
+	expireDate := db.MakeTimestamp() - age
+	return bc.getEvents(math.MinInt64, expireDate-1, -1, nil)
+}
+
+// Remove all the events, and their readings, that are older than the given age
+func (bc *BoltClient) RemoveEventByAge(age int64) (int, error) {
+	return bc.DeleteEventsCreatedBefore("", db.MakeTimestamp()-age)
+}
+
+// Get all of the events that have been pushed
+func (bc *BoltClient) EventsPushed() ([]contract.Event, error) {
+	return bc.getEvents(math.MinInt64, math.MaxInt64, -1, func(e *event) bool {
+		return e.Pushed > 0
+	})
+}
+
+// Delete the events, and their readings, created before the given time
+func (bc *BoltClient) DeleteEventsCreatedBefore(device string, created int64) (int, error) {
+	return bc.deleteEvents(created-1, 0, func(e *event) bool {
+		return device == "" || e.Device == device
+	})
+}
+
+// Delete the events, and their readings, beyond the given number of most recently created ones
+func (bc *BoltClient) DeleteEventsBeyondCount(device string, count int) (int, error) {
+	return bc.deleteEvents(math.MaxInt64, count, func(e *event) bool {
+		return device == "" || e.Device == device
+	})
+}
+
+// Delete the events, and their readings, that have been pushed
+func (bc *BoltClient) DeletePushedEvents(device string) (int, error) {
+	return bc.deleteEvents(math.MaxInt64, 0, func(e *event) bool {
+		return e.Pushed > 0 && (device == "" || e.Device == device)
+	})
+}
+
+// Remove all the events, and their readings, that have been pushed
+func (bc *BoltClient) ScrubEvents() (int, error) {
+	return bc.DeletePushedEvents("")
+}
+
+// Delete all of the readings and all of the events
+func (bc *BoltClient) ScrubAllEvents() error {
+	return bc.db.Update(func(tx *bbolt.Tx) error {
+		return clearBuckets(tx, readingBucket, readingCreatedIndex, eventBucket, eventCreatedIndex)
+	})
+}
+
+// Get the events created between start and end (inclusive) that match the filter, in creation order.
+// A negative limit returns all of them; a limit of 0 returns none.
+func (bc *BoltClient) getEvents(start, end int64, limit int, match func(e *event) bool) ([]contract.Event, error) {
+	return bc.getEventsAfter(createdKey(start, ""), end, limit, match)
+}
+
+// Get the events created after the creation index key and up to end (inclusive) that match the filter
+func (bc *BoltClient) getEventsAfter(after []byte, end int64, limit int, match func(e *event) bool) ([]contract.Event, error) {
+	events := []contract.Event{}
+	if limit == 0 {
+		return events, nil
+	}
+
+	err := bc.db.View(func(tx *bbolt.Tx) error {
+		return walkCreatedAfter(tx, eventBucket, eventCreatedIndex, after, func(data []byte) (bool, error) {
+			stored := event{}
+			if err := json.Unmarshal(data, &stored); err != nil {
+				return false, err
+			}
+			if stored.Created > end {
+				return false, nil
+			}
+			if match != nil && !match(&stored) {
+				return true, nil
+			}
+
+			readings, err := getReadingsForEvent(tx, stored)
+			if err != nil {
+				// Like the mongo client, missing readings do not fail a list of events
+				if err != db.ErrNotFound {
+					return false, err
+				}
+			}
+			events = append(events, stored.toContract(readings))
+			return limit < 0 || len(events) < limit, nil
+		})
+	})
+	if err != nil {
+		return []contract.Event{}, err
+	}
+	return events, nil
+}
+
+// Delete the events created up to end (inclusive) that match the filter, along with their readings
+// When keep is positive, the keep most recently created matching events are left in place
+func (bc *BoltClient) deleteEvents(end int64, keep int, match func(e *event) bool) (int, error) {
+	var found []event
+	err := bc.db.Update(func(tx *bbolt.Tx) error {
+		err := walkCreated(tx, eventBucket, eventCreatedIndex, math.MinInt64, func(data []byte) (bool, error) {
+			stored := event{}
+			if err := json.Unmarshal(data, &stored); err != nil {
+				return false, err
+			}
+			if stored.Created > end {
+				return false, nil
+			}
+			if match(&stored) {
+				found = append(found, stored)
+			}
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// The walk is in creation order, so the most recent events are at the end
+		if keep > 0 {
+			if keep >= len(found) {
+				found = nil
+			} else {
+				found = found[:len(found)-keep]
+			}
+		}
+		for _, e := range found {
+			for _, id := range e.Readings {
+				var r contract.Reading
+				if err := getRecord(tx, readingBucket, id, &r); err != nil {
+					if err == db.ErrNotFound {
+						continue
+					}
+					return err
+				}
+				if err := deleteRecord(tx, readingBucket, readingCreatedIndex, id, r.Created); err != nil {
+					return err
+				}
+			}
+			if err := deleteRecord(tx, eventBucket, eventCreatedIndex, e.ID, e.Created); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(found), nil
+}
+
+func getReadingsForEvent(tx *bbolt.Tx, e event) ([]contract.Reading, error) {
+	readings := []contract.Reading{}
+	for _, id := range e.Readings {
+		var r contract.Reading
+		err := getRecord(tx, readingBucket, id, &r)
+		if err == db.ErrNotFound {
+			// The reading was replaced by a summary when downsampling
+			continue
+		}
+		if err != nil {
+			return []contract.Reading{}, err
+		}
+		readings = append(readings, r)
+	}
+	return readings, nil
+}
+
+// ************************ READINGS ************************************
+
+// Return a list of readings sorted by creation time
+func (bc *BoltClient) Readings() ([]contract.Reading, error) {
+	return bc.getReadings(math.MinInt64, math.MaxInt64, -1, nil)
+}
+
+// Post a new reading
+func (bc *BoltClient) AddReading(r contract.Reading) (string, error) {
+	var err error
+	r.Id, err = toId(r.Id)
+	if err != nil {
+		return r.Id, err
+	}
+	if r.Created == 0 {
+		r.Created = db.MakeTimestamp()
+	}
+
+	err = bc.db.Update(func(tx *bbolt.Tx) error {
+		return putRecord(tx, readingBucket, readingCreatedIndex, r.Id, r.Created, r)
+	})
+	return r.Id, err
+}
+
+// Update a reading
+// 404 - reading cannot be found
+// 409 - Value descriptor doesn't exist
+// 503 - unknown issues
+func (bc *BoltClient) UpdateReading(r contract.Reading) error {
+	id, err := checkId(r.Id)
+	if err != nil {
+		return err
+	}
+
+	return bc.db.Update(func(tx *bbolt.Tx) error {
+		var existing contract.Reading
+		if err := getRecord(tx, readingBucket, id, &existing); err != nil {
+			return err
+		}
+
+		r.Modified = db.MakeTimestamp()
+		if r.Created == 0 {
+			r.Created = existing.Created
+		}
+
+		if err := deleteRecord(tx, readingBucket, readingCreatedIndex, id, existing.Created); err != nil {
+			return err
+		}
+		return putRecord(tx, readingBucket, readingCreatedIndex, id, r.Created, r)
+	})
+}
+
+// Get a reading by ID
+func (bc *BoltClient) ReadingById(id string) (contract.Reading, error) {
+	id, err := checkId(id)
+	if err != nil {
+		return contract.Reading{}, err
+	}
+
+	var r contract.Reading
+	err = bc.db.View(func(tx *bbolt.Tx) error {
+		return getRecord(tx, readingBucket, id, &r)
+	})
+	if err != nil {
+		return contract.Reading{}, err
+	}
+	return r, nil
+}
+
+// Get the count of readings in bolt
+func (bc *BoltClient) ReadingCount() (int, error) {
+	return bc.count(readingBucket)
+}
+
+// Delete a reading by ID
+// 404 - can't find the reading with the given id
+func (bc *BoltClient) DeleteReadingById(id string) error {
+	id, err := checkId(id)
+	if err != nil {
+		return err
+	}
+
+	return bc.db.Update(func(tx *bbolt.Tx) error {
+		var r contract.Reading
+		if err := getRecord(tx, readingBucket, id, &r); err != nil {
+			return err
+		}
+		return deleteRecord(tx, readingBucket, readingCreatedIndex, id, r.Created)
+	})
+}
+
+// Return a list of readings for the given device (id or name)
+// Sort the list of readings on creation date
+func (bc *BoltClient) ReadingsByDevice(id string, limit int) ([]contract.Reading, error) {
+	return bc.getReadings(math.MinInt64, math.MaxInt64, limit, func(r *contract.Reading) bool {
+		return r.Device == id
+	})
+}
+
+// Return a list of readings for the given value descriptor
+// Limit by the given limit
+func (bc *BoltClient) ReadingsByValueDescriptor(name string, limit int) ([]contract.Reading, error) {
+	return bc.getReadings(math.MinInt64, math.MaxInt64, limit, func(r *contract.Reading) bool {
+		return r.Name == name
+	})
+}
+
+// Return a list of readings whose name is in the list of value descriptor names
+func (bc *BoltClient) ReadingsByValueDescriptorNames(names []string, limit int) ([]contract.Reading, error) {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return bc.getReadings(math.MinInt64, math.MaxInt64, limit, func(r *contract.Reading) bool {
+		return set[r.Name]
+	})
+}
+
+// Return a list of readings whose value descriptor has the UOM label
+func (bc *BoltClient) ReadingsByUomLabel(uomLabel string, limit int) ([]contract.Reading, error) {
+	return bc.readingsByValueDescriptors(limit, func(v *contract.ValueDescriptor) bool {
+		return v.UomLabel == uomLabel
+	})
+}
+
+// Return a list of readings whose value descriptor has the label
+func (bc *BoltClient) ReadingsByLabel(label string, limit int) ([]contract.Reading, error) {
+	return bc.readingsByValueDescriptors(limit, func(v *contract.ValueDescriptor) bool {
+		return hasLabel(v, label)
+	})
+}
+
+// Return a list of readings whose value descriptor has the type
+func (bc *BoltClient) ReadingsByType(typeString string, limit int) ([]contract.Reading, error) {
+	return bc.readingsByValueDescriptors(limit, func(v *contract.ValueDescriptor) bool {
+		return v.Type == typeString
+	})
+}
+
+// Return a list of readings whose value descriptor matches the filter
+func (bc *BoltClient) readingsByValueDescriptors(limit int, match func(v *contract.ValueDescriptor) bool) ([]contract.Reading, error) {
+	descriptors, err := bc.getValueDescriptors(match)
+	if err != nil {
+		return []contract.Reading{}, err
+	}
+	if len(descriptors) == 0 {
+		return []contract.Reading{}, nil
+	}
+	names := make([]string, 0, len(descriptors))
+	for _, v := range descriptors {
+		names = append(names, v.Name)
+	}
+	return bc.ReadingsByValueDescriptorNames(names, limit)
+}
+
+// Return a list of readings whos creation time is in-between start and end
+// Limit by the limit parameter
+func (bc *BoltClient) ReadingsByCreationTime(start, end int64, limit int) ([]contract.Reading, error) {
+	return bc.getReadings(start, end, limit, nil)
+}
+
+// Return a list of readings for the value descriptor whos creation time is in-between start and end
+func (bc *BoltClient) ReadingsByValueDescriptorAndCreationTime(name string, start, end int64, limit int) ([]contract.Reading, error) {
+	return bc.getReadings(start, end, limit, func(r *contract.Reading) bool {
+		return r.Name == name
+	})
+}
+
+// Summarize the numeric readings of the value descriptor created between start (inclusive) and end (exclusive)
+func (bc *BoltClient) ReadingAggregates(name string, device string, start, end, interval int64) ([]contract.ReadingAggregate, error) {
+	aggregator := db.NewReadingAggregator(name, start, end, interval)
+	err := bc.db.View(func(tx *bbolt.Tx) error {
+		return walkCreatedAfter(tx, readingBucket, readingCreatedIndex, createdKey(start, ""), func(data []byte) (bool, error) {
+			var r contract.Reading
+			if err := json.Unmarshal(data, &r); err != nil {
+				return false, err
+			}
+			if r.Created >= end {
+				return false, nil
+			}
+			if r.Name == name && (device == "" || r.Device == device) {
+				aggregator.Add(r)
+			}
+			return true, nil
+		})
+	})
+	if err != nil {
+		return []contract.ReadingAggregate{}, err
+	}
+	return aggregator.Aggregates(), nil
+}
+
+// Delete the readings created before the given time
+func (bc *BoltClient) DeleteReadingsCreatedBefore(name string, created int64) (int, error) {
+	return bc.deleteReadings(created-1, 0, func(r *contract.Reading) bool {
+		return name == "" || r.Name == name
+	})
+}
+
+// Delete the readings beyond the given number of most recently created ones
+func (bc *BoltClient) DeleteReadingsBeyondCount(name string, count int) (int, error) {
+	return bc.deleteReadings(math.MaxInt64, count, func(r *contract.Reading) bool {
+		return name == "" || r.Name == name
+	})
+}
+
+// Delete the readings that have been pushed
+func (bc *BoltClient) DeletePushedReadings(name string) (int, error) {
+	return bc.deleteReadings(math.MaxInt64, 0, func(r *contract.Reading) bool {
+		return r.Pushed > 0 && (name == "" || r.Name == name)
+	})
+}
+
+// Add the reading in place of the readings with the ids, in a single transaction
+func (bc *BoltClient) ReplaceReadings(ids []string, r contract.Reading) (string, error) {
+	var err error
+	r.Id, err = toId(r.Id)
+	if err != nil {
+		return r.Id, err
+	}
+	if r.Created == 0 {
+		r.Created = db.MakeTimestamp()
+	}
+
+	err = bc.db.Update(func(tx *bbolt.Tx) error {
+		for _, id := range ids {
+			var replaced contract.Reading
+			err := getRecord(tx, readingBucket, id, &replaced)
+			if err == db.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err = deleteRecord(tx, readingBucket, readingCreatedIndex, id, replaced.Created); err != nil {
+				return err
+			}
+		}
+		return putRecord(tx, readingBucket, readingCreatedIndex, r.Id, r.Created, r)
+	})
+	return r.Id, err
+}
+
+// Delete the readings created up to end (inclusive) that match the filter
+// When keep is positive, the keep most recently created matching readings are left in place
+func (bc *BoltClient) deleteReadings(end int64, keep int, match func(r *contract.Reading) bool) (int, error) {
+	var found []contract.Reading
+	err := bc.db.Update(func(tx *bbolt.Tx) error {
+		err := walkCreated(tx, readingBucket, readingCreatedIndex, math.MinInt64, func(data []byte) (bool, error) {
+			var r contract.Reading
+			if err := json.Unmarshal(data, &r); err != nil {
+				return false, err
+			}
+			if r.Created > end {
+				return false, nil
+			}
+			if match(&r) {
+				found = append(found, r)
+			}
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// The walk is in creation order, so the most recent readings are at the end
+		if keep > 0 {
+			if keep >= len(found) {
+				found = nil
+			} else {
+				found = found[:len(found)-keep]
+			}
+		}
+		for _, r := range found {
+			if err := deleteRecord(tx, readingBucket, readingCreatedIndex, r.Id, r.Created); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(found), nil
+}
+
+// Return a page of readings whos creation time is in-between start and end, of the value descriptor if any,
+// starting after the cursor
+func (bc *BoltClient) ReadingsPage(start, end int64, name string, cursor string, limit int) ([]contract.Reading, string, error) {
+	after, err := pageKey(start, cursor)
+	if err != nil {
+		return []contract.Reading{}, "", err
+	}
+	if limit <= 0 {
+		return []contract.Reading{}, "", nil
+	}
+
+	// Fetch one more than the page to find out if there is a next page
+	readings, err := bc.getReadingsAfter(after, end, limit+1, func(r *contract.Reading) bool {
+		return name == "" || r.Name == name
+	})
+	if err != nil {
+		return []contract.Reading{}, "", err
+	}
+
+	next := ""
+	if len(readings) > limit {
+		readings = readings[:limit]
+		next = db.Cursor{Created: readings[limit-1].Created, Id: readings[limit-1].Id}.Encode()
+	}
+	return readings, next, nil
+}
+
+// Return a list of readings for a device filtered by the value descriptor and limited by the limit
+// The readings are linked to the device through an event
+func (bc *BoltClient) ReadingsByDeviceAndValueDescriptor(deviceId, valueDescriptor string, limit int) ([]contract.Reading, error) {
+	return bc.getReadings(math.MinInt64, math.MaxInt64, limit, func(r *contract.Reading) bool {
+		return r.Device == deviceId && r.Name == valueDescriptor
+	})
+}
+
+// Get the readings created between start and end (inclusive) that match the filter, in creation order.
+// A negative limit returns all of them; a limit of 0 returns none.
+func (bc *BoltClient) getReadings(start, end int64, limit int, match func(r *contract.Reading) bool) ([]contract.Reading, error) {
+	return bc.getReadingsAfter(createdKey(start, ""), end, limit, match)
+}
+
+// Get the readings created after the creation index key and up to end (inclusive) that match the filter
+func (bc *BoltClient) getReadingsAfter(after []byte, end int64, limit int, match func(r *contract.Reading) bool) ([]contract.Reading, error) {
+	readings := []contract.Reading{}
+	if limit == 0 {
+		return readings, nil
+	}
+
+	err := bc.db.View(func(tx *bbolt.Tx) error {
+		return walkCreatedAfter(tx, readingBucket, readingCreatedIndex, after, func(data []byte) (bool, error) {
+			var r contract.Reading
+			if err := json.Unmarshal(data, &r); err != nil {
+				return false, err
+			}
+			if r.Created > end {
+				return false, nil
+			}
+			if match != nil && !match(&r) {
+				return true, nil
+			}
+			readings = append(readings, r)
+			return limit < 0 || len(readings) < limit, nil
+		})
+	})
+	if err != nil {
+		return []contract.Reading{}, err
+	}
+	return readings, nil
+}
+
+// ************************* VALUE DESCRIPTORS *****************************
+
+// Add a value descriptor
+// 409 - Formatting is bad or it is not unique
+// 503 - Unexpected
+// The formatting is checked by the caller
+func (bc *BoltClient) AddValueDescriptor(v contract.ValueDescriptor) (string, error) {
+	id, err := toId(v.Id)
+	if err != nil {
+		return v.Id, err
+	}
+	v.Id = id
+	if v.Created == 0 {
+		v.Created = db.MakeTimestamp()
+	}
+
+	err = bc.db.Update(func(tx *bbolt.Tx) error {
+		names := tx.Bucket(valueDescriptorNameIndex)
+		// Duplicate name
+		if names.Get([]byte(v.Name)) != nil {
+			return db.ErrNotUnique
+		}
+		if err := names.Put([]byte(v.Name), []byte(v.Id)); err != nil {
+			return err
+		}
+		return putRecord(tx, valueDescriptorBucket, nil, v.Id, v.Created, v)
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.Id, nil
+}
+
+// Return a list of all the value descriptors
+// 513 Service Unavailable - database problems
+func (bc *BoltClient) ValueDescriptors() ([]contract.ValueDescriptor, error) {
+	return bc.getValueDescriptors(nil)
+}
+
+// Update a value descriptor
+// First use the ID for identification, then the name
+// The formatting is checked by the caller
+// 404 not found if the value descriptor cannot be found by the identifiers
+func (bc *BoltClient) UpdateValueDescriptor(cvd contract.ValueDescriptor) error {
+	return bc.db.Update(func(tx *bbolt.Tx) error {
+		names := tx.Bucket(valueDescriptorNameIndex)
+
+		id := cvd.Id
+		if id == "" {
+			if owner := names.Get([]byte(cvd.Name)); owner != nil {
+				id = string(owner)
+			}
+		}
+		id, err := checkId(id)
+		if err != nil {
+			return err
+		}
+
+		var existing contract.ValueDescriptor
+		if err := getRecord(tx, valueDescriptorBucket, id, &existing); err != nil {
+			return err
+		}
+
+		// See if the name is unique if it changed
+		if owner := names.Get([]byte(cvd.Name)); owner != nil && string(owner) != id {
+			return db.ErrNotUnique
+		}
+		if err := names.Delete([]byte(existing.Name)); err != nil {
+			return err
+		}
+		if err := names.Put([]byte(cvd.Name), []byte(id)); err != nil {
+			return err
+		}
+
+		cvd.Id = id
+		cvd.Modified = db.MakeTimestamp()
+		if cvd.Created == 0 {
+			cvd.Created = existing.Created
+		}
+		return putRecord(tx, valueDescriptorBucket, nil, id, cvd.Created, cvd)
+	})
+}
+
+// Delete the value descriptor based on the id
+// Not found error if there isn't a value descriptor for the ID
+// ValueDescriptorStillInUse if the value descriptor is still referenced by readings
+func (bc *BoltClient) DeleteValueDescriptorById(id string) error {
+	id, err := checkId(id)
+	if err != nil {
+		return err
+	}
+
+	return bc.db.Update(func(tx *bbolt.Tx) error {
+		var v contract.ValueDescriptor
+		if err := getRecord(tx, valueDescriptorBucket, id, &v); err != nil {
+			return err
+		}
+		if err := tx.Bucket(valueDescriptorNameIndex).Delete([]byte(v.Name)); err != nil {
+			return err
+		}
+		return deleteRecord(tx, valueDescriptorBucket, nil, id, v.Created)
+	})
+}
+
+// Return a value descriptor based on the name
+// Can return null if no value descriptor is found
+func (bc *BoltClient) ValueDescriptorByName(name string) (contract.ValueDescriptor, error) {
+	var v contract.ValueDescriptor
+	err := bc.db.View(func(tx *bbolt.Tx) error {
+		id := tx.Bucket(valueDescriptorNameIndex).Get([]byte(name))
+		if id == nil {
+			return db.ErrNotFound
+		}
+		return getRecord(tx, valueDescriptorBucket, string(id), &v)
+	})
+	if err != nil {
+		return contract.ValueDescriptor{}, err
+	}
+	return v, nil
+}
+
+// Return all of the value descriptors based on the names
+func (bc *BoltClient) ValueDescriptorsByName(names []string) ([]contract.ValueDescriptor, error) {
+	vList := []contract.ValueDescriptor{}
+
+	for _, name := range names {
+		v, err := bc.ValueDescriptorByName(name)
+		if err != nil && err != db.ErrNotFound {
+			return []contract.ValueDescriptor{}, err
+		}
+		if err == nil {
+			vList = append(vList, v)
+		}
+	}
+
+	return vList, nil
+}
+
+// Return a value descriptor based on the id
+// Return NotFoundError if there is no value descriptor for the id
+func (bc *BoltClient) ValueDescriptorById(id string) (contract.ValueDescriptor, error) {
+	id, err := checkId(id)
+	if err != nil {
+		return contract.ValueDescriptor{}, err
+	}
+
+	var v contract.ValueDescriptor
+	err = bc.db.View(func(tx *bbolt.Tx) error {
+		return getRecord(tx, valueDescriptorBucket, id, &v)
+	})
+	if err != nil {
+		return contract.ValueDescriptor{}, err
+	}
+	return v, nil
+}
+
+// Return all the value descriptors that match the UOM label
+func (bc *BoltClient) ValueDescriptorsByUomLabel(uomLabel string) ([]contract.ValueDescriptor, error) {
+	return bc.getValueDescriptors(func(v *contract.ValueDescriptor) bool {
+		return v.UomLabel == uomLabel
+	})
+}
+
+// Return value descriptors based on if it has the label
+func (bc *BoltClient) ValueDescriptorsByLabel(label string) ([]contract.ValueDescriptor, error) {
+	return bc.getValueDescriptors(func(v *contract.ValueDescriptor) bool {
+		return hasLabel(v, label)
+	})
+}
+
+func hasLabel(v *contract.ValueDescriptor, label string) bool {
+	for _, l := range v.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// Return value descriptors based on the type
+func (bc *BoltClient) ValueDescriptorsByType(t string) ([]contract.ValueDescriptor, error) {
+	return bc.getValueDescriptors(func(v *contract.ValueDescriptor) bool {
+		return v.Type == t
+	})
+}
+
+// Delete all of the value descriptors
+func (bc *BoltClient) ScrubAllValueDescriptors() error {
+	return bc.db.Update(func(tx *bbolt.Tx) error {
+		return clearBuckets(tx, valueDescriptorBucket, valueDescriptorNameIndex)
+	})
+}
+
+// Get the value descriptors that match the filter
+func (bc *BoltClient) getValueDescriptors(match func(v *contract.ValueDescriptor) bool) ([]contract.ValueDescriptor, error) {
+	values := []contract.ValueDescriptor{}
+	err := bc.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(valueDescriptorBucket).ForEach(func(k, data []byte) error {
+			var v contract.ValueDescriptor
+			if err := json.Unmarshal(data, &v); err != nil {
+				return err
+			}
+			if match == nil || match(&v) {
+				values = append(values, v)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return []contract.ValueDescriptor{}, err
+	}
+	return values, nil
+}
+
+// Get the number of records in a bucket
+func (bc *BoltClient) count(bucket []byte) (int, error) {
+	var n int
+	err := bc.db.View(func(tx *bbolt.Tx) error {
+		n = tx.Bucket(bucket).Stats().KeyN
+		return nil
+	})
+	return n, err
+}
@@ -22,6 +22,7 @@ import (
 const (
 	// Databases
 	MongoDB  = "mongodb"
+	BoltDB   = "boltdb"
 
 	// Data
 	EventsCollection          = "event"
@@ -56,6 +57,7 @@ var (
 	ErrCommandStillInUse   = errors.New("Command is still in use by device profiles")
 	ErrSlugEmpty           = errors.New("Slug is nil or empty")
 	ErrNameEmpty           = errors.New("Name is required")
+	ErrInvalidCursor       = errors.New("Invalid cursor")
 )
 
 type Configuration struct {
@@ -17,6 +17,7 @@ import (
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db/mongo/models"
 	contract "github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	"github.com/google/uuid"
 )
@@ -158,6 +159,16 @@ func (mc MongoClient) EventsForDevice(id string) ([]contract.Event, error) {
 	return mapEvents(mc.getEvents(bson.M{"device": id}))
 }
 
+// Get a list of events holding the tag with the value, limited by the limit
+func (mc MongoClient) EventsByTag(key, value string, limit int) ([]contract.Event, error) {
+	return mapEvents(mc.getEventsLimit(bson.M{"tags." + key: value}, limit))
+}
+
+// Delete all of the events by the device id (and the readings)
+func (mc MongoClient) DeleteEventsByDeviceId(id string) (int, error) {
+	return mc.deleteEvents(bson.M{"device": id}, 0)
+}
+
 // Return a list of events whos creation time is between startTime and endTime
 // Limit the number of results by limit
 func (mc MongoClient) EventsByCreationTime(startTime, endTime int64, limit int) ([]contract.Event, error) {
@@ -168,17 +179,83 @@ func (mc MongoClient) EventsByCreationTime(startTime, endTime int64, limit int)
 	return mapEvents(mc.getEventsLimit(query, limit))
 }
 
+// Return a page of events whos creation time is between startTime and endTime, of the devices if any
+// Sort the events by creation time and ID, starting after the cursor
+func (mc MongoClient) EventsPage(startTime, endTime int64, devices []string, cursor string, limit int) ([]contract.Event, string, error) {
+	query, err := pageQuery(startTime, endTime, cursor)
+	if err != nil {
+		return []contract.Event{}, "", err
+	}
+	if len(devices) > 0 {
+		query["device"] = bson.M{"$in": devices}
+	}
+
+	if limit <= 0 {
+		return []contract.Event{}, "", nil
+	}
+
+	s := mc.getSessionCopy()
+	defer s.Close()
+
+	me := []models.Event{}
+
+	// Fetch one more than the page to find out if there is a next page
+	err = s.DB(mc.database.Name).C(db.EventsCollection).Find(query).Sort("created", "_id").Limit(limit + 1).All(&me)
+	if err != nil {
+		return []contract.Event{}, "", err
+	}
+
+	next := ""
+	if len(me) > limit {
+		me = me[:limit]
+		next = db.Cursor{Created: me[limit-1].Created, Id: me[limit-1].Id.Hex()}.Encode()
+	}
+
+	events, err := mapEvents(mc.getReadingsForEventList(me))
+	if err != nil {
+		return []contract.Event{}, "", err
+	}
+	return events, next, nil
+}
+
 // Get Events that are older than the given age (defined by age = now - created)
 func (mc MongoClient) EventsOlderThanAge(age int64) ([]contract.Event, error) {
 	expireDate := (db.MakeTimestamp()) - age
 	return mapEvents(mc.getEvents(bson.M{"created": bson.M{"$lt": expireDate}}))
 }
 
+// Remove all the events, and their readings, that are older than the given age
+func (mc MongoClient) RemoveEventByAge(age int64) (int, error) {
+	return mc.DeleteEventsCreatedBefore("", db.MakeTimestamp()-age)
+}
+
 // Get all of the events that have been pushed
 func (mc MongoClient) EventsPushed() ([]contract.Event, error) {
 	return mapEvents(mc.getEvents(bson.M{"pushed": bson.M{"$gt": int64(0)}}))
 }
 
+// Delete the events, and their readings, created before the given time
+func (mc MongoClient) DeleteEventsCreatedBefore(device string, created int64) (int, error) {
+	query := bson.M{"created": bson.M{"$lt": created}}
+	return mc.deleteEvents(matchField(query, "device", device), 0)
+}
+
+// Delete the events, and their readings, beyond the given number of most recently created ones
+func (mc MongoClient) DeleteEventsBeyondCount(device string, count int) (int, error) {
+	return mc.deleteEvents(matchField(bson.M{}, "device", device), count)
+}
+
+// Delete the events, and their readings, that have been pushed
+func (mc MongoClient) DeletePushedEvents(device string) (int, error) {
+	query := bson.M{"pushed": bson.M{"$gt": int64(0)}}
+	return mc.deleteEvents(matchField(query, "device", device), 0)
+}
+
+// Remove all the events, and their readings, that have been pushed
+func (mc MongoClient) ScrubEvents() (int, error) {
+	return mc.DeletePushedEvents("")
+}
+
 // Delete all of the readings and all of the events
 func (mc MongoClient) ScrubAllEvents() error {
 	s := mc.getSessionCopy()
@@ -211,6 +288,41 @@ func (mc MongoClient) getEvents(q bson.M) ([]models.Event, error) {
 	return mc.getReadingsForEventList(me)
 }
 
+// Delete the events matching the query along with their readings
+// When keep is positive, the keep most recently created events are left in place
+func (mc MongoClient) deleteEvents(q bson.M, keep int) (int, error) {
+	s := mc.getSessionCopy()
+	defer s.Close()
+
+	var found []struct {
+		Id       bson.ObjectId `bson:"_id"`
+		Readings []mgo.DBRef   `bson:"readings"`
+	}
+	query := s.DB(mc.database.Name).C(db.EventsCollection).Find(q).Select(bson.M{"_id": 1, "readings": 1})
+	if keep > 0 {
+		query = query.Sort("-created", "-_id").Skip(keep)
+	}
+	err := query.All(&found)
+	if err != nil {
+		return 0, err
+	}
+
+	events := make([]interface{}, 0, len(found))
+	readings := []interface{}{}
+	for _, e := range found {
+		events = append(events, e.Id)
+		for _, r := range e.Readings {
+			readings = append(readings, r.Id)
+		}
+	}
+
+	_, err = removeIds(s.DB(mc.database.Name).C(db.ReadingsCollection), readings)
+	if err != nil {
+		return 0, err
+	}
+	return removeIds(s.DB(mc.database.Name).C(db.EventsCollection), events)
+}
+
 // Get events with a limit
 func (mc MongoClient) getEventsLimit(q bson.M, limit int) ([]models.Event, error) {
 	s := mc.getSessionCopy()
@@ -340,6 +452,38 @@ func (mc MongoClient) ReadingsByValueDescriptorNames(names []string, limit int)
 	return mapReadings(mc.getReadingsLimit(query, limit))
 }
 
+// Return a list of readings whose value descriptor has the UOM label
+func (mc MongoClient) ReadingsByUomLabel(uomLabel string, limit int) ([]contract.Reading, error) {
+	return mc.readingsByValueDescriptors(bson.M{"uomLabel": uomLabel}, limit)
+}
+
+// Return a list of readings whose value descriptor has the label
+func (mc MongoClient) ReadingsByLabel(label string, limit int) ([]contract.Reading, error) {
+	return mc.readingsByValueDescriptors(bson.M{"labels": label}, limit)
+}
+
+// Return a list of readings whose value descriptor has the type
+func (mc MongoClient) ReadingsByType(typeString string, limit int) ([]contract.Reading, error) {
+	return mc.readingsByValueDescriptors(bson.M{"type": typeString}, limit)
+}
+
+// Return a list of readings whose value descriptor matches the query
+// Only the names of the value descriptors are read, with a single distinct query
+func (mc MongoClient) readingsByValueDescriptors(q bson.M, limit int) ([]contract.Reading, error) {
+	s := mc.getSessionCopy()
+	defer s.Close()
+
+	var names []string
+	err := s.DB(mc.database.Name).C(db.ValueDescriptorCollection).Find(q).Distinct("name", &names)
+	if err != nil {
+		return []contract.Reading{}, err
+	}
+	if len(names) == 0 {
+		return []contract.Reading{}, nil
+	}
+	return mapReadings(mc.getReadingsLimit(bson.M{"name": bson.M{"$in": names}}, limit))
+}
+
 // Return a list of readings whos creation time is in-between start and end
 // Limit by the limit parameter
 func (mc MongoClient) ReadingsByCreationTime(start, end int64, limit int) ([]contract.Reading, error) {
@@ -350,6 +494,148 @@ func (mc MongoClient) ReadingsByCreationTime(start, end int64, limit int) ([]con
 	return mapReadings(mc.getReadingsLimit(query, limit))
 }
 
+// Return a list of readings for the value descriptor whos creation time is in-between start and end
+// Limit by the limit parameter
+func (mc MongoClient) ReadingsByValueDescriptorAndCreationTime(name string, start, end int64, limit int) ([]contract.Reading, error) {
+	query := bson.M{"name": name, "created": bson.M{
+		"$gte": start,
+		"$lte": end,
+	}}
+	return mapReadings(mc.getReadingsLimit(query, limit))
+}
+
+// Summarize the numeric readings of the value descriptor created between start (inclusive) and end (exclusive)
+// The readings are streamed in creation order, as values are stored as strings that cannot be summed by a $group
+func (mc MongoClient) ReadingAggregates(name string, device string, start, end, interval int64) ([]contract.ReadingAggregate, error) {
+	s := mc.getSessionCopy()
+	defer s.Close()
+
+	query := bson.M{"name": name, "created": bson.M{
+		"$gte": start,
+		"$lt":  end,
+	}}
+	query = matchField(query, "device", device)
+
+	aggregator := db.NewReadingAggregator(name, start, end, interval)
+	iter := s.DB(mc.database.Name).C(db.ReadingsCollection).Find(query).Sort("created", "_id").Iter()
+	var r models.Reading
+	for iter.Next(&r) {
+		aggregator.Add(r.ToContract())
+		r = models.Reading{}
+	}
+	if err := iter.Close(); err != nil {
+		return []contract.ReadingAggregate{}, err
+	}
+	return aggregator.Aggregates(), nil
+}
+
+// Delete the readings created before the given time
+func (mc MongoClient) DeleteReadingsCreatedBefore(name string, created int64) (int, error) {
+	query := bson.M{"created": bson.M{"$lt": created}}
+	return mc.deleteReadings(matchField(query, "name", name))
+}
+
+// Delete the readings beyond the given number of most recently created ones
+func (mc MongoClient) DeleteReadingsBeyondCount(name string, count int) (int, error) {
+	s := mc.getSessionCopy()
+	defer s.Close()
+
+	var found []struct {
+		Id bson.ObjectId `bson:"_id"`
+	}
+	query := matchField(bson.M{}, "name", name)
+	err := s.DB(mc.database.Name).C(db.ReadingsCollection).Find(query).Select(bson.M{"_id": 1}).Sort("-created", "-_id").Skip(count).All(&found)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]interface{}, 0, len(found))
+	for _, r := range found {
+		ids = append(ids, r.Id)
+	}
+	return removeIds(s.DB(mc.database.Name).C(db.ReadingsCollection), ids)
+}
+
+// Delete the readings that have been pushed
+func (mc MongoClient) DeletePushedReadings(name string) (int, error) {
+	query := bson.M{"pushed": bson.M{"$gt": int64(0)}}
+	return mc.deleteReadings(matchField(query, "name", name))
+}
+
+// Add the reading in place of the readings with the ids
+// The reading is added first, so that the replaced readings are not lost should adding it fail
+func (mc MongoClient) ReplaceReadings(ids []string, r contract.Reading) (string, error) {
+	id, err := mc.AddReading(r)
+	if err != nil {
+		return id, err
+	}
+
+	s := mc.getSessionCopy()
+	defer s.Close()
+
+	// Readings are identified by their UUID, or by their BSON id for older readings
+	var oids, uuids []interface{}
+	for _, i := range ids {
+		if bson.IsObjectIdHex(i) {
+			oids = append(oids, bson.ObjectIdHex(i))
+		} else {
+			uuids = append(uuids, i)
+		}
+	}
+	c := s.DB(mc.database.Name).C(db.ReadingsCollection)
+	if _, err = removeIds(c, oids); err != nil {
+		return id, err
+	}
+	_, err = removeField(c, "uuid", uuids)
+	return id, err
+}
+
+// Delete the readings matching the query
+func (mc MongoClient) deleteReadings(q bson.M) (int, error) {
+	s := mc.getSessionCopy()
+	defer s.Close()
+
+	info, err := s.DB(mc.database.Name).C(db.ReadingsCollection).RemoveAll(q)
+	if err != nil {
+		return 0, err
+	}
+	return info.Removed, nil
+}
+
+// Return a page of readings whos creation time is in-between start and end, of the value descriptor if any
+// Sort the readings by creation time and ID, starting after the cursor
+func (mc MongoClient) ReadingsPage(start, end int64, name string, cursor string, limit int) ([]contract.Reading, string, error) {
+	query, err := pageQuery(start, end, cursor)
+	if err != nil {
+		return []contract.Reading{}, "", err
+	}
+	query = matchField(query, "name", name)
+
+	if limit <= 0 {
+		return []contract.Reading{}, "", nil
+	}
+
+	s := mc.getSessionCopy()
+	defer s.Close()
+
+	readings := []models.Reading{}
+
+	// Fetch one more than the page to find out if there is a next page
+	err = s.DB(mc.database.Name).C(db.ReadingsCollection).Find(query).Sort("created", "_id").Limit(limit + 1).All(&readings)
+	if err != nil {
+		return []contract.Reading{}, "", err
+	}
+
+	next := ""
+	if len(readings) > limit {
+		readings = readings[:limit]
+		next = db.Cursor{Created: readings[limit-1].Created, Id: readings[limit-1].Id.Hex()}.Encode()
+	}
+
+	mapped, err := mapReadings(readings, nil)
+	return mapped, next, err
+}
+
 // Return a list of readings for a device filtered by the value descriptor and limited by the limit
 // The readings are linked to the device through an event
 func (mc MongoClient) ReadingsByDeviceAndValueDescriptor(deviceId, valueDescriptor string, limit int) ([]contract.Reading, error) {
@@ -402,6 +688,10 @@ func (mc MongoClient) getReadingsForEvent(event models.Event) ([]models.Reading,
 	for _, rRef := range event.GetDBRefs() {
 		var reading models.Reading
 		err := mc.database.C(db.ReadingsCollection).FindId(rRef.Id).One(&reading)
+		if err == mgo.ErrNotFound {
+			// The reading was replaced by a summary when downsampling
+			continue
+		}
 		if err != nil {
 			return []models.Reading{}, errorMap(err)
 		}
@@ -435,7 +725,7 @@ func (mc MongoClient) getReadingsForEventList(events []models.Event) ([]models.E
 // Add a value descriptor
 // 409 - Formatting is bad or it is not unique
 // 503 - Unexpected
-// TODO: Check for valid printf formatting
+// The formatting is checked by the caller
 func (mc MongoClient) AddValueDescriptor(v contract.ValueDescriptor) (string, error) {
 	s := mc.getSessionCopy()
 	defer s.Close()
@@ -470,7 +760,7 @@ func (mc MongoClient) ValueDescriptors() ([]contract.ValueDescriptor, error) {
 
 // Update a value descriptor
 // First use the ID for identification, then the name
-// TODO: Check for the valid printf formatting
+// The formatting is checked by the caller
 // 404 not found if the value descriptor cannot be found by the identifiers
 func (mc MongoClient) UpdateValueDescriptor(cvd contract.ValueDescriptor) error {
 	s := mc.getSessionCopy()
@@ -630,6 +920,63 @@ func (mc MongoClient) getValueDescriptor(q bson.M) (models.ValueDescriptor, erro
 	return m, err
 }
 
+// Maximum number of IDs in a single bulk removal
+const removeChunkSize = 1000
+
+// Remove the documents with the given IDs, in chunks to keep each query small
+func removeIds(c *mgo.Collection, ids []interface{}) (int, error) {
+	return removeField(c, "_id", ids)
+}
+
+// Remove the documents whose field has one of the values, in chunks to keep each query small
+func removeField(c *mgo.Collection, field string, values []interface{}) (int, error) {
+	removed := 0
+	for start := 0; start < len(values); start += removeChunkSize {
+		end := start + removeChunkSize
+		if end > len(values) {
+			end = len(values)
+		}
+		info, err := c.RemoveAll(bson.M{field: bson.M{"$in": values[start:end]}})
+		if err != nil {
+			return removed, err
+		}
+		removed += info.Removed
+	}
+	return removed, nil
+}
+
+// Add an equality match on the field to the query, unless the value is empty
+func matchField(q bson.M, field string, value string) bson.M {
+	if value != "" {
+		q[field] = value
+	}
+	return q
+}
+
+// Build the query for a page of records created between start and end, after the position of the cursor
+func pageQuery(start, end int64, cursor string) (bson.M, error) {
+	query := bson.M{"created": bson.M{
+		"$gte": start,
+		"$lte": end,
+	}}
+	if cursor == "" {
+		return query, nil
+	}
+
+	c, err := db.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if !bson.IsObjectIdHex(c.Id) {
+		return nil, db.ErrInvalidCursor
+	}
+	query["$or"] = []bson.M{
+		{"created": bson.M{"$gt": c.Created}},
+		{"created": c.Created, "_id": bson.M{"$gt": bson.ObjectIdHex(c.Id)}},
+	}
+	return query, nil
+}
+
 func mapEvents(events []models.Event, err error) ([]contract.Event, error) {
 	if err != nil {
 		return []contract.Event{}, err
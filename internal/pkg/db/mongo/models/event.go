@@ -31,6 +31,7 @@ type Event struct {
 	Origin   int64
 	Event    string
 	Readings []Reading
+	Tags     map[string]string
 	dbRefs   []mgo.DBRef
 }
 
@@ -49,6 +50,7 @@ func (e *Event) ToContract() contract.Event {
 		Origin:   e.Origin,
 		Event:    e.Event,
 		Readings: []contract.Reading{},
+		Tags:     e.Tags,
 	}
 	for _, r := range e.Readings {
 		to.Readings = append(to.Readings, r.ToContract())
@@ -69,6 +71,7 @@ func (e *Event) FromContract(from contract.Event) error {
 	e.Modified = from.Modified
 	e.Origin = from.Origin
 	e.Event = from.Event
+	e.Tags = from.Tags
 	e.Readings = []Reading{}
 	for _, val := range from.Readings {
 		r := &Reading{}
@@ -97,16 +100,17 @@ func (e *Event) GetBSON() (interface{}, error) {
 	}
 
 	return struct {
-		ID       bson.ObjectId `bson:"_id,omitempty"`
-		Uuid     string        `bson:"uuid,omitempty"`
-		Pushed   int64         `bson:"pushed"`
-		Device   string        `bson:"device"` // Device identifier (name or id)
-		Created  int64         `bson:"created"`
-		Modified int64         `bson:"modified"`
-		Origin   int64         `bson:"origin"`
-		Schedule string        `bson:"schedule,omitempty"` // Schedule identifier
-		Event    string        `bson:"event"`              // Schedule event identifier
-		Readings []mgo.DBRef   `bson:"readings,omitempty"` // List of readings
+		ID       bson.ObjectId     `bson:"_id,omitempty"`
+		Uuid     string            `bson:"uuid,omitempty"`
+		Pushed   int64             `bson:"pushed"`
+		Device   string            `bson:"device"` // Device identifier (name or id)
+		Created  int64             `bson:"created"`
+		Modified int64             `bson:"modified"`
+		Origin   int64             `bson:"origin"`
+		Schedule string            `bson:"schedule,omitempty"` // Schedule identifier
+		Event    string            `bson:"event"`              // Schedule event identifier
+		Readings []mgo.DBRef       `bson:"readings,omitempty"` // List of readings
+		Tags     map[string]string `bson:"tags,omitempty"`
 	}{
 		ID:       e.Id,
 		Uuid:     e.Uuid,
@@ -117,22 +121,24 @@ func (e *Event) GetBSON() (interface{}, error) {
 		Origin:   e.Origin,
 		Event:    e.Event,
 		Readings: readings,
+		Tags:     e.Tags,
 	}, nil
 }
 
 // Custom unmarshaling out of Mongo
 func (e *Event) SetBSON(raw bson.Raw) error {
 	decoded := new(struct {
-		ID       bson.ObjectId `bson:"_id,omitempty"`
-		Uuid     string        `bson:"uuid,omitempty"`
-		Pushed   int64         `bson:"pushed"`
-		Device   string        `bson:"device"` // Device identifier (name or id)
-		Created  int64         `bson:"created"`
-		Modified int64         `bson:"modified"`
-		Origin   int64         `bson:"origin"`
-		Schedule string        `bson:"schedule,omitempty"` // Schedule identifier
-		Event    string        `bson:"event"`              // Schedule event identifier
-		DBRefs   []mgo.DBRef   `bson:"readings"`           // List of readings
+		ID       bson.ObjectId     `bson:"_id,omitempty"`
+		Uuid     string            `bson:"uuid,omitempty"`
+		Pushed   int64             `bson:"pushed"`
+		Device   string            `bson:"device"` // Device identifier (name or id)
+		Created  int64             `bson:"created"`
+		Modified int64             `bson:"modified"`
+		Origin   int64             `bson:"origin"`
+		Schedule string            `bson:"schedule,omitempty"` // Schedule identifier
+		Event    string            `bson:"event"`              // Schedule event identifier
+		DBRefs   []mgo.DBRef       `bson:"readings"`           // List of readings
+		Tags     map[string]string `bson:"tags,omitempty"`
 	})
 
 	bsonErr := raw.Unmarshal(decoded)
@@ -150,6 +156,7 @@ func (e *Event) SetBSON(raw bson.Raw) error {
 	e.Origin = decoded.Origin
 	e.Event = decoded.Event
 	e.Readings = []Reading{}
+	e.Tags = decoded.Tags
 	e.dbRefs = decoded.DBRefs
 	return nil
 }
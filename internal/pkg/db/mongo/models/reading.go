@@ -29,6 +29,12 @@ type Reading struct {
 	Device   string        `bson:"device"`
 	Name     string        `bson:"name"`
 	Value    string        `bson:"value"` // Device sensor data value
+	// Raw data of binary readings
+	BinaryValue []byte `bson:"binaryValue,omitempty"`
+	// Set when the value fails the assertion of its device resource
+	AssertionFailed bool `bson:"assertionFailed,omitempty"`
+	// Set on the readings summarizing the older readings they replaced
+	Summary *contract.ReadingAggregate `bson:"summary,omitempty"`
 }
 
 func (r *Reading) ToContract() contract.Reading {
@@ -38,14 +44,17 @@ func (r *Reading) ToContract() contract.Reading {
 		id = r.Id.Hex()
 	}
 	to := contract.Reading{
-		Id:       id,
-		Pushed:   r.Pushed,
-		Created:  r.Created,
-		Origin:   r.Origin,
-		Modified: r.Modified,
-		Device:   r.Device,
-		Name:     r.Name,
-		Value:    r.Value,
+		Id:              id,
+		Pushed:          r.Pushed,
+		Created:         r.Created,
+		Origin:          r.Origin,
+		Modified:        r.Modified,
+		Device:          r.Device,
+		Name:            r.Name,
+		Value:           r.Value,
+		BinaryValue:     r.BinaryValue,
+		AssertionFailed: r.AssertionFailed,
+		Summary:         r.Summary,
 	}
 	return to
 }
@@ -64,6 +73,9 @@ func (r *Reading) FromContract(from contract.Reading) error {
 	r.Device = from.Device
 	r.Name = from.Name
 	r.Value = from.Value
+	r.BinaryValue = from.BinaryValue
+	r.AssertionFailed = from.AssertionFailed
+	r.Summary = from.Summary
 
 	if r.Created == 0 {
 		r.Created = db.MakeTimestamp()
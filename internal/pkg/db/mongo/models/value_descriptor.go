@@ -34,6 +34,8 @@ type ValueDescriptor struct {
 	UomLabel     string        `bson:"uomLabel,omitempty"`
 	Formatting   string        `bson:"formatting,omitempty"`
 	Labels       []string      `bson:"labels,omitempty"`
+	// Values allowed for an enumeration
+	AllowedValues []string `bson:"allowedValues,omitempty"`
 }
 
 func (v *ValueDescriptor) ToContract() contract.ValueDescriptor {
@@ -60,6 +62,9 @@ func (v *ValueDescriptor) ToContract() contract.ValueDescriptor {
 	for _, l := range v.Labels {
 		to.Labels = append(to.Labels, l)
 	}
+	if len(v.AllowedValues) > 0 {
+		to.AllowedValues = append([]string{}, v.AllowedValues...)
+	}
 	return to
 }
 
@@ -86,6 +91,9 @@ func (v *ValueDescriptor) FromContract(from contract.ValueDescriptor) error {
 	for _, l := range from.Labels {
 		v.Labels = append(v.Labels, l)
 	}
+	if len(from.AllowedValues) > 0 {
+		v.AllowedValues = append([]string{}, from.AllowedValues...)
+	}
 
 	if v.Created == 0 {
 		v.Created = db.MakeTimestamp()
@@ -243,6 +243,65 @@ func testDBReadings(t *testing.T, db interfaces.DBClient) {
 		t.Fatalf("There should be 100 readings, not %d", len(readings))
 	}
 
+	seen := map[string]bool{}
+	pages := 0
+	cursor := ""
+	for {
+		readings, cursor, err = db.ReadingsPage(beforeTime, afterTime, "", cursor, 50)
+		if err != nil {
+			t.Fatalf("Error getting ReadingsPage: %v", err)
+		}
+		pages++
+		for i, r := range readings {
+			if seen[r.Id] {
+				t.Fatalf("Reading %s returned twice by ReadingsPage", r.Id)
+			}
+			seen[r.Id] = true
+			if i > 0 && r.Created < readings[i-1].Created {
+				t.Fatalf("ReadingsPage should be ordered by creation time")
+			}
+		}
+		if cursor == "" {
+			break
+		}
+	}
+	if len(seen) != 110 || pages != 3 {
+		t.Fatalf("There should be 110 readings in 3 pages, not %d in %d", len(seen), pages)
+	}
+	_, _, err = db.ReadingsPage(beforeTime, afterTime, "", "INVALID", 50)
+	if err != dbp.ErrInvalidCursor {
+		t.Fatalf("ReadingsPage should reject an invalid cursor: %v", err)
+	}
+	readings, cursor, err = db.ReadingsPage(beforeTime, afterTime, "name1", "", 50)
+	if err != nil {
+		t.Fatalf("Error getting ReadingsPage of a value descriptor: %v", err)
+	}
+	if len(readings) != 2 || cursor != "" {
+		t.Fatalf("There should be 2 readings of the value descriptor, not %d", len(readings))
+	}
+
+	readings, err = db.ReadingsByValueDescriptorAndCreationTime("name1", beforeTime, afterTime, 10)
+	if err != nil {
+		t.Fatalf("Error getting ReadingsByValueDescriptorAndCreationTime: %v", err)
+	}
+	if len(readings) != 2 {
+		t.Fatalf("There should be 2 readings, not %d", len(readings))
+	}
+	readings, err = db.ReadingsByValueDescriptorAndCreationTime("name1", beforeTime, afterTime, 1)
+	if err != nil {
+		t.Fatalf("Error getting ReadingsByValueDescriptorAndCreationTime: %v", err)
+	}
+	if len(readings) != 1 {
+		t.Fatalf("There should be 1 readings, not %d", len(readings))
+	}
+	readings, err = db.ReadingsByValueDescriptorAndCreationTime("name1", afterTime, afterTime+1, 10)
+	if err != nil {
+		t.Fatalf("Error getting ReadingsByValueDescriptorAndCreationTime: %v", err)
+	}
+	if len(readings) != 0 {
+		t.Fatalf("There should be 0 readings, not %d", len(readings))
+	}
+
 	r := contract.Reading{}
 	r.Id = id
 	r.Name = "name"
@@ -419,6 +478,60 @@ func testDBEvents(t *testing.T, db interfaces.DBClient) {
 		t.Fatalf("There should be 100 events, not %d", len(events))
 	}
 
+	seen := map[string]bool{}
+	pages := 0
+	cursor := ""
+	for {
+		events, cursor, err = db.EventsPage(beforeTime, afterTime, nil, cursor, 30)
+		if err != nil {
+			t.Fatalf("Error getting EventsPage: %v", err)
+		}
+		pages++
+		for i, e := range events {
+			if seen[e.ID] {
+				t.Fatalf("Event %s returned twice by EventsPage", e.ID)
+			}
+			seen[e.ID] = true
+			if i > 0 && e.Created < events[i-1].Created {
+				t.Fatalf("EventsPage should be ordered by creation time")
+			}
+		}
+		if cursor == "" {
+			break
+		}
+		if len(events) != 30 {
+			t.Fatalf("There should be 30 events in a page with a next cursor, not %d", len(events))
+		}
+	}
+	if len(seen) != 110 || pages != 4 {
+		t.Fatalf("There should be 110 events in 4 pages, not %d in %d", len(seen), pages)
+	}
+	_, _, err = db.EventsPage(beforeTime, afterTime, nil, "INVALID", 30)
+	if err != dbp.ErrInvalidCursor {
+		t.Fatalf("EventsPage should reject an invalid cursor: %v", err)
+	}
+
+	devices := []string{"name1", "name2"}
+	events, cursor, err = db.EventsPage(beforeTime, afterTime, devices, "", 3)
+	if err != nil {
+		t.Fatalf("Error getting EventsPage of devices: %v", err)
+	}
+	if len(events) != 3 || cursor == "" {
+		t.Fatalf("There should be a full page of 3 events of the devices, not %d", len(events))
+	}
+	last, cursor, err := db.EventsPage(beforeTime, afterTime, devices, cursor, 3)
+	if err != nil {
+		t.Fatalf("Error getting EventsPage of devices: %v", err)
+	}
+	if len(last) != 1 || cursor != "" {
+		t.Fatalf("There should be a last page of 1 event of the devices, not %d", len(last))
+	}
+	for _, e := range append(events, last...) {
+		if e.Device != "name1" && e.Device != "name2" {
+			t.Fatalf("EventsPage returned an event of device %s", e.Device)
+		}
+	}
+
 	events, err = db.EventsOlderThanAge(0)
 	if err != nil {
 		t.Fatalf("Error getting EventsOlderThanAge: %v", err)
@@ -645,9 +758,449 @@ func testDBValueDescriptors(t *testing.T, db interfaces.DBClient) {
 	}
 }
 
+func testDBBulkEvents(t *testing.T, db interfaces.DBClient) {
+	err := db.ScrubAllEvents()
+	if err != nil {
+		t.Fatalf("Error removing all events")
+	}
+
+	_, err = populateDbEvents(db, 10, 0)
+	if err != nil {
+		t.Fatalf("Error populating db: %v\n", err)
+	}
+	_, err = populateDbEvents(db, 10, 1)
+	if err != nil {
+		t.Fatalf("Error populating db: %v\n", err)
+	}
+	e := contract.Event{Device: "name1", Readings: []contract.Reading{{Name: "name"}, {Name: "name"}}}
+	_, err = db.AddEvent(e)
+	if err != nil {
+		t.Fatalf("Error adding event: %v", err)
+	}
+
+	removed, err := db.DeleteEventsByDeviceId("name1")
+	if err != nil {
+		t.Fatalf("Error deleting events by device id: %v", err)
+	}
+	if removed != 3 {
+		t.Fatalf("There should be 3 events removed, not %d", removed)
+	}
+	count, err := db.ReadingCount()
+	if err != nil {
+		t.Fatalf("Error getting readings count:  %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("The readings of the events should be removed, %d left", count)
+	}
+
+	removed, err = db.ScrubEvents()
+	if err != nil {
+		t.Fatalf("Error scrubbing events: %v", err)
+	}
+	if removed != 9 {
+		t.Fatalf("There should be 9 events removed, not %d", removed)
+	}
+
+	removed, err = db.RemoveEventByAge(60000)
+	if err != nil {
+		t.Fatalf("Error removing events by age: %v", err)
+	}
+	if removed != 0 {
+		t.Fatalf("There should be 0 events removed, not %d", removed)
+	}
+	removed, err = db.RemoveEventByAge(-60000)
+	if err != nil {
+		t.Fatalf("Error removing events by age: %v", err)
+	}
+	if removed != 9 {
+		t.Fatalf("There should be 9 events removed, not %d", removed)
+	}
+	count, err = db.EventCount()
+	if err != nil {
+		t.Fatalf("Error getting events count:  %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("There should be 0 events instead of %d", count)
+	}
+}
+
+func testDBEventTags(t *testing.T, db interfaces.DBClient) {
+	err := db.ScrubAllEvents()
+	if err != nil {
+		t.Fatalf("Error removing all events")
+	}
+
+	_, err = populateDbEvents(db, 5, 0)
+	if err != nil {
+		t.Fatalf("Error populating db: %v\n", err)
+	}
+	tagged := contract.Event{Device: "name", Tags: map[string]string{"site": "north", "batch": "42"}}
+	id, err := db.AddEvent(tagged)
+	if err != nil {
+		t.Fatalf("Error adding event: %v", err)
+	}
+	tagged.Tags = map[string]string{"site": "south"}
+	_, err = db.AddEvent(tagged)
+	if err != nil {
+		t.Fatalf("Error adding event: %v", err)
+	}
+
+	e, err := db.EventById(id)
+	if err != nil {
+		t.Fatalf("Error getting event by id: %v", err)
+	}
+	if len(e.Tags) != 2 || e.Tags["site"] != "north" || e.Tags["batch"] != "42" {
+		t.Fatalf("The tags of the event should be kept, not %v", e.Tags)
+	}
+
+	events, err := db.EventsByTag("site", "north", 10)
+	if err != nil {
+		t.Fatalf("Error getting events by tag: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != id {
+		t.Fatalf("There should be 1 event with id %s, not %v", id, events)
+	}
+	events, err = db.EventsByTag("batch", "41", 10)
+	if err != nil {
+		t.Fatalf("Error getting events by tag: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("There should be 0 events, not %d", len(events))
+	}
+
+	e.Pushed = 1
+	err = db.UpdateEvent(e)
+	if err != nil {
+		t.Fatalf("Error updating event: %v", err)
+	}
+	e, err = db.EventById(id)
+	if err != nil {
+		t.Fatalf("Error getting event by id: %v", err)
+	}
+	if e.Tags["site"] != "north" {
+		t.Fatalf("The tags of the event should be kept on update, not %v", e.Tags)
+	}
+}
+
+func testDBReadingsByValueDescriptors(t *testing.T, db interfaces.DBClient) {
+	err := db.ScrubAllEvents()
+	if err != nil {
+		t.Fatalf("Error removing all events")
+	}
+	err = db.ScrubAllValueDescriptors()
+	if err != nil {
+		t.Fatalf("Error removing all value descriptors")
+	}
+
+	_, err = populateDbValues(db, 10)
+	if err != nil {
+		t.Fatalf("Error populating db: %v\n", err)
+	}
+	_, err = populateDbReadings(db, 10)
+	if err != nil {
+		t.Fatalf("Error populating db: %v\n", err)
+	}
+
+	readings, err := db.ReadingsByUomLabel("name1", 10)
+	if err != nil {
+		t.Fatalf("Error getting readings by UOM label: %v", err)
+	}
+	if len(readings) != 1 || readings[0].Name != "name1" {
+		t.Fatalf("There should be 1 reading named name1, not %v", readings)
+	}
+
+	readings, err = db.ReadingsByLabel("LABEL", 10)
+	if err != nil {
+		t.Fatalf("Error getting readings by label: %v", err)
+	}
+	if len(readings) != 10 {
+		t.Fatalf("There should be 10 readings, not %d", len(readings))
+	}
+	readings, err = db.ReadingsByLabel("LABEL", 3)
+	if err != nil {
+		t.Fatalf("Error getting readings by label: %v", err)
+	}
+	if len(readings) != 3 {
+		t.Fatalf("There should be 3 readings, not %d", len(readings))
+	}
+
+	readings, err = db.ReadingsByType("name2", 10)
+	if err != nil {
+		t.Fatalf("Error getting readings by type: %v", err)
+	}
+	if len(readings) != 1 || readings[0].Name != "name2" {
+		t.Fatalf("There should be 1 reading named name2, not %v", readings)
+	}
+	readings, err = db.ReadingsByType("unknown", 10)
+	if err != nil {
+		t.Fatalf("Error getting readings by type: %v", err)
+	}
+	if len(readings) != 0 {
+		t.Fatalf("There should be 0 readings, not %d", len(readings))
+	}
+
+	err = db.ScrubAllValueDescriptors()
+	if err != nil {
+		t.Fatalf("Error removing all value descriptors")
+	}
+}
+
+func testDBRetention(t *testing.T, db interfaces.DBClient) {
+	err := db.ScrubAllEvents()
+	if err != nil {
+		t.Fatalf("Error removing all events")
+	}
+
+	_, err = populateDbEvents(db, 10, 0)
+	if err != nil {
+		t.Fatalf("Error populating db: %v\n", err)
+	}
+	_, err = populateDbEvents(db, 10, 1)
+	if err != nil {
+		t.Fatalf("Error populating db: %v\n", err)
+	}
+
+	removed, err := db.DeletePushedEvents("name1")
+	if err != nil {
+		t.Fatalf("Error deleting pushed events: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("There should be 1 event removed, not %d", removed)
+	}
+	count, err := db.EventCountByDeviceId("name1")
+	if err != nil {
+		t.Fatalf("Error getting events count:  %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("There should be 1 events instead of %d", count)
+	}
+
+	removed, err = db.DeletePushedEvents("")
+	if err != nil {
+		t.Fatalf("Error deleting pushed events: %v", err)
+	}
+	if removed != 9 {
+		t.Fatalf("There should be 9 events removed, not %d", removed)
+	}
+
+	removed, err = db.DeleteEventsBeyondCount("", 4)
+	if err != nil {
+		t.Fatalf("Error deleting events beyond count: %v", err)
+	}
+	if removed != 6 {
+		t.Fatalf("There should be 6 events removed, not %d", removed)
+	}
+	count, err = db.EventCount()
+	if err != nil {
+		t.Fatalf("Error getting events count:  %v", err)
+	}
+	if count != 4 {
+		t.Fatalf("There should be 4 events instead of %d", count)
+	}
+
+	removed, err = db.DeleteEventsCreatedBefore("name", dbp.MakeTimestamp()+1)
+	if err != nil {
+		t.Fatalf("Error deleting events created before: %v", err)
+	}
+	if removed != 0 {
+		t.Fatalf("There should be 0 events removed, not %d", removed)
+	}
+
+	e := contract.Event{Device: "name", Readings: []contract.Reading{{Name: "name"}, {Name: "name"}}}
+	_, err = db.AddEvent(e)
+	if err != nil {
+		t.Fatalf("Error adding event: %v", err)
+	}
+	removed, err = db.DeleteEventsCreatedBefore("", dbp.MakeTimestamp()+1)
+	if err != nil {
+		t.Fatalf("Error deleting events created before: %v", err)
+	}
+	if removed != 5 {
+		t.Fatalf("There should be 5 events removed, not %d", removed)
+	}
+	count, err = db.ReadingCount()
+	if err != nil {
+		t.Fatalf("Error getting readings count:  %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("The readings of the events should be removed, %d left", count)
+	}
+
+	_, err = populateDbReadings(db, 10)
+	if err != nil {
+		t.Fatalf("Error populating db: %v\n", err)
+	}
+
+	removed, err = db.DeletePushedReadings("")
+	if err != nil {
+		t.Fatalf("Error deleting pushed readings: %v", err)
+	}
+	if removed != 0 {
+		t.Fatalf("There should be 0 readings removed, not %d", removed)
+	}
+
+	removed, err = db.DeleteReadingsBeyondCount("name1", 0)
+	if err != nil {
+		t.Fatalf("Error deleting readings beyond count: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("There should be 1 readings removed, not %d", removed)
+	}
+
+	removed, err = db.DeleteReadingsBeyondCount("", 5)
+	if err != nil {
+		t.Fatalf("Error deleting readings beyond count: %v", err)
+	}
+	if removed != 4 {
+		t.Fatalf("There should be 4 readings removed, not %d", removed)
+	}
+
+	removed, err = db.DeleteReadingsCreatedBefore("", dbp.MakeTimestamp()+1)
+	if err != nil {
+		t.Fatalf("Error deleting readings created before: %v", err)
+	}
+	if removed != 5 {
+		t.Fatalf("There should be 5 readings removed, not %d", removed)
+	}
+}
+
+func testDBReplaceReadings(t *testing.T, db interfaces.DBClient) {
+	err := db.ScrubAllEvents()
+	if err != nil {
+		t.Fatalf("Error removing all events")
+	}
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := db.AddReading(contract.Reading{Name: "name", Device: "device", Value: fmt.Sprintf("%d", i)})
+		if err != nil {
+			t.Fatalf("Error adding reading: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	summary := contract.Reading{Name: "name", Device: "device", Value: "0.5", Created: 1,
+		Summary: &contract.ReadingAggregate{Name: "name", Device: "device", Count: 2, Min: 0, Max: 1, Avg: 0.5}}
+	id, err := db.ReplaceReadings([]string{ids[0], ids[1], "ffffffff-ffff-ffff-ffff-ffffffffffff"}, summary)
+	if err != nil {
+		t.Fatalf("Error replacing readings: %v", err)
+	}
+
+	readings, err := db.ReadingsByValueDescriptor("name", 10)
+	if err != nil {
+		t.Fatalf("Error getting readings: %v", err)
+	}
+	if len(readings) != 2 {
+		t.Fatalf("There should be 2 readings instead of %d", len(readings))
+	}
+	r, err := db.ReadingById(id)
+	if err != nil {
+		t.Fatalf("Error getting the summary reading: %v", err)
+	}
+	if r.Summary == nil || r.Summary.Count != 2 || r.Created != 1 {
+		t.Fatalf("Unexpected summary reading %v", r)
+	}
+	if _, err = db.ReadingById(ids[2]); err != nil {
+		t.Fatalf("The reading not replaced should remain: %v", err)
+	}
+
+	// Events keep the readings that were not replaced
+	eventId, err := db.AddEvent(contract.Event{Device: "device", Readings: []contract.Reading{
+		{Name: "name", Device: "device", Value: "1"},
+		{Name: "other", Device: "device", Value: "2"},
+	}})
+	if err != nil {
+		t.Fatalf("Error adding event: %v", err)
+	}
+	e, err := db.EventById(eventId)
+	if err != nil || len(e.Readings) != 2 {
+		t.Fatalf("Error getting the event before replacing its reading: %v", err)
+	}
+	if _, err = db.ReplaceReadings([]string{e.Readings[0].Id}, summary); err != nil {
+		t.Fatalf("Error replacing readings: %v", err)
+	}
+	e, err = db.EventById(eventId)
+	if err != nil {
+		t.Fatalf("Error getting the event after replacing its reading: %v", err)
+	}
+	if len(e.Readings) != 1 || e.Readings[0].Name != "other" {
+		t.Fatalf("The event should keep its other reading instead of %v", e.Readings)
+	}
+	events, err := db.EventsForDevice("device")
+	if err != nil {
+		t.Fatalf("Error getting the events of the device: %v", err)
+	}
+	if len(events) != 1 || len(events[0].Readings) != 1 {
+		t.Fatalf("The events of the device should keep their other reading instead of %v", events)
+	}
+}
+
+func testDBReadingAggregates(t *testing.T, db interfaces.DBClient) {
+	err := db.ScrubAllEvents()
+	if err != nil {
+		t.Fatalf("Error removing all events")
+	}
+
+	// More readings than any read limit, so that the whole range must be aggregated by the database
+	for i := 0; i < 300; i++ {
+		device := "device1"
+		if i%3 == 0 {
+			device = "device2"
+		}
+		r := contract.Reading{Name: "aggregated", Device: device, Value: strconv.Itoa(i), Created: int64(1000 + i)}
+		if _, err = db.AddReading(r); err != nil {
+			t.Fatalf("Error adding reading: %v", err)
+		}
+	}
+	if _, err = db.AddReading(contract.Reading{Name: "other", Device: "device1", Value: "1000", Created: 1000}); err != nil {
+		t.Fatalf("Error adding reading: %v", err)
+	}
+
+	aggregates, err := db.ReadingAggregates("aggregated", "", 1000, 1300, 100)
+	if err != nil {
+		t.Fatalf("Error getting reading aggregates: %v", err)
+	}
+	if len(aggregates) != 6 {
+		t.Fatalf("There should be 6 aggregates instead of %d", len(aggregates))
+	}
+	count := 0
+	for _, a := range aggregates {
+		count += a.Count
+	}
+	if count != 300 {
+		t.Fatalf("The aggregates should count 300 readings instead of %d", count)
+	}
+
+	aggregates, err = db.ReadingAggregates("aggregated", "device1", 1000, 1100, 100)
+	if err != nil {
+		t.Fatalf("Error getting reading aggregates: %v", err)
+	}
+	if len(aggregates) != 1 {
+		t.Fatalf("There should be 1 aggregate instead of %d", len(aggregates))
+	}
+	a := aggregates[0]
+	if a.Device != "device1" || a.Count != 66 || a.Min != 1 || a.Max != 98 || a.First != 1 || a.Last != 98 {
+		t.Fatalf("Unexpected aggregate %v", a)
+	}
+
+	aggregates, err = db.ReadingAggregates("aggregated", "", 2000, 3000, 100)
+	if err != nil {
+		t.Fatalf("Error getting reading aggregates: %v", err)
+	}
+	if aggregates == nil || len(aggregates) != 0 {
+		t.Fatalf("There should be no aggregates instead of %v", aggregates)
+	}
+}
+
 func TestDataDB(t *testing.T, db interfaces.DBClient) {
 	testDBReadings(t, db)
 	testDBEvents(t, db)
+	testDBRetention(t, db)
+	testDBBulkEvents(t, db)
+	testDBEventTags(t, db)
+	testDBReadingsByValueDescriptors(t, db)
+	testDBReplaceReadings(t, db)
+	testDBReadingAggregates(t, db)
 	testDBValueDescriptors(t, db)
 
 	db.CloseSession()
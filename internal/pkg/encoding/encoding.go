@@ -0,0 +1,72 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package encoding encodes and decodes the events exchanged between the services, as JSON or CBOR.
+package encoding
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/ugorji/go/codec"
+)
+
+// CBOR keys structs by the names of their JSON fields, so both encodings hold the same documents
+var cborHandle = &codec.CborHandle{}
+
+// ContentType returns the content type of the value of a Content-Type header, JSON unless CBOR.
+func ContentType(header string) string {
+	mediaType, _, _ := mime.ParseMediaType(header)
+	if mediaType == clients.ContentCbor {
+		return clients.ContentCbor
+	}
+	return clients.ContentJson
+}
+
+// ContentTypeOf returns the content type of an encoded value, for transports without headers.
+// JSON documents start with a printable character, while CBOR maps and arrays start with their major type.
+func ContentTypeOf(data []byte) string {
+	if len(data) > 0 && data[0] >= 0x80 && data[0] <= 0xbf {
+		return clients.ContentCbor
+	}
+	return clients.ContentJson
+}
+
+// Marshal encodes the value in the content type.
+func Marshal(v interface{}, contentType string) ([]byte, error) {
+	if contentType == clients.ContentCbor {
+		var data []byte
+		err := codec.NewEncoderBytes(&data, cborHandle).Encode(v)
+		return data, err
+	}
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the data, encoded in the content type, into the value.
+func Unmarshal(data []byte, contentType string, v interface{}) error {
+	if contentType == clients.ContentCbor {
+		return codec.NewDecoderBytes(data, cborHandle).Decode(v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Decode decodes the value read from the reader, encoded in the content type.
+func Decode(r io.Reader, contentType string, v interface{}) error {
+	if contentType == clients.ContentCbor {
+		return codec.NewDecoder(r, cborHandle).Decode(v)
+	}
+	return json.NewDecoder(r).Decode(v)
+}
@@ -0,0 +1,126 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package encoding
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Event of a vibration sensor, with a spectrum of 4096 samples and a binary capture
+func newTestEvent() models.Event {
+	spectrum := make([]byte, 0, 4096*8)
+	for i := 0; i < 4096; i++ {
+		spectrum = append(spectrum, []byte("0.123456,")...)
+	}
+	capture := make([]byte, 64*1024)
+	for i := range capture {
+		capture[i] = byte(i)
+	}
+
+	return models.Event{
+		ID:     "5b9a2f8c-6d3e-4a1b-9c7d-2e8f4a6b1c3d",
+		Device: "Accelerometer",
+		Origin: 1536000000000,
+		Tags:   map[string]string{"site": "A"},
+		Readings: []models.Reading{
+			{Name: "Spectrum", Value: "[" + string(spectrum[:len(spectrum)-1]) + "]", Origin: 1536000000000},
+			{Name: "Capture", BinaryValue: capture, Origin: 1536000000000},
+		},
+	}
+}
+
+func TestContentType(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", clients.ContentJson},
+		{"application/json; charset=utf-8", clients.ContentJson},
+		{"application/cbor", clients.ContentCbor},
+		{"application/x-yaml", clients.ContentJson},
+	}
+	for _, tt := range tests {
+		if got := ContentType(tt.header); got != tt.expected {
+			t.Errorf("header %s: expected %s, got %s", tt.header, tt.expected, got)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	event := newTestEvent()
+
+	for _, contentType := range []string{clients.ContentJson, clients.ContentCbor} {
+		data, err := Marshal(event, contentType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ContentTypeOf(data) != contentType {
+			t.Errorf("expected %s to be told from its first byte", contentType)
+		}
+
+		var decoded models.Event
+		if err = Unmarshal(data, contentType, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, event) {
+			t.Errorf("%s: the decoded event differs from the encoded one", contentType)
+		}
+
+		decoded = models.Event{}
+		if err = Decode(bytes.NewReader(data), contentType, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Device != event.Device || len(decoded.Readings) != 2 {
+			t.Errorf("%s: unexpected decoded event %v", contentType, decoded)
+		}
+	}
+}
+
+func BenchmarkEncoding(b *testing.B) {
+	event := newTestEvent()
+
+	for name, contentType := range map[string]string{"JSON": clients.ContentJson, "CBOR": clients.ContentCbor} {
+		data, err := Marshal(event, contentType)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(name+"Marshal", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := Marshal(event, contentType)
+				if err != nil {
+					b.Fatalf("Error marshal: %v", err)
+				}
+			}
+			b.SetBytes(int64(len(data)))
+		})
+
+		b.Run(name+"Unmarshal", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var e models.Event
+				err := Unmarshal(data, contentType, &e)
+				if err != nil {
+					b.Fatalf("Error unmarshal: %v", err)
+				}
+			}
+			b.SetBytes(int64(len(data)))
+		})
+	}
+}
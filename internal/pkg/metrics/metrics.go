@@ -0,0 +1,331 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package metrics holds the metrics of a service and exposes them in the Prometheus text format.
+//
+// Metrics are created once, usually as package variables, and registered in DefaultRegistry.
+// Each service serves the registry on clients.ApiPrometheusRoute and counts the HTTP requests
+// it handles with Middleware.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	counterType   = "counter"
+	gaugeType     = "gauge"
+	histogramType = "histogram"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of the latency histograms
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Sample is the value of a metric for a set of label values, in the order of the labels of the metric.
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// A metric family, written with its help and type
+type collector interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds the metrics of a service.
+type Registry struct {
+	mutex      sync.Mutex
+	collectors map[string]collector
+}
+
+// DefaultRegistry holds the metrics of the service, along with the metrics of the Go runtime.
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	DefaultRegistry.Register(runtimeCollector{})
+}
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// Register adds the metric to the registry. Metric names are unique within a registry.
+func (r *Registry) Register(c collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.collectors[c.name()]; ok {
+		panic(fmt.Sprintf("metric %s registered twice", c.name()))
+	}
+	r.collectors[c.name()] = c
+}
+
+// Write writes the metrics of the registry, sorted by name, in the Prometheus text format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mutex.Lock()
+	collectors := make([]collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mutex.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Name, help and labels of a metric
+type desc struct {
+	metricName string
+	help       string
+	kind       string
+	labels     []string
+}
+
+func (d desc) name() string {
+	return d.metricName
+}
+
+func (d desc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.metricName, strings.NewReplacer("\\", `\\`, "\n", `\n`).Replace(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.metricName, d.kind)
+}
+
+// Write a sample line, with the extra label appended to the labels of the metric when given
+func (d desc) writeSample(w *bufio.Writer, suffix string, labelValues []string, extraLabel string, extraValue string, value float64) {
+	w.WriteString(d.metricName + suffix)
+	if len(d.labels) > 0 || extraLabel != "" {
+		w.WriteByte('{')
+		for i, label := range d.labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, label, labelValues[i])
+		}
+		if extraLabel != "" {
+			if len(d.labels) > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, extraLabel, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+var labelValueReplacer = strings.NewReplacer("\\", `\\`, "\"", `\"`, "\n", `\n`)
+
+func writeLabel(w *bufio.Writer, label string, value string) {
+	w.WriteString(label + `="` + labelValueReplacer.Replace(value) + `"`)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Key of a set of label values, which cannot hold the separator
+func labelKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+func (d desc) checkLabelValues(labelValues []string) {
+	if len(labelValues) != len(d.labels) {
+		panic(fmt.Sprintf("metric %s has %d labels, got %d values", d.metricName, len(d.labels), len(labelValues)))
+	}
+}
+
+// Counter is a metric that only goes up, such as a number of requests.
+type Counter struct {
+	desc
+	mutex  sync.Mutex
+	values map[string]*Sample
+}
+
+// NewCounter creates a counter with the labels and registers it in DefaultRegistry.
+func NewCounter(name string, help string, labels ...string) *Counter {
+	c := &Counter{desc: desc{metricName: name, help: help, kind: counterType, labels: labels}, values: make(map[string]*Sample)}
+	DefaultRegistry.Register(c)
+	return c
+}
+
+// Inc adds one to the counter of the label values.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds the value, which must not be negative, to the counter of the label values.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.checkLabelValues(labelValues)
+	key := labelKey(labelValues)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, ok := c.values[key]
+	if !ok {
+		s = &Sample{LabelValues: append([]string(nil), labelValues...)}
+		c.values[key] = s
+	}
+	s.Value += v
+}
+
+// Value returns the counter of the label values.
+func (c *Counter) Value(labelValues ...string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if s, ok := c.values[labelKey(labelValues)]; ok {
+		return s.Value
+	}
+	return 0
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.writeHeader(w)
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := c.values[key]
+		c.writeSample(w, "", s.LabelValues, "", "", s.Value)
+	}
+}
+
+// Histogram counts observations, such as latencies, in buckets.
+type Histogram struct {
+	desc
+	buckets []float64
+	mutex   sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labelValues []string
+	counts      []uint64 // Observations in each bucket, not cumulated
+	count       uint64
+	sum         float64
+}
+
+// NewHistogram creates a histogram with the bucket upper bounds and labels, and registers it in DefaultRegistry.
+func NewHistogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		desc:    desc{metricName: name, help: help, kind: histogramType, labels: labels},
+		buckets: append([]float64(nil), buckets...),
+		values:  make(map[string]*histogramValue),
+	}
+	sort.Float64s(h.buckets)
+	DefaultRegistry.Register(h)
+	return h
+}
+
+// Observe adds the value to the histogram of the label values.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.checkLabelValues(labelValues)
+	key := labelKey(labelValues)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	hv, ok := h.values[key]
+	if !ok {
+		hv = &histogramValue{labelValues: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hv
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		hv.counts[i]++
+	}
+	hv.count++
+	hv.sum += v
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.writeHeader(w)
+	keys := make([]string, 0, len(h.values))
+	for key := range h.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		hv := h.values[key]
+		var cumulated uint64
+		for i, bound := range h.buckets {
+			cumulated += hv.counts[i]
+			h.writeSample(w, "_bucket", hv.labelValues, "le", formatFloat(bound), float64(cumulated))
+		}
+		h.writeSample(w, "_bucket", hv.labelValues, "le", "+Inf", float64(hv.count))
+		h.writeSample(w, "_sum", hv.labelValues, "", "", hv.sum)
+		h.writeSample(w, "_count", hv.labelValues, "", "", float64(hv.count))
+	}
+}
+
+// Metric whose samples are read from the service when the metrics are written
+type funcCollector struct {
+	desc
+	samples func() []Sample
+}
+
+// NewGaugeFunc registers in DefaultRegistry a gauge whose value is read from the function.
+func NewGaugeFunc(name string, help string, value func() float64) {
+	DefaultRegistry.Register(funcCollector{
+		desc:    desc{metricName: name, help: help, kind: gaugeType},
+		samples: func() []Sample { return []Sample{{Value: value()}} },
+	})
+}
+
+// NewCounterFunc registers in DefaultRegistry a counter whose samples, one per set of label values,
+// are read from the function. It exposes the counters a service already keeps.
+func NewCounterFunc(name string, help string, labels []string, samples func() []Sample) {
+	DefaultRegistry.Register(funcCollector{
+		desc:    desc{metricName: name, help: help, kind: counterType, labels: labels},
+		samples: samples,
+	})
+}
+
+func (f funcCollector) write(w *bufio.Writer) {
+	samples := f.samples()
+	sort.Slice(samples, func(i, j int) bool {
+		return labelKey(samples[i].LabelValues) < labelKey(samples[j].LabelValues)
+	})
+
+	f.writeHeader(w)
+	for _, s := range samples {
+		f.writeSample(w, "", s.LabelValues, "", "", s.Value)
+	}
+}
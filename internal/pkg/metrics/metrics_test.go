@@ -0,0 +1,109 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestWrite(t *testing.T) {
+	c := NewCounter("test_sends_total", "Sends by \"outcome\".", "registration", "outcome")
+	c.Inc("b", "sent")
+	c.Add(2, "a", "sent")
+	c.Inc("a\"1", "failed")
+
+	h := NewHistogram("test_latency_seconds", "Latencies.", []float64{1, 0.1})
+	h.Observe(0.05)
+	h.Observe(0.5)
+	h.Observe(3)
+
+	NewGaugeFunc("test_buffered", "Buffered events.", func() float64 { return 7 })
+	NewCounterFunc("test_throttled_total", "Throttled events.", []string{"device"}, func() []Sample {
+		return []Sample{{LabelValues: []string{"z"}, Value: 1}, {LabelValues: []string{"y"}, Value: 2}}
+	})
+
+	var buf bytes.Buffer
+	if err := DefaultRegistry.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	expected := []string{`# HELP test_buffered Buffered events.
+# TYPE test_buffered gauge
+test_buffered 7
+`, `# HELP test_latency_seconds Latencies.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 1
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 3.55
+test_latency_seconds_count 3
+`, `# HELP test_sends_total Sends by "outcome".
+# TYPE test_sends_total counter
+test_sends_total{registration="a\"1",outcome="failed"} 1
+test_sends_total{registration="a",outcome="sent"} 2
+test_sends_total{registration="b",outcome="sent"} 1
+`, `# TYPE test_throttled_total counter
+test_throttled_total{device="y"} 2
+test_throttled_total{device="z"} 1
+`, "go_goroutines "}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("expected the metrics to hold:\n%s\ngot:\n%s", e, out)
+		}
+	}
+	if c.Value("a", "sent") != 2 {
+		t.Errorf("expected a counter of 2, got %g", c.Value("a", "sent"))
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	r := mux.NewRouter()
+	r.Use(Middleware)
+	r.HandleFunc("/api/v1/ping", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("pong"))
+	}).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/event/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}).Methods(http.MethodGet)
+	r.HandleFunc("/metrics", Handler).Methods(http.MethodGet)
+
+	for _, path := range []string{"/api/v1/ping", "/api/v1/event/1", "/api/v1/event/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if v := httpRequests.Value("/api/v1/ping", http.MethodGet, "200"); v != 1 {
+		t.Errorf("expected 1 request to ping, got %g", v)
+	}
+	// Requests are counted by route, not by path
+	if v := httpRequests.Value("/api/v1/event/{id}", http.MethodGet, "404"); v != 2 {
+		t.Errorf("expected 2 requests for events, got %g", v)
+	}
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rr.Header().Get("Content-Type") != ContentType {
+		t.Errorf("unexpected content type %s", rr.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rr.Body.String(), `edgex_http_request_duration_seconds_count{route="/api/v1/event/{id}",method="GET"} 2`) {
+		t.Errorf("expected the latencies of the event requests, got:\n%s", rr.Body.String())
+	}
+}
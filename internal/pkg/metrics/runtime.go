@@ -0,0 +1,50 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"bufio"
+	"runtime"
+)
+
+// The memory statistics also reported by the metrics handlers of the services, read once per write
+type runtimeCollector struct{}
+
+func (runtimeCollector) name() string {
+	return "go_"
+}
+
+func (runtimeCollector) write(w *bufio.Writer) {
+	var rtm runtime.MemStats
+	runtime.ReadMemStats(&rtm)
+
+	metrics := []struct {
+		desc
+		value float64
+	}{
+		{desc{"go_goroutines", "Number of goroutines that currently exist.", gaugeType, nil}, float64(runtime.NumGoroutine())},
+		{desc{"go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", gaugeType, nil}, float64(rtm.Alloc)},
+		{desc{"go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed.", counterType, nil}, float64(rtm.TotalAlloc)},
+		{desc{"go_memstats_sys_bytes", "Number of bytes obtained from system.", gaugeType, nil}, float64(rtm.Sys)},
+		{desc{"go_memstats_mallocs_total", "Total number of mallocs.", counterType, nil}, float64(rtm.Mallocs)},
+		{desc{"go_memstats_frees_total", "Total number of frees.", counterType, nil}, float64(rtm.Frees)},
+		{desc{"go_memstats_live_objects", "Number of live objects, mallocs less frees.", gaugeType, nil}, float64(rtm.Mallocs - rtm.Frees)},
+		{desc{"go_gc_runs_total", "Number of completed GC cycles.", counterType, nil}, float64(rtm.NumGC)},
+	}
+	for _, m := range metrics {
+		m.writeHeader(w)
+		m.writeSample(w, "", nil, "", "", m.value)
+	}
+}
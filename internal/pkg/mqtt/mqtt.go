@@ -0,0 +1,84 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package mqtt holds what the services exchanging events through an MQTT broker have in common.
+package mqtt
+
+import (
+	"strings"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+)
+
+const (
+	// Time allowed to connect to the broker
+	ConnectTimeout = 10 * time.Second
+	// Longest delay between two attempts to connect to the broker
+	MaxReconnectInterval = time.Minute
+	// Time allowed for the broker to acknowledge an event
+	PublishTimeout = 10 * time.Second
+)
+
+// Device names may hold the characters MQTT reserves for topic levels and wildcards
+var topicReplacer = strings.NewReplacer("/", "_", "+", "_", "#", "_")
+
+// NewClientOptions creates the options of a client connecting to the broker of the message queue.
+// Once connected, the client reconnects on its own when the connection is lost.
+func NewClientOptions(queue config.MessageQueueInfo) *MQTT.ClientOptions {
+	opts := MQTT.NewClientOptions()
+	opts.AddBroker(queue.Uri())
+	opts.SetClientID(queue.ClientId)
+	opts.SetUsername(queue.Username)
+	opts.SetPassword(queue.Password)
+	opts.SetConnectTimeout(ConnectTimeout)
+	opts.SetAutoReconnect(true)
+	opts.SetMaxReconnectInterval(MaxReconnectInterval)
+	return opts
+}
+
+// Connect the client to the broker, retrying with a growing delay until it succeeds.
+// Every failed attempt is reported to onError.
+func Connect(client MQTT.Client, onError func(err error)) {
+	delay := time.Second
+	for {
+		token := client.Connect()
+		if token.Wait() && token.Error() == nil {
+			return
+		}
+		onError(token.Error())
+
+		time.Sleep(delay)
+		if delay *= 2; delay > MaxReconnectInterval {
+			delay = MaxReconnectInterval
+		}
+	}
+}
+
+// EventTopic returns the topic the events of the device are published to
+func EventTopic(queue config.MessageQueueInfo, device string) string {
+	if !queue.TopicPerDevice {
+		return queue.Topic
+	}
+	return queue.Topic + "/" + topicReplacer.Replace(device)
+}
+
+// SubscriptionTopic returns the topic filter matching the events of every device
+func SubscriptionTopic(queue config.MessageQueueInfo) string {
+	if !queue.TopicPerDevice {
+		return queue.Topic
+	}
+	return queue.Topic + "/+"
+}
@@ -0,0 +1,46 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+)
+
+func TestTopics(t *testing.T) {
+	tests := []struct {
+		name         string
+		perDevice    bool
+		device       string
+		topic        string
+		subscription string
+	}{
+		{"Single topic", false, "Thermostat", "events", "events"},
+		{"Topic per device", true, "Thermostat", "events/Thermostat", "events/+"},
+		{"Reserved characters", true, "floor/1+#", "events/floor_1__", "events/+"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := config.MessageQueueInfo{Topic: "events", TopicPerDevice: tt.perDevice}
+			if topic := EventTopic(queue, tt.device); topic != tt.topic {
+				t.Errorf("expected topic %s, got %s", tt.topic, topic)
+			}
+			if topic := SubscriptionTopic(queue); topic != tt.subscription {
+				t.Errorf("expected subscription %s, got %s", tt.subscription, topic)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package test provides an in-process MQTT broker for the tests of the services using MQTT.
+// It supports what the services rely on: connecting, subscribing with wildcards and publishing.
+// Messages are always delivered to subscribers with QoS 0 and nothing is retained.
+package test
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+)
+
+type Broker struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	clients  map[*client]bool
+}
+
+type client struct {
+	conn    net.Conn
+	mutex   sync.Mutex
+	filters []string
+}
+
+// NewBroker starts a broker listening on a local port
+func NewBroker() (*Broker, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Broker{listener: listener, clients: make(map[*client]bool)}
+	go b.accept()
+	return b, nil
+}
+
+// MessageQueue returns the settings to connect to the broker
+func (b *Broker) MessageQueue() config.MessageQueueInfo {
+	addr := b.listener.Addr().(*net.TCPAddr)
+	return config.MessageQueueInfo{
+		Protocol: "tcp",
+		Host:     addr.IP.String(),
+		Port:     addr.Port,
+		Type:     config.MQTT,
+	}
+}
+
+// Disconnect drops the connection of every client, as a broker restart would
+func (b *Broker) Disconnect() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for c := range b.clients {
+		c.conn.Close()
+		delete(b.clients, c)
+	}
+}
+
+// Close stops the broker
+func (b *Broker) Close() {
+	b.listener.Close()
+	b.Disconnect()
+}
+
+func (b *Broker) accept() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &client{conn: conn}
+		b.mutex.Lock()
+		b.clients[c] = true
+		b.mutex.Unlock()
+		go b.serve(c)
+	}
+}
+
+func (b *Broker) serve(c *client) {
+	defer func() {
+		b.mutex.Lock()
+		delete(b.clients, c)
+		b.mutex.Unlock()
+		c.conn.Close()
+	}()
+
+	for {
+		cp, err := packets.ReadPacket(c.conn)
+		if err != nil {
+			return
+		}
+
+		switch p := cp.(type) {
+		case *packets.ConnectPacket:
+			err = c.write(packets.NewControlPacket(packets.Connack))
+		case *packets.SubscribePacket:
+			b.mutex.Lock()
+			c.filters = append(c.filters, p.Topics...)
+			b.mutex.Unlock()
+			ack := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+			ack.MessageID = p.MessageID
+			ack.ReturnCodes = make([]byte, len(p.Topics))
+			err = c.write(ack)
+		case *packets.PublishPacket:
+			b.publish(p)
+			switch p.Qos {
+			case 1:
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				err = c.write(ack)
+			case 2:
+				rec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				rec.MessageID = p.MessageID
+				err = c.write(rec)
+			}
+		case *packets.PubrelPacket:
+			comp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			comp.MessageID = p.MessageID
+			err = c.write(comp)
+		case *packets.PingreqPacket:
+			err = c.write(packets.NewControlPacket(packets.Pingresp))
+		case *packets.DisconnectPacket:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Forward the message to the clients subscribed to its topic
+func (b *Broker) publish(p *packets.PublishPacket) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for c := range b.clients {
+		for _, filter := range c.filters {
+			if matches(filter, p.TopicName) {
+				msg := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+				msg.TopicName = p.TopicName
+				msg.Payload = p.Payload
+				c.write(msg)
+				break
+			}
+		}
+	}
+}
+
+func (c *client) write(p packets.ControlPacket) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return p.Write(c.conn)
+}
+
+// Whether the topic matches the filter, which may use the + and # wildcards
+func matches(filter string, topic string) bool {
+	f := strings.Split(filter, "/")
+	t := strings.Split(topic, "/")
+	for i, level := range f {
+		if level == "#" {
+			return true
+		}
+		if i >= len(t) || (level != "+" && level != t[i]) {
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
@@ -24,9 +24,13 @@ import (
 	"time"
 
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
+var transmissions = metrics.NewCounter("edgex_notifications_transmissions_total",
+	"Notifications transmitted, by channel type, status and whether the transmission is a resend.", "channel", "status", "resend")
+
 func sendViaChannel(n models.Notification, c models.Channel, receiver string) {
 	LoggingClient.Debug("Sending notification: " + n.Slug + ", via channel: " + c.String())
 	var tr models.TransmissionRecord
@@ -35,6 +39,7 @@ func sendViaChannel(n models.Notification, c models.Channel, receiver string) {
 	} else {
 		tr = restSend(n.Content, c.Url)
 	}
+	transmissions.Inc(string(c.Type), string(tr.Status), "false")
 	t, err := persistTransmission(tr, n, c, receiver)
 	if err == nil {
 		handleFailedTransmission(t)
@@ -48,6 +53,7 @@ func resendViaChannel(t models.Transmission) {
 	} else {
 		tr = restSend(t.Notification.Content, t.Channel.Url)
 	}
+	transmissions.Inc(string(t.Channel.Type), string(tr.Status), "true")
 	t.ResendCount = t.ResendCount + 1
 	t.Status = tr.Status
 	t.Records = append(t.Records, tr)
@@ -17,7 +17,6 @@ import (
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
 	"github.com/edgexfoundry/edgex-go/internal/support/scheduler/errors"
 	contract "github.com/edgexfoundry/edgex-go/pkg/models"
-	"github.com/robfig/cron"
 )
 
 func getIntervals(limit int) ([]contract.Interval, error) {
@@ -67,6 +66,13 @@ func addNewInterval(interval contract.Interval) (string, error) {
 			return "", errors.NewErrInvalidFrequencyFormat(freq)
 		}
 	}
+	// Validate the Cron expression
+	cronSpec := interval.Cron
+	if cronSpec != "" {
+		if !isCronValid(cronSpec) {
+			return "", errors.NewErrInvalidCronFormat(cronSpec)
+		}
+	}
 
 	// Validate that interval is not in queue
 	ret,err = scClient.QueryIntervalByName(name)
@@ -101,7 +107,7 @@ func updateInterval(from contract.Interval) error {
 	}
 	// Update the fields
 	if from.Cron != "" {
-		if _, err := cron.Parse(from.Cron); err != nil {
+		if !isCronValid(from.Cron) {
 			return errors.NewErrInvalidCronFormat(from.Cron)
 		}
 		to.Cron = from.Cron
@@ -17,6 +17,7 @@ package scheduler
 import (
 	"encoding/json"
 	"github.com/edgexfoundry/edgex-go/internal"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
 	"github.com/edgexfoundry/edgex-go/internal/support/scheduler/errors"
 	"github.com/edgexfoundry/edgex-go/pkg/clients"
 
@@ -31,6 +32,7 @@ import (
 
 func LoadRestRoutes() *mux.Router {
 	r := mux.NewRouter()
+	r.Use(metrics.Middleware)
 
 	// Ping Resource
 	r.HandleFunc(clients.ApiPingRoute, pingHandler).Methods(http.MethodGet)
@@ -40,6 +42,7 @@ func LoadRestRoutes() *mux.Router {
 
 	// Metrics
 	r.HandleFunc(clients.ApiMetricsRoute, metricsHandler).Methods(http.MethodGet)
+	r.HandleFunc(clients.ApiPrometheusRoute, metrics.Handler).Methods(http.MethodGet)
 
 	// Interval
 	r.HandleFunc(clients.ApiIntervalRoute, intervalHandler).Methods(http.MethodGet, http.MethodPut, http.MethodPost)
@@ -145,6 +148,8 @@ func intervalHandler(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, t.Error(), http.StatusBadRequest)
 			case *errors.ErrInvalidTimeFormat:
 				http.Error(w, t.Error(), http.StatusBadRequest)
+			case *errors.ErrInvalidCronFormat:
+				http.Error(w, t.Error(), http.StatusBadRequest)
 			case *errors.ErrInvalidFrequencyFormat:
 				http.Error(w, t.Error(), http.StatusBadRequest)
 			default:
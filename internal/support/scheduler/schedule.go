@@ -9,6 +9,7 @@ package scheduler
 import (
 	"errors"
 	"fmt"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
 	contract "github.com/edgexfoundry/edgex-go/pkg/models"
 	queueV1 "gopkg.in/eapache/queue.v1"
 	"io/ioutil"
@@ -30,6 +31,9 @@ var (
 	intervalActionNameToIntervalActionIdMap = make(map[string]string)           // map : interval action name -> interval action id
 )
 
+var executions = metrics.NewCounter("edgex_scheduler_executions_total",
+	"Interval actions executed, by interval, interval action and outcome: succeeded or failed.", "interval", "action", "outcome")
+
 func StartTicker() {
 	go func() {
 		for range ticker.C {
@@ -454,6 +458,11 @@ func execute(context *IntervalContext, wg *sync.WaitGroup) error {
 		}
 		responseBytes, statusCode, err := sendRequestAndGetResponse(client, req)
 		responseStr := string(responseBytes)
+		if err != nil || statusCode >= http.StatusBadRequest {
+			executions.Inc(context.Interval.Name, intervalAction.Name, "failed")
+		} else {
+			executions.Inc(context.Interval.Name, intervalAction.Name, "succeeded")
+		}
 
 		LoggingClient.Debug(fmt.Sprintf("execution returns status code : %d", statusCode))
 		LoggingClient.Debug("execution returns response content : " + responseStr)
@@ -9,6 +9,7 @@ package scheduler
 
 import (
 	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/robfig/cron"
 
 	"regexp"
 	"strconv"
@@ -22,6 +23,8 @@ type IntervalContext struct {
 	EndTime           time.Time
 	NextTime          time.Time
 	Frequency         time.Duration
+	Schedule          cron.Schedule  // parsed cron expression, nil when the interval runs on its frequency
+	Location          *time.Location // time zone the cron expression is evaluated in
 	CurrentIterations int64
 	MaxIterations     int64
 	MarkedDeleted     bool
@@ -67,10 +70,32 @@ func (sc *IntervalContext) Reset(interval models.Interval) {
 		sc.EndTime = t
 	}
 
-	//frequency and next time
+	//frequency, cron and next time
 	nowBenchmark := time.Now().Unix()
 	sc.Frequency = parseFrequency(sc.Interval.Frequency)
 
+	sc.Schedule = nil
+	sc.Location = time.Local
+	if sc.Interval.Cron != "" {
+		schedule, location, err := parseCron(sc.Interval.Cron)
+		if err != nil {
+			LoggingClient.Error("parse cron error, falling back to frequency, the original cron string is : " + sc.Interval.Cron)
+		} else {
+			sc.Schedule = schedule
+			sc.Location = location
+		}
+	}
+
+	if sc.Schedule != nil {
+		//first activation at or after the start time, skipping the ones already missed
+		from := sc.StartTime.Add(-time.Second)
+		if from.Unix() < nowBenchmark && !sc.Interval.RunOnce {
+			from = time.Unix(nowBenchmark, 0)
+		}
+		sc.NextTime = sc.nextCronTime(from)
+		return
+	}
+
 	sc.NextTime = sc.StartTime
 	if sc.StartTime.Unix() <= nowBenchmark && !sc.Interval.RunOnce {
 		for sc.NextTime.Unix() <= nowBenchmark {
@@ -91,7 +116,16 @@ func (sc *IntervalContext) UpdateIterations() {
 
 func (sc *IntervalContext) UpdateNextTime() {
 	if !sc.IsComplete() {
-		sc.NextTime = sc.NextTime.Add(sc.Frequency)
+		if sc.Schedule != nil {
+			//do not replay activations missed while the previous execution was running
+			from := sc.NextTime
+			if now := time.Now(); from.Before(now) {
+				from = now
+			}
+			sc.NextTime = sc.nextCronTime(from)
+		} else {
+			sc.NextTime = sc.NextTime.Add(sc.Frequency)
+		}
 	}
 }
 
@@ -102,10 +136,17 @@ func (sc *IntervalContext) GetInfo() string {
 func (sc *IntervalContext) isComplete(time time.Time) bool {
 	complete := (sc.StartTime.Unix() < time.Unix() && sc.Interval.RunOnce) ||
 		(sc.NextTime.Unix() > sc.EndTime.Unix()) ||
+		(sc.Schedule != nil && sc.NextTime.IsZero()) ||
 		((sc.MaxIterations != 0) && (sc.CurrentIterations >= sc.MaxIterations))
 	return complete
 }
 
+// nextCronTime returns the first activation of the cron schedule strictly after the given time,
+// or the zero time when the schedule will never fire again.
+func (sc *IntervalContext) nextCronTime(from time.Time) time.Time {
+	return sc.Schedule.Next(from.In(sc.Location))
+}
+
 func parseFrequency(durationStr string) time.Duration {
 	durationRegex := regexp.MustCompile(`P(?P<years>\d+Y)?(?P<months>\d+M)?(?P<days>\d+D)?T?(?P<hours>\d+H)?(?P<minutes>\d+M)?(?P<seconds>\d+S)?`)
 	matches := durationRegex.FindStringSubmatch(durationStr)
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
@@ -49,6 +50,7 @@ const (
 )
 
 func TestRet(t *testing.T) {
+	LoggingClient = logger.NewMockClient()
 	testInterval := models.Interval{
 		Name:      TestIntervalName,
 		Start:     TestIntervalStart,
@@ -155,6 +157,7 @@ func TestRet(t *testing.T) {
 }
 
 func TestIsComplete(t *testing.T) {
+	LoggingClient = logger.NewMockClient()
 	testInterval := models.Interval{
 		Name:      TestIntervalName,
 		Start:     TestIntervalStart,
@@ -205,3 +208,82 @@ func TestParseFrequency(t *testing.T) {
 		t.Errorf(TestUnexpectedMsgFormatStrForFloatVal, duration.Seconds(), 0.0)
 	}
 }
+
+func TestCronNextTime(t *testing.T) {
+	LoggingClient = logger.NewMockClient()
+	testInterval := models.Interval{
+		Name:      TestIntervalName,
+		Start:     TestIntervalStart,
+		End:       TestIntervalEnd,
+		Frequency: TestIntervalFrequency,
+		Cron:      "CRON_TZ=UTC 0 */15 6-18 * * MON-FRI",
+	}
+
+	testIntervalContext := IntervalContext{}
+	testIntervalContext.Reset(testInterval)
+
+	if testIntervalContext.Schedule == nil {
+		t.Fatal(TestUnexpectedMsg)
+	}
+
+	next := testIntervalContext.NextTime.UTC()
+	if !next.After(time.Now()) {
+		t.Errorf(TestUnexpectedMsgFormatStr, next, "a time in the future")
+	}
+	if next.Second() != 0 || next.Minute()%15 != 0 || next.Hour() < 6 || next.Hour() > 18 {
+		t.Errorf(TestUnexpectedMsgFormatStr, next, "a quarter hour between 06:00 and 18:45")
+	}
+	if next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+		t.Errorf(TestUnexpectedMsgFormatStr, next.Weekday(), "a week day")
+	}
+
+	//seconds field and time zone selection
+	testInterval.Cron = "TZ=Asia/Tokyo 30 0 9 * * *"
+	testInterval.Start = ""
+	testIntervalContext.Reset(testInterval)
+
+	next = testIntervalContext.NextTime.In(testIntervalContext.Location)
+	if next.Hour() != 9 || next.Minute() != 0 || next.Second() != 30 {
+		t.Errorf(TestUnexpectedMsgFormatStr, next, "09:00:30 Asia/Tokyo")
+	}
+	if next.UTC().Hour() != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, next.UTC().Hour(), 0)
+	}
+
+	previous := testIntervalContext.NextTime
+	testIntervalContext.UpdateNextTime()
+	if testIntervalContext.NextTime.Sub(previous) != 24*time.Hour {
+		t.Errorf(TestUnexpectedMsgFormatStr, testIntervalContext.NextTime.Sub(previous), 24*time.Hour)
+	}
+
+	//an invalid expression falls back to the frequency
+	testInterval.Cron = TestIntervalCron
+	testIntervalContext.Reset(testInterval)
+
+	if testIntervalContext.Schedule != nil {
+		t.Error(TestUnexpectedMsg)
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		valid bool
+	}{
+		{"seconds", "0 */15 6-18 * * MON-FRI", true},
+		{"descriptor", "@hourly", true},
+		{"time zone", "CRON_TZ=America/New_York 0 0 12 * * *", true},
+		{"short time zone prefix", "TZ=UTC 0 0 12 * * *", true},
+		{"unknown time zone", "CRON_TZ=Nowhere/Special 0 0 12 * * *", false},
+		{"time zone only", "CRON_TZ=UTC", false},
+		{"free text", TestIntervalCron, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if valid := isCronValid(tt.spec); valid != tt.valid {
+				t.Errorf(TestUnexpectedMsgFormatStrForBoolVal, valid, tt.valid)
+			}
+		})
+	}
+}
@@ -14,15 +14,23 @@
 package scheduler
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/robfig/cron"
 )
 
 const (
 	frequencyPattern = `^P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+S)?)?$`
 )
 
+// Prefixes which may precede a cron expression to select the time zone it is evaluated in,
+// e.g. "CRON_TZ=Europe/Berlin 0 */15 6-18 * * MON-FRI"
+var cronTimeZonePrefixes = []string{"CRON_TZ=", "TZ="}
+
 func isFrequencyValid(frequency string) bool {
 	matched, _ := regexp.MatchString(frequencyPattern, frequency)
 	if matched {
@@ -33,6 +41,40 @@ func isFrequencyValid(frequency string) bool {
 	return matched
 }
 
+// Parse a cron expression with a leading seconds field and an optional time zone prefix.
+// Expressions without a time zone are evaluated in the local time zone of the scheduler.
+func parseCron(spec string) (cron.Schedule, *time.Location, error) {
+	spec = strings.TrimSpace(spec)
+	location := time.Local
+	for _, prefix := range cronTimeZonePrefixes {
+		if !strings.HasPrefix(spec, prefix) {
+			continue
+		}
+		i := strings.IndexAny(spec, " \t")
+		if i == -1 {
+			return nil, nil, fmt.Errorf("missing cron expression after time zone: %s", spec)
+		}
+		loc, err := time.LoadLocation(spec[len(prefix):i])
+		if err != nil {
+			return nil, nil, err
+		}
+		location = loc
+		spec = strings.TrimSpace(spec[i:])
+		break
+	}
+
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schedule, location, nil
+}
+
+func isCronValid(spec string) bool {
+	_, _, err := parseCron(spec)
+	return err == nil
+}
+
 // Convert millisecond string to Time
 func msToTime(ms string) (time.Time, error) {
 	msInt, err := strconv.ParseInt(ms, 10, 64)
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/edgexfoundry/edgex-go/internal/pkg/metrics"
 	"github.com/edgexfoundry/edgex-go/internal/system/agent/logger"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"github.com/gorilla/mux"
@@ -28,11 +29,13 @@ import (
 
 func LoadRestRoutes() *mux.Router {
 	r := mux.NewRouter()
+	r.Use(metrics.Middleware)
 	b := r.PathPrefix("/api/v1").Subrouter()
 
 	b.HandleFunc("/operation", operationHandler).Methods(http.MethodPost)
 	b.HandleFunc("/config/{services}", configHandler).Methods(http.MethodGet)
 	b.HandleFunc("/metrics/{services}", metricsHandler).Methods(http.MethodGet)
+	b.HandleFunc("/prometheus", metrics.Handler).Methods(http.MethodGet)
 
 	// Ping Resource
 	// /api/v1/ping
@@ -18,22 +18,27 @@ const ClientMonitorDefault = 15000
 const (
 	ApiBase                    = "/api/v1"
 	ApiAddressableRoute        = "/api/v1/addressable"
+	ApiBufferRoute             = "/api/v1/buffer"
 	ApiCallbackRoute           = "/api/v1/callback"
 	ApiCommandRoute            = "/api/v1/command"
 	ApiConfigRoute             = "/api/v1/config"
 	ApiDeviceRoute             = "/api/v1/device"
 	ApiDeviceProfileRoute      = "/api/v1/deviceprofile"
 	ApiDeviceServiceRoute      = "/api/v1/deviceservice"
+	ApiDownsamplingRoute       = "/api/v1/downsampling"
 	ApiEventRoute              = "/api/v1/event"
 	ApiLoggingRoute            = "/api/v1/logs"
 	ApiMetricsRoute            = "/api/v1/metrics"
 	ApiNotificationRoute       = "/api/v1/notification"
 	ApiNotifyRegistrationRoute = "/api/v1/notify/registrations"
 	ApiPingRoute               = "/api/v1/ping"
+	ApiPrometheusRoute         = "/api/v1/prometheus"
 	ApiProvisionWatcherRoute   = "/api/v1/provisionwatcher"
+	ApiRateLimitRoute          = "/api/v1/ratelimit"
 	ApiReadingRoute            = "/api/v1/reading"
 	ApiRegistrationRoute       = "/api/v1/registration"
 	ApiRegistrationByNameRoute = ApiRegistrationRoute + "/name"
+	ApiRetentionRoute          = "/api/v1/retention"
 	ApiScheduleRoute           = "/api/v1/schedule"
 	ApiScheduleEventRoute      = "/api/v1/scheduleevent"
 	ApiSubscriptionRoute       = "/api/v1/subscription"
@@ -32,7 +32,9 @@ type EventClient interface {
 	EventCountForDevice(deviceId string) (int, error)
 	EventsForDevice(id string, limit int) ([]models.Event, error)
 	EventsForInterval(start int, end int, limit int) ([]models.Event, error)
+	EventsPage(start int64, end int64, cursor string, limit int) (models.EventPage, error)
 	EventsForDeviceAndValueDescriptor(deviceId string, vd string, limit int) ([]models.Event, error)
+	EventsForTag(key string, value string, limit int) ([]models.Event, error)
 	Add(event *models.Event) (string, error)
 	DeleteForDevice(id string) error
 	DeleteOld(age int) error
@@ -122,11 +124,33 @@ func (e *EventRestClient) EventsForInterval(start int, end int, limit int) ([]mo
 	return e.requestEventSlice(e.url + "/" + strconv.Itoa(start) + "/" + strconv.Itoa(end) + "/" + strconv.Itoa(limit))
 }
 
+// Get a page of events for interval, starting after the cursor (from the start when empty)
+func (e *EventRestClient) EventsPage(start int64, end int64, cursor string, limit int) (models.EventPage, error) {
+	path := e.url + "/page/" + strconv.FormatInt(start, 10) + "/" + strconv.FormatInt(end, 10) + "/" + strconv.Itoa(limit)
+	if cursor != "" {
+		path += "?cursor=" + url.QueryEscape(cursor)
+	}
+
+	page := models.EventPage{}
+	data, err := clients.GetRequest(path)
+	if err != nil {
+		return page, err
+	}
+
+	err = json.Unmarshal(data, &page)
+	return page, err
+}
+
 // Get events for device and value descriptor
 func (e *EventRestClient) EventsForDeviceAndValueDescriptor(deviceId string, vd string, limit int) ([]models.Event, error) {
 	return e.requestEventSlice(e.url + "/device/" + url.QueryEscape(deviceId) + "/valuedescriptor/" + url.QueryEscape(vd) + "/" + strconv.Itoa(limit))
 }
 
+// Get events holding the tag with the value
+func (e *EventRestClient) EventsForTag(key string, value string, limit int) ([]models.Event, error) {
+	return e.requestEventSlice(e.url + "/tag/" + url.QueryEscape(key) + "/" + url.QueryEscape(value) + "/" + strconv.Itoa(limit))
+}
+
 // Add event
 func (e *EventRestClient) Add(event *models.Event) (string, error) {
 	return clients.PostJsonRequest(e.url, event)
@@ -124,6 +124,39 @@ func TestGetEvents(t *testing.T) {
 	}
 }
 
+func TestEventsForTag(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+
+		expected := clients.ApiEventRoute + "/tag/site+code/A/10"
+		if r.URL.EscapedPath() != expected {
+			t.Errorf("expected uri path is %s, actual uri path is %s", expected, r.URL.EscapedPath())
+		}
+
+		w.Write([]byte("[{\"Device\" : \"" + TestEventDevice1 + "\", \"tags\" : {\"site code\" : \"A\"}}]"))
+	}))
+
+	defer ts.Close()
+
+	params := types.EndpointParams{
+		ServiceKey:  internal.CoreDataServiceKey,
+		Path:        clients.ApiEventRoute,
+		UseRegistry: false,
+		Url:         ts.URL + clients.ApiEventRoute,
+		Interval:    clients.ClientMonitorDefault}
+
+	ec := NewEventClient(params, mockEventEndpoint{})
+
+	eArr, err := ec.EventsForTag("site code", "A", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(eArr) != 1 || eArr[0].Tags["site code"] != "A" {
+		t.Errorf("unexpected events %v", eArr)
+	}
+}
+
 func TestNewEventClientWithConsul(t *testing.T) {
 	deviceUrl := "http://localhost:48080" + clients.ApiEventRoute
 	params := types.EndpointParams{
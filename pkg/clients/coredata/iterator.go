@@ -0,0 +1,136 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ *******************************************************************************/
+package coredata
+
+import (
+	"errors"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Walks every event created in an interval one page at a time, following the cursors returned by core-data.
+//
+//	it := NewEventIterator(client, start, end, "", 500)
+//	for it.Next() {
+//		for _, e := range it.Events() { ... }
+//	}
+//	if it.Err() != nil { ... }
+type EventIterator struct {
+	client   EventClient
+	start    int64
+	end      int64
+	pageSize int
+	cursor   string
+	done     bool
+	events   []models.Event
+	err      error
+}
+
+// An empty cursor starts from the beginning of the interval, otherwise the walk resumes after it
+func NewEventIterator(client EventClient, start int64, end int64, cursor string, pageSize int) *EventIterator {
+	return &EventIterator{client: client, start: start, end: end, cursor: cursor, pageSize: pageSize}
+}
+
+// Fetch the next page, returning false once every page has been read or a request failed
+func (it *EventIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	if it.pageSize <= 0 {
+		it.err = errors.New("page size must be positive")
+		return false
+	}
+
+	page, err := it.client.EventsPage(it.start, it.end, it.cursor, it.pageSize)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.events = page.Events
+	it.cursor = page.Next
+	it.done = page.Next == ""
+	return len(page.Events) > 0 || !it.done
+}
+
+// The events of the current page
+func (it *EventIterator) Events() []models.Event {
+	return it.events
+}
+
+// The cursor of the page after the current one, which can be kept to resume the walk later
+func (it *EventIterator) Cursor() string {
+	return it.cursor
+}
+
+// The error that stopped the walk, if any
+func (it *EventIterator) Err() error {
+	return it.err
+}
+
+// Walks every reading created in an interval one page at a time, following the cursors returned by core-data.
+type ReadingIterator struct {
+	client   ReadingClient
+	start    int64
+	end      int64
+	pageSize int
+	cursor   string
+	done     bool
+	readings []models.Reading
+	err      error
+}
+
+// An empty cursor starts from the beginning of the interval, otherwise the walk resumes after it
+func NewReadingIterator(client ReadingClient, start int64, end int64, cursor string, pageSize int) *ReadingIterator {
+	return &ReadingIterator{client: client, start: start, end: end, cursor: cursor, pageSize: pageSize}
+}
+
+// Fetch the next page, returning false once every page has been read or a request failed
+func (it *ReadingIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	if it.pageSize <= 0 {
+		it.err = errors.New("page size must be positive")
+		return false
+	}
+
+	page, err := it.client.ReadingsPage(it.start, it.end, it.cursor, it.pageSize)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.readings = page.Readings
+	it.cursor = page.Next
+	it.done = page.Next == ""
+	return len(page.Readings) > 0 || !it.done
+}
+
+// The readings of the current page
+func (it *ReadingIterator) Readings() []models.Reading {
+	return it.readings
+}
+
+// The cursor of the page after the current one, which can be kept to resume the walk later
+func (it *ReadingIterator) Cursor() string {
+	return it.cursor
+}
+
+// The error that stopped the walk, if any
+func (it *ReadingIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,117 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ *******************************************************************************/
+package coredata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestEventIterator(t *testing.T) {
+	expectedPath := clients.ApiEventRoute + "/page/100/200/2"
+	pages := map[string]models.EventPage{
+		"":      {Events: []models.Event{{ID: "1"}, {ID: "2"}}, Next: "page2"},
+		"page2": {Events: []models.Event{{ID: "3"}}},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != expectedPath {
+			t.Errorf("expected uri path is %s, actual uri path is %s", expectedPath, r.URL.EscapedPath())
+		}
+
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(page)
+	}))
+
+	defer ts.Close()
+
+	params := types.EndpointParams{
+		ServiceKey:  internal.CoreDataServiceKey,
+		Path:        clients.ApiEventRoute,
+		UseRegistry: false,
+		Url:         ts.URL + clients.ApiEventRoute,
+		Interval:    clients.ClientMonitorDefault}
+
+	ec := NewEventClient(params, mockEventEndpoint{})
+
+	var ids []string
+	it := NewEventIterator(ec, 100, 200, "", 2)
+	for it.Next() {
+		for _, e := range it.Events() {
+			ids = append(ids, e.ID)
+		}
+	}
+	if it.Err() != nil {
+		t.Fatalf("unexpected error %s", it.Err().Error())
+	}
+	if len(ids) != 3 || ids[0] != "1" || ids[2] != "3" {
+		t.Errorf("unexpected events walked %v", ids)
+	}
+
+	it = NewEventIterator(ec, 100, 200, "unknown", 2)
+	if it.Next() {
+		t.Errorf("expected the walk to stop on an invalid cursor")
+	}
+	if it.Err() == nil {
+		t.Errorf("expected an error for an invalid cursor")
+	}
+}
+
+func TestReadingIterator(t *testing.T) {
+	pages := map[string]models.ReadingPage{
+		"":      {Readings: []models.Reading{{Id: "1"}}, Next: "page2"},
+		"page2": {Readings: []models.Reading{{Id: "2"}}, Next: "page3"},
+		"page3": {Readings: []models.Reading{}},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+	}))
+
+	defer ts.Close()
+
+	params := types.EndpointParams{
+		ServiceKey:  internal.CoreDataServiceKey,
+		Path:        clients.ApiReadingRoute,
+		UseRegistry: false,
+		Url:         ts.URL + clients.ApiReadingRoute,
+		Interval:    clients.ClientMonitorDefault}
+
+	rc := NewReadingClient(params, mockReadingEndpoint{})
+
+	count := 0
+	it := NewReadingIterator(rc, 0, 10, "", 1)
+	for it.Next() {
+		count += len(it.Readings())
+	}
+	if it.Err() != nil {
+		t.Fatalf("unexpected error %s", it.Err().Error())
+	}
+	if count != 2 {
+		t.Errorf("expected 2 readings, walked %d", count)
+	}
+}
@@ -36,6 +36,8 @@ type ReadingClient interface {
 	ReadingsForLabel(label string, limit int) ([]models.Reading, error)
 	ReadingsForType(readingType string, limit int) ([]models.Reading, error)
 	ReadingsForInterval(start int, end int, limit int) ([]models.Reading, error)
+	ReadingsPage(start int64, end int64, cursor string, limit int) (models.ReadingPage, error)
+	ReadingAggregates(name string, deviceId string, start int64, end int64, interval int64) ([]models.ReadingAggregate, error)
 	Add(readiing *models.Reading) (string, error)
 	Delete(id string) error
 }
@@ -142,11 +144,47 @@ func (r *ReadingRestClient) ReadingsForInterval(start int, end int, limit int) (
 	return r.requestReadingSlice(r.url + "/" + strconv.Itoa(start) + "/" + strconv.Itoa(end) + "/" + strconv.Itoa(limit))
 }
 
+// Get a page of readings for interval, starting after the cursor (from the start when empty)
+func (r *ReadingRestClient) ReadingsPage(start int64, end int64, cursor string, limit int) (models.ReadingPage, error) {
+	path := r.url + "/page/" + strconv.FormatInt(start, 10) + "/" + strconv.FormatInt(end, 10) + "/" + strconv.Itoa(limit)
+	if cursor != "" {
+		path += "?cursor=" + url.QueryEscape(cursor)
+	}
+
+	page := models.ReadingPage{}
+	data, err := clients.GetRequest(path)
+	if err != nil {
+		return page, err
+	}
+
+	err = json.Unmarshal(data, &page)
+	return page, err
+}
+
 // Get readings for device and value descriptor
 func (r *ReadingRestClient) ReadingsForDeviceAndValueDescriptor(deviceId string, vd string, limit int) ([]models.Reading, error) {
 	return r.requestReadingSlice(r.url + "/device/" + url.QueryEscape(deviceId) + "/valuedescriptor/" + url.QueryEscape(vd) + "/" + strconv.Itoa(limit))
 }
 
+// Get min/max/avg/count/first/last of the readings for a value descriptor, grouped into buckets of interval
+// milliseconds between start and end. An empty deviceId aggregates the readings of every device.
+func (r *ReadingRestClient) ReadingAggregates(name string, deviceId string, start int64, end int64, interval int64) ([]models.ReadingAggregate, error) {
+	path := r.url + "/aggregate/name/" + url.QueryEscape(name)
+	if deviceId != "" {
+		path += "/device/" + url.QueryEscape(deviceId)
+	}
+	path += "/" + strconv.FormatInt(start, 10) + "/" + strconv.FormatInt(end, 10) + "/" + strconv.FormatInt(interval, 10)
+
+	data, err := clients.GetRequest(path)
+	if err != nil {
+		return []models.ReadingAggregate{}, err
+	}
+
+	aggregates := make([]models.ReadingAggregate, 0)
+	err = json.Unmarshal(data, &aggregates)
+	return aggregates, err
+}
+
 // Add a reading
 func (r *ReadingRestClient) Add(reading *models.Reading) (string, error) {
 	return clients.PostJsonRequest(r.url, reading)
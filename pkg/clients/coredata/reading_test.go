@@ -88,6 +88,50 @@ func TestGetReadings(t *testing.T) {
 	}
 }
 
+func TestGetReadingAggregates(t *testing.T) {
+	expectedPath := clients.ApiReadingRoute + "/aggregate/name/Temperature/device/" + TestReadingDevice1 + "/1000/2000/100"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+
+		if r.Method != http.MethodGet {
+			t.Errorf("expected http method is GET, active http method is : %s", r.Method)
+		}
+
+		if r.URL.EscapedPath() != expectedPath {
+			t.Errorf("expected uri path is %s, actual uri path is %s", expectedPath, r.URL.EscapedPath())
+		}
+
+		w.Write([]byte("[" +
+			"{\"name\":\"Temperature\",\"device\":\"" + TestReadingDevice1 + "\",\"start\":1000,\"end\":1100,\"count\":2,\"min\":1,\"max\":3,\"avg\":2,\"first\":1,\"last\":3}" +
+			"]"))
+	}))
+
+	defer ts.Close()
+
+	url := ts.URL + clients.ApiReadingRoute
+
+	params := types.EndpointParams{
+		ServiceKey:  internal.CoreDataServiceKey,
+		Path:        clients.ApiReadingRoute,
+		UseRegistry: false,
+		Url:         url,
+		Interval:    clients.ClientMonitorDefault}
+
+	rc := NewReadingClient(params, mockReadingEndpoint{})
+
+	aggregates, err := rc.ReadingAggregates("Temperature", TestReadingDevice1, 1000, 2000, 100)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(aggregates) != 1 {
+		t.Fatalf("expected aggregate array's length is 1, actual array's length is : %d", len(aggregates))
+	}
+	if aggregates[0].Count != 2 || aggregates[0].Avg != 2 {
+		t.Errorf("unexpected aggregate %s", aggregates[0].String())
+	}
+}
+
 func TestNewReadingClientWithConsul(t *testing.T) {
 	deviceUrl := "http://localhost:48080" + clients.ApiReadingRoute
 	params := types.EndpointParams{
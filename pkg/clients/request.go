@@ -29,9 +29,14 @@ import (
 )
 
 const (
-	ContentType = "Content-Type"
-	ContentJson = "application/json"
-	ContentYaml = "application/x-yaml"
+	ContentType   = "Content-Type"
+	ContentJson   = "application/json"
+	ContentYaml   = "application/x-yaml"
+	ContentNdjson = "application/x-ndjson"
+	ContentCbor   = "application/cbor"
+
+	// Header identifying the submissions of a same event, so that retries are not added twice
+	IdempotencyKey = "Idempotency-Key"
 )
 
 // Helper method to get the body from the response after making the request
@@ -25,28 +25,30 @@ import (
  * Event struct to hold event data
  */
 type Event struct {
-	ID       string    `json:"id"`
-	Pushed   int64     `json:"pushed"`
-	Device   string    `json:"device"` // Device identifier (name or id)
-	Created  int64     `json:"created"`
-	Modified int64     `json:"modified"`
-	Origin   int64     `json:"origin"`
-	Event    string    `json:"event"`    // Schedule event identifier
-	Readings []Reading `json:"readings"` // List of readings
+	ID       string            `json:"id"`
+	Pushed   int64             `json:"pushed"`
+	Device   string            `json:"device"` // Device identifier (name or id)
+	Created  int64             `json:"created"`
+	Modified int64             `json:"modified"`
+	Origin   int64             `json:"origin"`
+	Event    string            `json:"event"`        // Schedule event identifier
+	Readings []Reading         `json:"readings"`     // List of readings
+	Tags     map[string]string `json:"tags" xml:"-"` // Metadata attached to the event, such as batch IDs or site codes
 }
 
 // Custom marshaling to make empty strings null
 func (e Event) MarshalJSON() ([]byte, error) {
 	test := struct {
-		ID       *string   `json:"id,omitempty"`
-		Pushed   int64     `json:"pushed,omitempty"`
-		Device   *string   `json:"device,omitempty"` // Device identifier (name or id)
-		Created  int64     `json:"created,omitempty"`
-		Modified int64     `json:"modified,omitempty"`
-		Origin   int64     `json:"origin,omitempty"`
-		Schedule *string   `json:"schedule,omitempty"` // Schedule identifier
-		Event    *string   `json:"event,omitempty"`    // Schedule event identifier
-		Readings []Reading `json:"readings,omitempty"` // List of readings
+		ID       *string           `json:"id,omitempty"`
+		Pushed   int64             `json:"pushed,omitempty"`
+		Device   *string           `json:"device,omitempty"` // Device identifier (name or id)
+		Created  int64             `json:"created,omitempty"`
+		Modified int64             `json:"modified,omitempty"`
+		Origin   int64             `json:"origin,omitempty"`
+		Schedule *string           `json:"schedule,omitempty"` // Schedule identifier
+		Event    *string           `json:"event,omitempty"`    // Schedule event identifier
+		Readings []Reading         `json:"readings,omitempty"` // List of readings
+		Tags     map[string]string `json:"tags,omitempty"`     // Metadata attached to the event
 	}{
 		Pushed:   e.Pushed,
 		Created:  e.Created,
@@ -69,6 +71,9 @@ func (e Event) MarshalJSON() ([]byte, error) {
 	if len(e.Readings) > 0 {
 		test.Readings = e.Readings
 	}
+	if len(e.Tags) > 0 {
+		test.Tags = e.Tags
+	}
 
 	return json.Marshal(test)
 }
@@ -15,6 +15,7 @@
 package models
 
 import (
+	"encoding/json"
 	"reflect"
 	"strconv"
 	"testing"
@@ -50,6 +51,27 @@ func TestEvent_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestEvent_MarshalJSONTags(t *testing.T) {
+	e := Event{Device: "Device", Tags: map[string]string{"batch": "42"}}
+
+	got, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"device":"Device","tags":{"batch":"42"}}`
+	if string(got) != want {
+		t.Errorf("Event.MarshalJSON() = %s, want %s", got, want)
+	}
+
+	var out Event
+	if err = json.Unmarshal(got, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out.Tags, e.Tags) {
+		t.Errorf("Event tags = %v, want %v", out.Tags, e.Tags)
+	}
+}
+
 func TestEvent_String(t *testing.T) {
 	tests := []struct {
 		name string
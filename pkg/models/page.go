@@ -0,0 +1,33 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package models
+
+/*
+ * One page of a listing of events, in creation order
+ * Next is the cursor of the following page, empty on the last page
+ */
+type EventPage struct {
+	Events []Event `json:"events"`
+	Next   string  `json:"next,omitempty"`
+}
+
+/*
+ * One page of a listing of readings, in creation order
+ * Next is the cursor of the following page, empty on the last page
+ */
+type ReadingPage struct {
+	Readings []Reading `json:"readings"`
+	Next     string    `json:"next,omitempty"`
+}
@@ -34,24 +34,36 @@ type Reading struct {
 	Device   string `json:"device"`
 	Name     string `json:"name"`
 	Value    string `json:"value"` // Device sensor data value
+	// Raw data of binary readings, such as images, carried as bytes in CBOR rather than base64 in Value
+	BinaryValue []byte `json:"binaryValue,omitempty"`
+	// Set when the value fails the assertion of its device resource
+	AssertionFailed bool `json:"assertionFailed"`
+	// Set on the readings summarizing the older readings they replaced, the value being their average
+	Summary *ReadingAggregate `json:"summary,omitempty"`
 }
 
 // Custom marshaling to make empty strings null
 func (r Reading) MarshalJSON() ([]byte, error) {
 	test := struct {
-		Id       *string `json:"id,omitempty"`
-		Pushed   int64   `json:"pushed,omitempty"`  // When the data was pushed out of EdgeX (0 - not pushed yet)
-		Created  int64   `json:"created,omitempty"` // When the reading was created
-		Origin   int64   `json:"origin,omitempty"`
-		Modified int64   `json:"modified,omitempty"`
-		Device   *string `json:"device,omitempty"`
-		Name     *string `json:"name,omitempty"`
-		Value    *string `json:"value,omitempty"` // Device sensor data value
+		Id              *string           `json:"id,omitempty"`
+		Pushed          int64             `json:"pushed,omitempty"`  // When the data was pushed out of EdgeX (0 - not pushed yet)
+		Created         int64             `json:"created,omitempty"` // When the reading was created
+		Origin          int64             `json:"origin,omitempty"`
+		Modified        int64             `json:"modified,omitempty"`
+		Device          *string           `json:"device,omitempty"`
+		Name            *string           `json:"name,omitempty"`
+		Value           *string           `json:"value,omitempty"` // Device sensor data value
+		BinaryValue     []byte            `json:"binaryValue,omitempty"`
+		AssertionFailed bool              `json:"assertionFailed,omitempty"`
+		Summary         *ReadingAggregate `json:"summary,omitempty"`
 	}{
-		Pushed:   r.Pushed,
-		Created:  r.Created,
-		Origin:   r.Origin,
-		Modified: r.Modified,
+		Pushed:          r.Pushed,
+		Created:         r.Created,
+		Origin:          r.Origin,
+		Modified:        r.Modified,
+		BinaryValue:     r.BinaryValue,
+		AssertionFailed: r.AssertionFailed,
+		Summary:         r.Summary,
 	}
 
 	// Empty strings are null
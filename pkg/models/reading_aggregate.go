@@ -0,0 +1,47 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package models
+
+import (
+	"encoding/json"
+)
+
+/*
+ * Summary of the numeric readings of one value descriptor from one device
+ * created within a time bucket [Start, End)
+ */
+type ReadingAggregate struct {
+	Name   string  `json:"name"`   // Value descriptor name
+	Device string  `json:"device"` // Device name
+	Start  int64   `json:"start"`  // Start of the bucket (inclusive)
+	End    int64   `json:"end"`    // End of the bucket (exclusive)
+	Count  int     `json:"count"`  // Number of numeric readings in the bucket
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Avg    float64 `json:"avg"`
+	First  float64 `json:"first"` // Value of the earliest reading in the bucket
+	Last   float64 `json:"last"`  // Value of the latest reading in the bucket
+}
+
+/*
+ * To String function for ReadingAggregate Struct
+ */
+func (a ReadingAggregate) String() string {
+	out, err := json.Marshal(a)
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
@@ -0,0 +1,41 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package models
+
+import (
+	"testing"
+)
+
+var TestReadingAggregate = ReadingAggregate{Name: TestValueDescriptorName, Device: TestDeviceName, Start: 100, End: 200, Count: 2, Min: 1, Max: 3, Avg: 2, First: 3, Last: 1}
+
+func TestReadingAggregate_String(t *testing.T) {
+	tests := []struct {
+		name string
+		a    ReadingAggregate
+		want string
+	}{
+		{"reading aggregate to string", TestReadingAggregate,
+			"{\"name\":\"" + TestValueDescriptorName + "\"" +
+				",\"device\":\"" + TestDeviceName + "\"" +
+				",\"start\":100,\"end\":200,\"count\":2,\"min\":1,\"max\":3,\"avg\":2,\"first\":3,\"last\":1}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.String(); got != tt.want {
+				t.Errorf("ReadingAggregate.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
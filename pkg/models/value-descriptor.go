@@ -18,6 +18,27 @@ import (
 	"encoding/json"
 )
 
+// Types of the values described by value descriptors
+const (
+	ValueTypeBool        = "B"   // Boolean
+	ValueTypeFloat       = "F"   // 64 bit floating point
+	ValueTypeInteger     = "I"   // 64 bit signed integer
+	ValueTypeInteger8    = "I8"  // 8 bit signed integer
+	ValueTypeInteger16   = "I16" // 16 bit signed integer
+	ValueTypeInteger32   = "I32" // 32 bit signed integer
+	ValueTypeInteger64   = "I64" // 64 bit signed integer
+	ValueTypeUnsigned    = "U"   // 64 bit unsigned integer
+	ValueTypeUnsigned8   = "U8"  // 8 bit unsigned integer
+	ValueTypeUnsigned16  = "U16" // 16 bit unsigned integer
+	ValueTypeUnsigned32  = "U32" // 32 bit unsigned integer
+	ValueTypeUnsigned64  = "U64" // 64 bit unsigned integer
+	ValueTypeString      = "S"   // String or character data
+	ValueTypeJSON        = "J"   // JSON data
+	ValueTypeBinary      = "BIN" // Binary data, base64 encoded
+	ValueTypeArray       = "A"   // JSON array of numbers
+	ValueTypeEnumeration = "E"   // One of the allowed values of the value descriptor
+)
+
 /*
  * Value Descriptor Struct
  */
@@ -35,29 +56,33 @@ type ValueDescriptor struct {
 	UomLabel     string      `json:"uomLabel"`
 	Formatting   string      `json:"formatting"`
 	Labels       []string    `json:"labels"`
+	// Values allowed for an enumeration
+	AllowedValues []string `json:"allowedValues"`
 }
 
 // Custom marshaling to make empty strings null
 func (v ValueDescriptor) MarshalJSON() ([]byte, error) {
 	test := struct {
-		Id           *string      `json:"id,omitempty"`
-		Created      int64        `json:"created,omitempty"`
-		Description  *string      `json:"description,omitempty"`
-		Modified     int64        `json:"modified,omitempty"`
-		Origin       int64        `json:"origin,omitempty"`
-		Name         *string      `json:"name,omitempty"`
-		Min          *interface{} `json:"min,omitempty"`
-		Max          *interface{} `json:"max,omitempty"`
-		DefaultValue *interface{} `json:"defaultValue,omitempty"`
-		Type         *string      `json:"type,omitempty"`
-		UomLabel     *string      `json:"uomLabel,omitempty"`
-		Formatting   *string      `json:"formatting,omitempty"`
-		Labels       []string     `json:"labels,omitempty"`
+		Id            *string      `json:"id,omitempty"`
+		Created       int64        `json:"created,omitempty"`
+		Description   *string      `json:"description,omitempty"`
+		Modified      int64        `json:"modified,omitempty"`
+		Origin        int64        `json:"origin,omitempty"`
+		Name          *string      `json:"name,omitempty"`
+		Min           *interface{} `json:"min,omitempty"`
+		Max           *interface{} `json:"max,omitempty"`
+		DefaultValue  *interface{} `json:"defaultValue,omitempty"`
+		Type          *string      `json:"type,omitempty"`
+		UomLabel      *string      `json:"uomLabel,omitempty"`
+		Formatting    *string      `json:"formatting,omitempty"`
+		Labels        []string     `json:"labels,omitempty"`
+		AllowedValues []string     `json:"allowedValues,omitempty"`
 	}{
-		Created:  v.Created,
-		Modified: v.Modified,
-		Origin:   v.Origin,
-		Labels:   v.Labels,
+		Created:       v.Created,
+		Modified:      v.Modified,
+		Origin:        v.Origin,
+		Labels:        v.Labels,
+		AllowedValues: v.AllowedValues,
 	}
 
 	// Empty strings are null